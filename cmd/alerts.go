@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/alert"
+	"github.com/caner-cetin/halycon/internal/config"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type inventoryAlertsConfig struct {
+	DryRun bool
+	Reset  bool
+}
+
+var (
+	inventoryAlertsCmd = &cobra.Command{
+		Use:   "alerts",
+		Short: "evaluates alert rules against the latest inventory snapshot (also runs after every inventory build)",
+		Run:   WrapCommandWithResources(inventoryAlerts, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+	inventoryAlertsCfg inventoryAlertsConfig
+)
+
+func getInventoryAlertsCmd() *cobra.Command {
+	inventoryAlertsCmd.PersistentFlags().BoolVar(&inventoryAlertsCfg.DryRun, "dry-run", false, "print alerts without notifying or updating deduplication state")
+	inventoryAlertsCmd.PersistentFlags().BoolVar(&inventoryAlertsCfg.Reset, "reset", false, "clear deduplication state so that active alerts fire again")
+	return inventoryAlertsCmd
+}
+
+func inventoryAlerts(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	if inventoryAlertsCfg.Reset {
+		if err := app.Query.ClearInventoryAlertStates(app.Ctx); err != nil {
+			log.Error().Err(err).Msg("failed to clear alert state")
+			return
+		}
+		color.Green("cleared alert state")
+	}
+	if len(cfg.Amazon.FBA.Alerts.Rules) == 0 {
+		color.Magenta("no alert rules configured, see amazon.fba.alerts in config")
+		return
+	}
+	if err := evaluateInventoryAlerts(app, inventoryAlertsCfg.DryRun); err != nil {
+		log.Error().Err(err).Msg("failed to evaluate inventory alerts")
+		return
+	}
+}
+
+// evaluateInventoryAlerts checks the configured rules against the latest inventory snapshot and notifies
+// alerts that have not fired before. Alerts stay in the state table until their condition clears,
+// so the same alert is not sent after every build.
+func evaluateInventoryAlerts(app AppCtx, dryRun bool) error {
+	rules := cfg.Amazon.FBA.Alerts.Rules
+	if len(rules) == 0 {
+		return nil
+	}
+	for _, rule := range rules {
+		if err := alert.ValidateRule(rule); err != nil {
+			return err
+		}
+	}
+	notifiers, err := getAlertNotifiers()
+	if err != nil {
+		return err
+	}
+	// alerts of a rule targeting an unknown notifier would be recorded as fired without being sent
+	for _, rule := range rules {
+		for _, target := range rule.Notifiers {
+			if !slices.ContainsFunc(notifiers, func(n alert.Notifier) bool { return n.Name() == target }) {
+				return fmt.Errorf("alert rule %s references unknown notifier %s", rule.Name, target)
+			}
+		}
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -cfg.Amazon.FBA.Restock.LookbackDays)
+	snapshots, err := app.Query.GetFBAInventorySnapshotsSince(app.Ctx, since)
+	if err != nil {
+		return fmt.Errorf("failed to query inventory snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		log.Warn().Msg("no inventory snapshots to evaluate alerts against")
+		return nil
+	}
+
+	latest, previous, previousAt := latestInventorySnapshots(snapshots)
+	recommendations := make(map[string]RestockRecommendation)
+	for _, recommendation := range computeRestockRecommendations(snapshots, cfg.Amazon.FBA.Restock.LeadTimeDays, cfg.Amazon.FBA.Restock.TargetCoverDays) {
+		recommendations[recommendation.SKU] = recommendation
+	}
+
+	firing := make(map[string]map[string]alert.Alert, len(rules))
+	for _, rule := range rules {
+		firing[rule.Name] = evaluateAlertRule(rule, latest, previous, recommendations, previousAt)
+	}
+
+	states, err := app.Query.GetInventoryAlertStates(app.Ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query alert state: %w", err)
+	}
+	names := make([]string, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		names = append(names, notifier.Name())
+	}
+	toNotify, resolved := pendingInventoryAlerts(rules, names, firing, latest, states)
+
+	if dryRun {
+		pending := 0
+		for _, notifier := range notifiers {
+			for _, a := range toNotify[notifier.Name()] {
+				fmt.Printf("%s %s %s\n", color.YellowString("[%s]", a.Rule), color.CyanString("-> %s", notifier.Name()), a.Message)
+				pending++
+			}
+		}
+		log.Info().Int("pending", pending).Int("resolved", len(resolved)).Msg("dry run, no notifications sent")
+		return nil
+	}
+
+	// alerts are recorded per notifier that delivered them, a failed notifier is retried on the next
+	// evaluation without sending the alert to the others again
+	var delivered []db.InsertInventoryAlertStateParams
+	sent, failed := 0, 0
+	for _, notifier := range notifiers {
+		alerts := toNotify[notifier.Name()]
+		if len(alerts) == 0 {
+			continue
+		}
+		if err := notifier.Notify(app.Ctx, alerts); err != nil {
+			log.Error().Err(err).Str("notifier", notifier.Name()).Msg("failed to send alerts")
+			failed += len(alerts)
+			continue
+		}
+		sent += len(alerts)
+		for _, a := range alerts {
+			delivered = append(delivered, db.InsertInventoryAlertStateParams{Rule: a.Rule, Sku: a.SKU, Notifier: notifier.Name(), FiredAt: a.FiredAt, Value: a.Value})
+		}
+	}
+
+	tx, err := app.DB.BeginTx(app.Ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer internal.Rollback(tx)
+	query := app.Query.WithTx(tx)
+	for _, params := range resolved {
+		if err := query.DeleteInventoryAlertState(app.Ctx, params); err != nil {
+			return fmt.Errorf("failed to resolve alert %s for %s: %w", params.Rule, params.Sku, err)
+		}
+	}
+	for _, params := range delivered {
+		if err := query.InsertInventoryAlertState(app.Ctx, params); err != nil {
+			return fmt.Errorf("failed to record alert %s for %s: %w", params.Rule, params.Sku, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit alert state: %w", err)
+	}
+	log.Info().Int("sent", sent).Int("failed", failed).Int("resolved", len(resolved)).Msg("evaluated inventory alerts")
+	return nil
+}
+
+// latestInventorySnapshots returns the snapshots of the latest and the one before it keyed by SKU,
+// and the time of the previous snapshot, which is zero if there is only one.
+func latestInventorySnapshots(snapshots []db.FbaInventorySnapshot) (latest map[string]db.FbaInventorySnapshot, previous map[string]db.FbaInventorySnapshot, previousAt time.Time) {
+	var latestAt time.Time
+	for _, snapshot := range snapshots {
+		if snapshot.SnapshotAt.After(latestAt) {
+			latestAt = snapshot.SnapshotAt
+		}
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.SnapshotAt.Before(latestAt) && snapshot.SnapshotAt.After(previousAt) {
+			previousAt = snapshot.SnapshotAt
+		}
+	}
+	latest = make(map[string]db.FbaInventorySnapshot)
+	previous = make(map[string]db.FbaInventorySnapshot)
+	for _, snapshot := range snapshots {
+		switch {
+		case snapshot.SnapshotAt.Equal(latestAt):
+			latest[snapshot.Sku] = snapshot
+		case !previousAt.IsZero() && snapshot.SnapshotAt.Equal(previousAt):
+			previous[snapshot.Sku] = snapshot
+		}
+	}
+	return latest, previous, previousAt
+}
+
+// pendingInventoryAlerts compares the firing alerts of each rule with the recorded state. It returns the
+// alerts each notifier has not delivered yet keyed by notifier name, and the recorded alerts whose
+// condition cleared. Rules without notifiers are sent to all of them.
+func pendingInventoryAlerts(rules []config.AlertRuleConfig, notifiers []string, firing map[string]map[string]alert.Alert, latest map[string]db.FbaInventorySnapshot, states []db.InventoryAlertState) (map[string][]alert.Alert, []db.DeleteInventoryAlertStateParams) {
+	type delivery struct{ rule, sku, notifier string }
+	delivered := make(map[delivery]bool, len(states))
+	resolving := make(map[db.DeleteInventoryAlertStateParams]bool)
+	var resolved []db.DeleteInventoryAlertStateParams
+	for _, state := range states {
+		delivered[delivery{state.Rule, state.Sku, state.Notifier}] = true
+		alerts, ruleExists := firing[state.Rule]
+		_, stillFiring := alerts[state.Sku]
+		ruleIdx := slices.IndexFunc(rules, func(r config.AlertRuleConfig) bool { return r.Name == state.Rule })
+		if ruleIdx != -1 && rules[ruleIdx].Type == alert.RuleSKUMissing {
+			// missing SKUs are only reported once after the snapshot they disappeared in, resolve when they come back
+			_, reappeared := latest[state.Sku]
+			stillFiring = !reappeared
+		}
+		params := db.DeleteInventoryAlertStateParams{Rule: state.Rule, Sku: state.Sku}
+		if (!ruleExists || !stillFiring) && !resolving[params] {
+			resolving[params] = true
+			resolved = append(resolved, params)
+		}
+	}
+
+	toNotify := make(map[string][]alert.Alert)
+	for _, rule := range rules {
+		targets := rule.Notifiers
+		if len(targets) == 0 {
+			targets = notifiers
+		}
+		for _, sku := range slices.Sorted(maps.Keys(firing[rule.Name])) {
+			for _, target := range targets {
+				if delivered[delivery{rule.Name, sku, target}] {
+					continue
+				}
+				toNotify[target] = append(toNotify[target], firing[rule.Name][sku])
+			}
+		}
+	}
+	return toNotify, resolved
+}
+
+// evaluateAlertRule returns the alerts of rule keyed by SKU.
+func evaluateAlertRule(rule config.AlertRuleConfig, latest map[string]db.FbaInventorySnapshot, previous map[string]db.FbaInventorySnapshot, recommendations map[string]RestockRecommendation, previousAt time.Time) map[string]alert.Alert {
+	alerts := make(map[string]alert.Alert)
+	now := time.Now().UTC()
+	newAlert := func(snapshot db.FbaInventorySnapshot, value float64, message string) alert.Alert {
+		return alert.Alert{
+			Rule:      rule.Name,
+			Type:      rule.Type,
+			SKU:       snapshot.Sku,
+			ASIN:      snapshot.Asin.String,
+			Title:     snapshot.Title.String,
+			Value:     value,
+			Threshold: rule.Threshold,
+			Message:   message,
+			FiredAt:   now,
+		}
+	}
+	applies := func(sku string) bool {
+		return len(rule.SKUs) == 0 || slices.Contains(rule.SKUs, sku)
+	}
+
+	if rule.Type == alert.RuleSKUMissing {
+		for sku, snapshot := range previous {
+			if _, exists := latest[sku]; exists || !applies(sku) {
+				continue
+			}
+			alerts[sku] = newAlert(snapshot, 0, fmt.Sprintf("%s (%s) disappeared from inventory summaries, last seen at %s", sku, snapshot.Asin.String, previousAt.Format(time.RFC3339)))
+		}
+		return alerts
+	}
+
+	for sku, snapshot := range latest {
+		if !applies(sku) {
+			continue
+		}
+		switch rule.Type {
+		case alert.RuleFulfillableBelow:
+			fulfillable := float64(snapshot.FulfillableQuantity.Int64)
+			if fulfillable < rule.Threshold {
+				alerts[sku] = newAlert(snapshot, fulfillable, fmt.Sprintf("%s (%s) has %d fulfillable units, below %g", sku, snapshot.Asin.String, snapshot.FulfillableQuantity.Int64, rule.Threshold))
+			}
+		case alert.RuleDaysOfCoverBelow:
+			recommendation, exists := recommendations[sku]
+			if !exists || math.IsInf(recommendation.DaysOfCover, 1) {
+				continue
+			}
+			if recommendation.DaysOfCover < rule.Threshold {
+				alerts[sku] = newAlert(snapshot, recommendation.DaysOfCover, fmt.Sprintf("%s (%s) has %.1f days of cover at %.2f units/day, below %g", sku, snapshot.Asin.String, recommendation.DaysOfCover, recommendation.Velocity, rule.Threshold))
+			}
+		case alert.RuleUnfulfillableAbove:
+			unfulfillable := float64(snapshot.UnfulfillableQuantity.Int64)
+			if unfulfillable > rule.Threshold {
+				alerts[sku] = newAlert(snapshot, unfulfillable, fmt.Sprintf("%s (%s) has %d unfulfillable units, above %g", sku, snapshot.Asin.String, snapshot.UnfulfillableQuantity.Int64, rule.Threshold))
+			}
+		}
+	}
+	return alerts
+}
+
+// getAlertNotifiers constructs the configured notifiers, or a single stdout notifier if none are configured.
+func getAlertNotifiers() ([]alert.Notifier, error) {
+	configs := cfg.Amazon.FBA.Alerts.Notifiers
+	if len(configs) == 0 {
+		configs = []config.NotifierConfig{{Name: "stdout", Type: "stdout"}}
+	}
+	notifiers := make([]alert.Notifier, 0, len(configs))
+	for _, notifierCfg := range configs {
+		if notifierCfg.Name == "" {
+			notifierCfg.Name = notifierCfg.Type
+		}
+		notifier, err := alert.NewNotifier(notifierCfg)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/caner-cetin/halycon/internal/alert"
+	"github.com/caner-cetin/halycon/internal/config"
+	"github.com/caner-cetin/halycon/internal/db"
+)
+
+func TestEvaluateAlertRule(t *testing.T) {
+	var snapshots []db.FbaInventorySnapshot
+	// A sells 5 units a day, B does not sell, C disappears in the latest snapshot and D is new
+	snapshots = append(snapshots, inventoryHistory("A", snapshotCounts{day: 0, fulfillable: 100, unfulfillable: 3}, snapshotCounts{day: 10, fulfillable: 50, unfulfillable: 3})...)
+	snapshots = append(snapshots, inventoryHistory("B", snapshotCounts{day: 0, fulfillable: 10}, snapshotCounts{day: 10, fulfillable: 10})...)
+	snapshots = append(snapshots, inventoryHistory("C", snapshotCounts{day: 0, fulfillable: 30})...)
+	snapshots = append(snapshots, inventoryHistory("D", snapshotCounts{day: 10, fulfillable: 2, unfulfillable: 8})...)
+
+	latest, previous, previousAt := latestInventorySnapshots(snapshots)
+	if got := slices.Sorted(maps.Keys(latest)); !slices.Equal(got, []string{"A", "B", "D"}) {
+		t.Fatalf("latest snapshot has %v, want [A B D]", got)
+	}
+	if got := slices.Sorted(maps.Keys(previous)); !slices.Equal(got, []string{"A", "B", "C"}) {
+		t.Fatalf("previous snapshot has %v, want [A B C]", got)
+	}
+	recommendations := make(map[string]RestockRecommendation)
+	for _, recommendation := range computeRestockRecommendations(snapshots, 30, 60) {
+		recommendations[recommendation.SKU] = recommendation
+	}
+
+	tests := []struct {
+		name string
+		rule config.AlertRuleConfig
+		// want is the value of the alert of each SKU
+		want map[string]float64
+	}{
+		{
+			name: "fulfillable below",
+			rule: config.AlertRuleConfig{Type: alert.RuleFulfillableBelow, Threshold: 20},
+			want: map[string]float64{"B": 10, "D": 2},
+		},
+		{
+			name: "fulfillable below limited to skus",
+			rule: config.AlertRuleConfig{Type: alert.RuleFulfillableBelow, Threshold: 20, SKUs: []string{"D"}},
+			want: map[string]float64{"D": 2},
+		},
+		{
+			name: "days of cover below skips skus without sales",
+			rule: config.AlertRuleConfig{Type: alert.RuleDaysOfCoverBelow, Threshold: 15},
+			want: map[string]float64{"A": 10},
+		},
+		{
+			name: "days of cover equal to threshold",
+			rule: config.AlertRuleConfig{Type: alert.RuleDaysOfCoverBelow, Threshold: 10},
+			want: map[string]float64{},
+		},
+		{
+			name: "unfulfillable above",
+			rule: config.AlertRuleConfig{Type: alert.RuleUnfulfillableAbove, Threshold: 5},
+			want: map[string]float64{"D": 8},
+		},
+		{
+			name: "sku missing",
+			rule: config.AlertRuleConfig{Type: alert.RuleSKUMissing},
+			want: map[string]float64{"C": 0},
+		},
+		{
+			name: "sku missing limited to skus",
+			rule: config.AlertRuleConfig{Type: alert.RuleSKUMissing, SKUs: []string{"A"}},
+			want: map[string]float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Name = "rule"
+			alerts := evaluateAlertRule(tt.rule, latest, previous, recommendations, previousAt)
+			got := make(map[string]float64, len(alerts))
+			for sku, a := range alerts {
+				got[sku] = a.Value
+				if a.Rule != "rule" || a.Type != tt.rule.Type || a.SKU != sku || a.ASIN != "B0"+sku || a.Threshold != tt.rule.Threshold {
+					t.Errorf("alert of %s = %+v", sku, a)
+				}
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("evaluateAlertRule() values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPendingInventoryAlerts(t *testing.T) {
+	rules := []config.AlertRuleConfig{
+		{Name: "low", Type: alert.RuleFulfillableBelow, Threshold: 20},
+		{Name: "stranded", Type: alert.RuleUnfulfillableAbove, Threshold: 5, Notifiers: []string{"webhook"}},
+		{Name: "missing", Type: alert.RuleSKUMissing},
+	}
+	notifiers := []string{"stdout", "webhook"}
+	firing := func(rule string, skus ...string) map[string]map[string]alert.Alert {
+		alerts := map[string]map[string]alert.Alert{"low": {}, "stranded": {}, "missing": {}}
+		for _, sku := range skus {
+			alerts[rule][sku] = alert.Alert{Rule: rule, SKU: sku}
+		}
+		return alerts
+	}
+	latest := map[string]db.FbaInventorySnapshot{"A": {Sku: "A"}, "B": {Sku: "B"}}
+
+	tests := []struct {
+		name   string
+		firing map[string]map[string]alert.Alert
+		states []db.InventoryAlertState
+		// want is the rule/sku of alerts to send keyed by notifier
+		want         map[string][]string
+		wantResolved []db.DeleteInventoryAlertStateParams
+	}{
+		{
+			name:   "new alert is sent to all notifiers",
+			firing: firing("low", "B", "A"),
+			want:   map[string][]string{"stdout": {"low/A", "low/B"}, "webhook": {"low/A", "low/B"}},
+		},
+		{
+			name:   "new alert is sent to notifiers of rule",
+			firing: firing("stranded", "A"),
+			want:   map[string][]string{"webhook": {"stranded/A"}},
+		},
+		{
+			name:   "delivered alert is not sent again",
+			firing: firing("low", "A"),
+			states: []db.InventoryAlertState{
+				{Rule: "low", Sku: "A", Notifier: "stdout"},
+				{Rule: "low", Sku: "A", Notifier: "webhook"},
+			},
+			want: map[string][]string{},
+		},
+		{
+			name:   "failed notifier is retried alone",
+			firing: firing("low", "A"),
+			states: []db.InventoryAlertState{{Rule: "low", Sku: "A", Notifier: "stdout"}},
+			want:   map[string][]string{"webhook": {"low/A"}},
+		},
+		{
+			name:   "cleared alert is resolved once for all notifiers",
+			firing: firing("low"),
+			states: []db.InventoryAlertState{
+				{Rule: "low", Sku: "A", Notifier: "stdout"},
+				{Rule: "low", Sku: "A", Notifier: "webhook"},
+			},
+			want:         map[string][]string{},
+			wantResolved: []db.DeleteInventoryAlertStateParams{{Rule: "low", Sku: "A"}},
+		},
+		{
+			name:         "alert of removed rule is resolved",
+			firing:       firing("low"),
+			states:       []db.InventoryAlertState{{Rule: "removed", Sku: "A", Notifier: "stdout"}},
+			want:         map[string][]string{},
+			wantResolved: []db.DeleteInventoryAlertStateParams{{Rule: "removed", Sku: "A"}},
+		},
+		{
+			name:   "missing sku stays fired while it is missing",
+			firing: firing("missing"),
+			states: []db.InventoryAlertState{{Rule: "missing", Sku: "C", Notifier: "stdout"}, {Rule: "missing", Sku: "C", Notifier: "webhook"}},
+			want:   map[string][]string{},
+		},
+		{
+			name:         "missing sku is resolved when it reappears",
+			firing:       firing("missing"),
+			states:       []db.InventoryAlertState{{Rule: "missing", Sku: "A", Notifier: "stdout"}},
+			want:         map[string][]string{},
+			wantResolved: []db.DeleteInventoryAlertStateParams{{Rule: "missing", Sku: "A"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toNotify, resolved := pendingInventoryAlerts(rules, notifiers, tt.firing, latest, tt.states)
+			got := make(map[string][]string, len(toNotify))
+			for notifier, alerts := range toNotify {
+				for _, a := range alerts {
+					got[notifier] = append(got[notifier], a.Rule+"/"+a.SKU)
+				}
+			}
+			if !maps.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("pendingInventoryAlerts() sends %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(resolved, tt.wantResolved) {
+				t.Errorf("pendingInventoryAlerts() resolves %v, want %v", resolved, tt.wantResolved)
+			}
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/caner-cetin/halycon/internal"
 	"github.com/caner-cetin/halycon/internal/amazon/catalog"
+	"github.com/caner-cetin/halycon/internal/db"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -67,3 +74,131 @@ func getCatalogItem(cmd *cobra.Command, args []string) {
 		}
 	}
 }
+
+// Sources of cached catalog item dimensions. Manual dimensions are never overwritten by the catalog, missing marks items the catalog
+// has no dimensions for, so they are not searched again on every run.
+const (
+	dimensionSourcePackage = "package"
+	dimensionSourceItem    = "item"
+	dimensionSourceManual  = "manual"
+	dimensionSourceMissing = "missing"
+)
+
+// searchCatalogItemsMaxIdentifiers is the maximum number of identifiers searchCatalogItems accepts at once.
+const searchCatalogItemsMaxIdentifiers = 20
+
+// itemDimensions are the dimensions of a single unit in inches and its weight in pounds.
+type itemDimensions struct {
+	Length float64
+	Width  float64
+	Height float64
+	Weight float64
+}
+
+func (d itemDimensions) valid() bool {
+	return d.Length > 0 && d.Width > 0 && d.Height > 0 && d.Weight > 0
+}
+
+// loadItemDimensions returns the dimensions of given ASINs from the local cache, searching the catalog for ASINs that are not cached yet.
+// Package dimensions are preferred over item dimensions, as they are what is put into a box. With refresh, every ASIN except the ones
+// with manual dimensions is searched again. ASINs without dimensions are not included in the result.
+func loadItemDimensions(app AppCtx, marketplaceId string, asins []string, refresh bool) (map[string]itemDimensions, error) {
+	dimensions := make(map[string]itemDimensions, len(asins))
+	var missing []string
+	for _, asin := range asins {
+		cached, err := app.Query.GetCatalogItemDimension(app.Ctx, db.GetCatalogItemDimensionParams{MarketplaceID: marketplaceId, Asin: asin})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get cached dimensions of %s: %w", asin, err)
+		}
+		if err == nil && (!refresh || cached.Source == dimensionSourceManual) {
+			if cached.Source != dimensionSourceMissing {
+				dimensions[asin] = itemDimensions{Length: cached.Length.Float64, Width: cached.Width.Float64, Height: cached.Height.Float64, Weight: cached.Weight.Float64}
+			}
+			continue
+		}
+		missing = append(missing, asin)
+	}
+
+	for batch := range slices.Chunk(missing, searchCatalogItemsMaxIdentifiers) {
+		params := catalog.SearchCatalogItemsParams{
+			MarketplaceIds:  []string{marketplaceId},
+			IdentifiersType: internal.Ptr(catalog.ASIN),
+			Identifiers:     &batch,
+			IncludedData:    &[]catalog.SearchCatalogItemsParamsIncludedData{"dimensions"},
+		}
+		resp, err := app.Amazon.Client.SearchCatalogItems(app.Ctx, &params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search catalog items: %w", err)
+		}
+		found := map[string]catalog.Item{}
+		if resp.JSON200 != nil {
+			for _, item := range resp.JSON200.Items {
+				found[item.Asin] = item
+			}
+		}
+		for _, asin := range batch {
+			source := dimensionSourceMissing
+			var parsed itemDimensions
+			if item, ok := found[asin]; ok && item.Dimensions != nil {
+				for _, marketplace := range *item.Dimensions {
+					if marketplace.MarketplaceId != marketplaceId {
+						continue
+					}
+					if d, ok := catalogDimensions(marketplace.Package); ok {
+						parsed, source = d, dimensionSourcePackage
+					} else if d, ok := catalogDimensions(marketplace.Item); ok {
+						parsed, source = d, dimensionSourceItem
+					}
+				}
+			}
+			if err := saveItemDimensions(app.Ctx, app.Query, marketplaceId, asin, parsed, source); err != nil {
+				return nil, fmt.Errorf("failed to cache dimensions of %s: %w", asin, err)
+			}
+			if source != dimensionSourceMissing {
+				dimensions[asin] = parsed
+			}
+		}
+	}
+	return dimensions, nil
+}
+
+func saveItemDimensions(ctx context.Context, query *db.Queries, marketplaceId string, asin string, dimensions itemDimensions, source string) error {
+	valid := source != dimensionSourceMissing
+	return query.UpsertCatalogItemDimension(ctx, db.UpsertCatalogItemDimensionParams{
+		MarketplaceID: marketplaceId,
+		Asin:          asin,
+		Length:        sql.NullFloat64{Float64: dimensions.Length, Valid: valid},
+		Width:         sql.NullFloat64{Float64: dimensions.Width, Valid: valid},
+		Height:        sql.NullFloat64{Float64: dimensions.Height, Valid: valid},
+		Weight:        sql.NullFloat64{Float64: dimensions.Weight, Valid: valid},
+		Source:        source,
+		UpdatedAt:     time.Now().UTC(),
+	})
+}
+
+// catalogDimensions converts catalog dimensions to inches and pounds, reporting false if any of them is missing or in an unknown unit.
+func catalogDimensions(dimensions *catalog.Dimensions) (itemDimensions, bool) {
+	var parsed itemDimensions
+	if dimensions == nil {
+		return parsed, false
+	}
+	var ok [4]bool
+	parsed.Length, ok[0] = catalogDimension(dimensions.Length, lengthUnitsInInches)
+	parsed.Width, ok[1] = catalogDimension(dimensions.Width, lengthUnitsInInches)
+	parsed.Height, ok[2] = catalogDimension(dimensions.Height, lengthUnitsInInches)
+	parsed.Weight, ok[3] = catalogDimension(dimensions.Weight, weightUnitsInPounds)
+	return parsed, !slices.Contains(ok[:], false) && parsed.valid()
+}
+
+var (
+	lengthUnitsInInches = map[string]float64{"inches": 1, "feet": 12, "centimeters": inchesPerCentimeter, "millimeters": inchesPerCentimeter / 10, "meters": inchesPerCentimeter * 100}
+	weightUnitsInPounds = map[string]float64{"pounds": 1, "ounces": 1.0 / 16, "kilograms": poundsPerKilogram, "grams": poundsPerKilogram / 1000}
+)
+
+func catalogDimension(dimension *catalog.Dimension, units map[string]float64) (float64, bool) {
+	if dimension == nil || dimension.Value == nil || dimension.Unit == nil {
+		return 0, false
+	}
+	factor, ok := units[strings.ToLower(*dimension.Unit)]
+	return float64(*dimension.Value) * factor, ok
+}
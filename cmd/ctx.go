@@ -8,7 +8,9 @@ import (
 
 	"github.com/caner-cetin/halycon/internal"
 	"github.com/caner-cetin/halycon/internal/amazon/catalog"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_eligibility"
 	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound_v0"
 	"github.com/caner-cetin/halycon/internal/amazon/fba_inventory"
 	"github.com/caner-cetin/halycon/internal/amazon/feeds"
 	"github.com/caner-cetin/halycon/internal/amazon/listings"
@@ -36,6 +38,8 @@ const (
 	ServiceFBAInventory
 	ServiceProductTypeDefinitions
 	ServiceFeeds
+	ServiceFBAInboundV0
+	ServiceFBAEligibility
 )
 
 type ResourceConfig struct {
@@ -122,6 +126,20 @@ func WrapCommandWithResources(fn func(cmd *cobra.Command, args []string), resour
 							return
 						}
 						app.Amazon.Client.AddService(sp_api.FeedsServiceName, client)
+					case ServiceFBAInboundV0:
+						client, err := fba_inbound_v0.NewClientWithResponses(server)
+						if err != nil {
+							log.Error().Err(err).Msg("failed to create fba inbound v0 client")
+							return
+						}
+						app.Amazon.Client.AddService(sp_api.FBAInboundV0ServiceName, client)
+					case ServiceFBAEligibility:
+						client, err := fba_eligibility.NewClientWithResponses(server)
+						if err != nil {
+							log.Error().Err(err).Msg("failed to create fba eligibility client")
+							return
+						}
+						app.Amazon.Client.AddService(sp_api.FBAEligibilityServiceName, client)
 					}
 				}
 			case ResourceDB:
@@ -143,6 +161,7 @@ func WrapCommandWithResources(fn func(cmd *cobra.Command, args []string), resour
 					log.Error().Err(err).Msg("failed to open sqlite database")
 					return
 				}
+				defer internal.CloseDB(app.DB)
 				if err := db.Migrate(app.DB); err != nil {
 					log.Error().Err(err).Msg("failed to migrate database")
 					return
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/product_type_definitions"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/rs/zerolog/log"
+)
+
+// definitionCacheTTL is how long the latest definition of a product type is served from the local database, before it is
+// fetched again to check for a new version. Definitions of a given version do not change and never expire.
+const definitionCacheTTL = 24 * time.Hour
+
+// productTypeDefinitionKey is the request a definition is cached for, versions of a product type are stored under the same key.
+type productTypeDefinitionKey struct {
+	ProductType   string
+	MarketplaceID string
+	Requirements  string
+	Locale        string
+}
+
+func newProductTypeDefinitionKey(productType string, params product_type_definitions.GetDefinitionsProductTypeParams) productTypeDefinitionKey {
+	key := productTypeDefinitionKey{
+		ProductType:   productType,
+		MarketplaceID: strings.Join(params.MarketplaceIds, ","),
+		Requirements:  "LISTING",
+		Locale:        "DEFAULT",
+	}
+	if params.Requirements != nil {
+		key.Requirements = cmp.Or(string(*params.Requirements), key.Requirements)
+	}
+	if params.Locale != nil {
+		key.Locale = cmp.Or(string(*params.Locale), key.Locale)
+	}
+	return key
+}
+
+// cachedProductTypeDefinition is a product type definition with its schema document.
+type cachedProductTypeDefinition struct {
+	Definition product_type_definitions.ProductTypeDefinition
+	Schema     []byte
+	FetchedAt  time.Time
+	// Cached is set when the definition is served from the local database.
+	Cached bool
+}
+
+// loadProductTypeDefinition returns a version of a product type definition, the latest one if version is empty or LATEST.
+// Definitions and schemas are cached in the local database, refresh fetches the latest version from Amazon even if the cached
+// one has not expired yet. A warning is logged when the latest version is different from the one cached before.
+func loadProductTypeDefinition(app AppCtx, productType string, params product_type_definitions.GetDefinitionsProductTypeParams, version string, refresh bool) (cachedProductTypeDefinition, error) {
+	key := newProductTypeDefinitionKey(productType, params)
+	latest := version == "" || strings.EqualFold(version, "LATEST")
+	var previous *db.ProductTypeDefinition
+	if latest {
+		row, err := app.Query.GetLatestProductTypeDefinition(app.Ctx, db.GetLatestProductTypeDefinitionParams(key))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return cachedProductTypeDefinition{}, fmt.Errorf("failed to get cached definition: %w", err)
+		}
+		if err == nil {
+			previous = &row
+		}
+	} else {
+		row, err := app.Query.GetProductTypeDefinitionVersion(app.Ctx, db.GetProductTypeDefinitionVersionParams{
+			ProductType:   key.ProductType,
+			MarketplaceID: key.MarketplaceID,
+			Requirements:  key.Requirements,
+			Locale:        key.Locale,
+			Version:       version,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return cachedProductTypeDefinition{}, fmt.Errorf("failed to get cached definition: %w", err)
+		}
+		if err == nil {
+			previous = &row
+		}
+	}
+	if previous != nil && (!latest || (!refresh && time.Since(previous.FetchedAt) < definitionCacheTTL)) {
+		definition, found, err := readCachedProductTypeDefinition(app, *previous)
+		if err != nil {
+			return cachedProductTypeDefinition{}, err
+		}
+		if found {
+			return definition, nil
+		}
+	}
+
+	if !latest {
+		params.ProductTypeVersion = &version
+	}
+	resp, err := app.Amazon.Client.GetProductTypeDefinition(app.Ctx, productType, &params)
+	if err != nil {
+		return cachedProductTypeDefinition{}, fmt.Errorf("failed to get product type definition: %w", err)
+	}
+	definition := cachedProductTypeDefinition{Definition: *resp.JSON200, FetchedAt: time.Now().UTC()}
+	checksum, schema, err := loadSchemaDocument(app, productType, definition.Definition.Schema)
+	if err != nil {
+		return cachedProductTypeDefinition{}, err
+	}
+	definition.Schema = schema
+
+	fetchedVersion := definition.Definition.ProductTypeVersion.Version
+	if latest && previous != nil && previous.Version != fetchedVersion {
+		log.Warn().Str("product_type", productType).Str("from", previous.Version).Str("to", fetchedVersion).
+			Msgf("product type has a new version, compare them with: halycon definition diff --type %s --from %s --to %s", productType, previous.Version, fetchedVersion)
+	}
+	encoded, err := json.Marshal(definition.Definition)
+	if err != nil {
+		return cachedProductTypeDefinition{}, err
+	}
+	if err := app.Query.UpsertProductTypeDefinition(app.Ctx, db.UpsertProductTypeDefinitionParams{
+		ProductType:    key.ProductType,
+		MarketplaceID:  key.MarketplaceID,
+		Requirements:   key.Requirements,
+		Locale:         key.Locale,
+		Version:        fetchedVersion,
+		Latest:         definition.Definition.ProductTypeVersion.Latest,
+		Definition:     string(encoded),
+		SchemaChecksum: checksum,
+		FetchedAt:      definition.FetchedAt,
+	}); err != nil {
+		log.Warn().Err(err).Str("product_type", productType).Msg("failed to cache product type definition")
+		return definition, nil
+	}
+	if definition.Definition.ProductTypeVersion.Latest {
+		if err := app.Query.ClearLatestProductTypeDefinition(app.Ctx, db.ClearLatestProductTypeDefinitionParams{
+			ProductType:   key.ProductType,
+			MarketplaceID: key.MarketplaceID,
+			Requirements:  key.Requirements,
+			Locale:        key.Locale,
+			Version:       fetchedVersion,
+		}); err != nil {
+			log.Warn().Err(err).Str("product_type", productType).Msg("failed to update cached product type definitions")
+		}
+	}
+	return definition, nil
+}
+
+// readCachedProductTypeDefinition decodes a cached definition with its schema, found is false if the schema is not cached.
+func readCachedProductTypeDefinition(app AppCtx, row db.ProductTypeDefinition) (cachedProductTypeDefinition, bool, error) {
+	schema, err := app.Query.GetProductTypeSchema(app.Ctx, row.SchemaChecksum)
+	if errors.Is(err, sql.ErrNoRows) {
+		return cachedProductTypeDefinition{}, false, nil
+	}
+	if err != nil {
+		return cachedProductTypeDefinition{}, false, fmt.Errorf("failed to get cached schema: %w", err)
+	}
+	definition := cachedProductTypeDefinition{Schema: []byte(schema.Schema), FetchedAt: row.FetchedAt, Cached: true}
+	if err := json.Unmarshal([]byte(row.Definition), &definition.Definition); err != nil {
+		return cachedProductTypeDefinition{}, false, fmt.Errorf("failed to decode cached definition: %w", err)
+	}
+	return definition, true, nil
+}
+
+// loadSchemaDocument returns the schema document of a definition from the local database, or downloads and stores it. Schemas
+// are stored by the checksum given with the definition, so they are downloaded again only when Amazon publishes a new version.
+func loadSchemaDocument(app AppCtx, productType string, link product_type_definitions.SchemaLink) (string, []byte, error) {
+	if link.Checksum != "" {
+		cached, err := app.Query.GetProductTypeSchema(app.Ctx, link.Checksum)
+		if err == nil {
+			return link.Checksum, []byte(cached.Schema), nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", nil, fmt.Errorf("failed to get cached schema: %w", err)
+		}
+	}
+	data, err := downloadProductTypeSchema(link.Link.Resource)
+	if err != nil {
+		return "", nil, err
+	}
+	checksum := link.Checksum
+	if checksum == "" {
+		sum := sha256.Sum256(data)
+		checksum = hex.EncodeToString(sum[:])
+	}
+	if err := app.Query.InsertProductTypeSchema(app.Ctx, db.InsertProductTypeSchemaParams{
+		Checksum:    checksum,
+		ProductType: productType,
+		Schema:      string(data),
+		FetchedAt:   time.Now().UTC(),
+	}); err != nil {
+		log.Warn().Err(err).Str("product_type", productType).Msg("failed to cache product type schema")
+	}
+	return checksum, data, nil
+}
+
+// productTypeDefinitionParams builds the definition request for the default marketplaces, empty values use Amazon's defaults.
+func productTypeDefinitionParams(requirements string, locale string) product_type_definitions.GetDefinitionsProductTypeParams {
+	params := product_type_definitions.GetDefinitionsProductTypeParams{MarketplaceIds: cfg.Amazon.Auth.DefaultMerchant.MarketplaceID}
+	if requirements != "" {
+		params.Requirements = internal.Ptr(product_type_definitions.GetDefinitionsProductTypeParamsRequirements(requirements))
+	}
+	if locale != "" {
+		params.Locale = internal.Ptr(product_type_definitions.GetDefinitionsProductTypeParamsLocale(locale))
+	}
+	return params
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/caner-cetin/halycon/internal/jsonschema"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type definitionDiffConfig struct {
+	ProductType  string
+	Requirements string
+	Locale       string
+	From         string
+	To           string
+	Export       exportConfig
+}
+
+// definitionChange is a difference between two versions of a product type schema.
+type definitionChange struct {
+	Path string
+	// Change is one of added, removed, required, optional, type, constraint, enum or condition.
+	Change string
+	From   string
+	To     string
+}
+
+var (
+	definitionDiffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "compares the schemas of two versions of a product type",
+		Run:   WrapCommandWithResources(diffDefinitions, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceProductTypeDefinitions}}),
+	}
+	definitionDiffCfg definitionDiffConfig
+)
+
+func getDefinitionDiffCmd() *cobra.Command {
+	flags := definitionDiffCmd.PersistentFlags()
+	flags.StringVarP(&definitionDiffCfg.ProductType, "type", "t", "", "The Amazon product type name.")
+	flags.StringVarP(&definitionDiffCfg.Requirements, "requirements", "r", "", "requirements of the definition (LISTING, LISTING_PRODUCT_ONLY or LISTING_OFFER_ONLY)")
+	flags.StringVar(&definitionDiffCfg.Locale, "locale", "", "locale of display labels, such as en_US (default: the default locale of the marketplace)")
+	flags.StringVar(&definitionDiffCfg.From, "from", "", "older product type version (default: the cached version before --to)")
+	flags.StringVar(&definitionDiffCfg.To, "to", "", "newer product type version (default: latest)")
+	addExportFlags(flags, &definitionDiffCfg.Export, "definition_diff")
+	definitionDiffCmd.MarkPersistentFlagRequired("type") //nolint:errcheck
+	return definitionDiffCmd
+}
+
+// definitionConstraintKeywords are compared between versions of a property.
+var definitionConstraintKeywords = []string{"minLength", "maxLength", "maxUtf8ByteLength", "pattern", "minimum", "maximum", "minItems", "maxItems", "maxUniqueItems"}
+
+func diffDefinitions(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	params := productTypeDefinitionParams(definitionDiffCfg.Requirements, definitionDiffCfg.Locale)
+	to, err := loadProductTypeDefinition(app, definitionDiffCfg.ProductType, params, definitionDiffCfg.To, false)
+	if err != nil {
+		log.Error().Err(err).Str("version", definitionDiffCfg.To).Msg("failed to load product type definition")
+		return
+	}
+	toVersion := to.Definition.ProductTypeVersion.Version
+	fromVersion := definitionDiffCfg.From
+	if fromVersion == "" {
+		key := newProductTypeDefinitionKey(definitionDiffCfg.ProductType, params)
+		versions, err := app.Query.ListProductTypeDefinitionVersions(app.Ctx, db.ListProductTypeDefinitionVersionsParams(key))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to list cached versions")
+			return
+		}
+		index := slices.IndexFunc(versions, func(v db.ProductTypeDefinition) bool { return v.Version != toVersion })
+		if index == -1 {
+			log.Error().Str("version", toVersion).Msg("no other version of the product type is cached, pass an older one with --from")
+			return
+		}
+		fromVersion = versions[index].Version
+	}
+	from, err := loadProductTypeDefinition(app, definitionDiffCfg.ProductType, params, fromVersion, false)
+	if err != nil {
+		log.Error().Err(err).Str("version", fromVersion).Msg("failed to load product type definition")
+		return
+	}
+	fromSchema, err := jsonschema.Compile(from.Schema)
+	if err != nil {
+		log.Error().Err(err).Str("version", fromVersion).Msg("failed to compile schema")
+		return
+	}
+	toSchema, err := jsonschema.Compile(to.Schema)
+	if err != nil {
+		log.Error().Err(err).Str("version", toVersion).Msg("failed to compile schema")
+		return
+	}
+
+	changes := diffDefinitionSchemas(fromSchema, toSchema)
+	fmt.Printf("%s %s %s -> %s\n\n", color.CyanString("Comparing"), definitionDiffCfg.ProductType, fromVersion, toVersion)
+	displayDefinitionChanges(changes)
+
+	if definitionDiffCfg.Export.Enabled() {
+		if err := writeExport(definitionDiffCfg.Export, "definition_diff", definitionDiffExportTable(changes, definitionDiffCfg.ProductType, fromVersion, toVersion)); err != nil {
+			log.Error().Err(err).Msg("failed to export definition diff")
+		}
+	}
+}
+
+// diffDefinitionSchemas compares properties of two schemas, with their types, requirements, constraints and enum values, and
+// the conditional rules at the root of the schemas.
+func diffDefinitionSchemas(from *jsonschema.Schema, to *jsonschema.Schema) []definitionChange {
+	var changes []definitionChange
+	diffDefinitionNode(from, to, from.Root(), to.Root(), "$", 0, &changes)
+
+	fromRules, toRules := definitionRules(from.Root()), definitionRules(to.Root())
+	for _, rule := range fromRules {
+		if !slices.Contains(toRules, rule) {
+			changes = append(changes, definitionChange{Path: "$", Change: "condition", From: rule})
+		}
+	}
+	for _, rule := range toRules {
+		if !slices.Contains(fromRules, rule) {
+			changes = append(changes, definitionChange{Path: "$", Change: "condition", To: rule})
+		}
+	}
+	return changes
+}
+
+func diffDefinitionNode(fromSchema *jsonschema.Schema, toSchema *jsonschema.Schema, fromNode any, toNode any, path string, depth int, changes *[]definitionChange) {
+	if depth > scaffoldMaxDepth {
+		return
+	}
+	from, to := mergeScaffoldSchema(fromSchema, fromNode), mergeScaffoldSchema(toSchema, toNode)
+	if fromType, toType := scaffoldType(from), scaffoldType(to); fromType != toType {
+		*changes = append(*changes, definitionChange{Path: path, Change: "type", From: fromType, To: toType})
+	}
+	for _, keyword := range definitionConstraintKeywords {
+		fromValue, toValue := definitionKeywordValue(from, keyword), definitionKeywordValue(to, keyword)
+		if fromValue != toValue {
+			*changes = append(*changes, definitionChange{Path: path, Change: "constraint", From: strings.TrimSpace(keyword + " " + fromValue), To: strings.TrimSpace(keyword + " " + toValue)})
+		}
+	}
+	fromChoices, toChoices := scaffoldChoices(fromSchema, from), scaffoldChoices(toSchema, to)
+	var removed, added []string
+	for _, choice := range fromChoices {
+		if !slices.Contains(toChoices, choice) {
+			removed = append(removed, choice)
+		}
+	}
+	for _, choice := range toChoices {
+		if !slices.Contains(fromChoices, choice) {
+			added = append(added, choice)
+		}
+	}
+	if len(removed) > 0 || len(added) > 0 {
+		*changes = append(*changes, definitionChange{Path: path, Change: "enum", From: strings.Join(removed, ", "), To: strings.Join(added, ", ")})
+	}
+
+	fromProperties, _ := from["properties"].(map[string]any)
+	toProperties, _ := to["properties"].(map[string]any)
+	fromRequired, toRequired := asSlice(from["required"]), asSlice(to["required"])
+	var names []string
+	for name := range fromProperties {
+		names = append(names, name)
+	}
+	for name := range toProperties {
+		if _, ok := fromProperties[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		child := path + "." + name
+		fromProperty, inFrom := fromProperties[name]
+		toProperty, inTo := toProperties[name]
+		isRequired := slices.Contains(toRequired, any(name))
+		wasRequired := slices.Contains(fromRequired, any(name))
+		switch {
+		case !inFrom:
+			*changes = append(*changes, definitionChange{Path: child, Change: "added", To: definitionRequirement(isRequired)})
+		case !inTo:
+			*changes = append(*changes, definitionChange{Path: child, Change: "removed", From: definitionRequirement(wasRequired)})
+		default:
+			if isRequired && !wasRequired {
+				*changes = append(*changes, definitionChange{Path: child, Change: "required", From: "optional", To: "required"})
+			} else if wasRequired && !isRequired {
+				*changes = append(*changes, definitionChange{Path: child, Change: "optional", From: "required", To: "optional"})
+			}
+			diffDefinitionNode(fromSchema, toSchema, fromProperty, toProperty, child, depth+1, changes)
+		}
+	}
+	fromItems, fromHasItems := from["items"]
+	toItems, toHasItems := to["items"]
+	if fromHasItems && toHasItems {
+		diffDefinitionNode(fromSchema, toSchema, fromItems, toItems, path+"[*]", depth+1, changes)
+	}
+}
+
+func definitionRequirement(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+func definitionKeywordValue(object map[string]any, keyword string) string {
+	value, ok := object[keyword]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// definitionRules returns the conditional rules (allOf branches with if) of a schema as JSON, keys of encoded maps are sorted
+// so equal rules encode the same.
+func definitionRules(root map[string]any) []string {
+	var rules []string
+	for _, branch := range asSlice(root["allOf"]) {
+		if object, ok := branch.(map[string]any); ok && object["if"] != nil {
+			encoded, err := json.Marshal(object)
+			if err != nil {
+				continue
+			}
+			rules = append(rules, string(encoded))
+		}
+	}
+	return rules
+}
+
+func displayDefinitionChanges(changes []definitionChange) {
+	fmt.Printf("%-12s %-50s %-40s %s\n", "Change", "Path", "From", "To")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------------------------------------"))
+	required := 0
+	for _, change := range changes {
+		line := fmt.Sprintf("%-12s %-50s %-40s %s", change.Change, truncateString(change.Path, 50), truncateString(valueOrDash(change.From), 40), truncateString(valueOrDash(change.To), 60))
+		switch {
+		case change.Change == "removed":
+			fmt.Println(color.RedString(line))
+		case change.Change == "required", change.Change == "added" && change.To == "required":
+			required++
+			fmt.Println(color.YellowString(line))
+		case change.Change == "added":
+			fmt.Println(color.GreenString(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+	fmt.Printf("\n%d changes, %d newly required properties\n", len(changes), required)
+}
+
+func definitionDiffExportTable(changes []definitionChange, productType string, fromVersion string, toVersion string) export.Table {
+	table := export.Table{
+		Name: "diff",
+		Columns: []export.Column{
+			{Name: "Product Type", Key: "product_type", Type: export.String},
+			{Name: "From Version", Key: "from_version", Type: export.String},
+			{Name: "To Version", Key: "to_version", Type: export.String},
+			{Name: "Path", Key: "path", Type: export.String},
+			{Name: "Change", Key: "change", Type: export.String},
+			{Name: "From", Key: "from", Type: export.String},
+			{Name: "To", Key: "to", Type: export.String},
+		},
+	}
+	for _, change := range changes {
+		table.Rows = append(table.Rows, []any{productType, fromVersion, toVersion, change.Path, change.Change, change.From, change.To})
+	}
+	return table
+}
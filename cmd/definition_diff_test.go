@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/caner-cetin/halycon/internal/jsonschema"
+)
+
+const definitionDiffFrom = `{
+  "$defs": {"marketplace_id": {"type": "string", "enum": ["ATVPDKIKX0DER"]}},
+  "required": ["item_name", "brand"],
+  "properties": {
+    "item_name": {"type": "array", "items": {"type": "object", "required": ["value"], "properties": {
+      "value": {"type": "string", "maxLength": 200},
+      "marketplace_id": {"$ref": "#/$defs/marketplace_id"}
+    }}},
+    "brand": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "string"}}}},
+    "color": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "string", "enum": ["red", "blue"]}}}},
+    "legacy": {"type": "string"},
+    "size": {"type": "string"}
+  },
+  "allOf": [{"if": {"required": ["color"]}, "then": {"required": ["size"]}}]
+}`
+
+const definitionDiffTo = `{
+  "$defs": {"marketplace_id": {"type": "string", "enum": ["ATVPDKIKX0DER", "A2EUQ1WTGCTBG2"]}},
+  "required": ["item_name", "color", "model_number"],
+  "properties": {
+    "item_name": {"type": "array", "items": {"type": "object", "required": ["value"], "properties": {
+      "value": {"type": "string", "maxLength": 250},
+      "marketplace_id": {"$ref": "#/$defs/marketplace_id"}
+    }}},
+    "brand": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "string"}}}},
+    "color": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "string", "enum": ["red", "green"]}}}},
+    "model_number": {"type": "string"},
+    "size": {"type": "integer"}
+  },
+  "allOf": [{"if": {"required": ["color"]}, "then": {"required": ["model_number"]}}]
+}`
+
+func TestDiffDefinitionSchemas(t *testing.T) {
+	compile := func(raw string) *jsonschema.Schema {
+		t.Helper()
+		schema, err := jsonschema.Compile([]byte(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return schema
+	}
+	from, to := compile(definitionDiffFrom), compile(definitionDiffTo)
+
+	tests := []struct {
+		name     string
+		from, to *jsonschema.Schema
+		want     []definitionChange
+	}{
+		{
+			name: "changes",
+			from: from,
+			to:   to,
+			want: []definitionChange{
+				{Path: "$.brand", Change: "optional", From: "required", To: "optional"},
+				{Path: "$.color", Change: "required", From: "optional", To: "required"},
+				{Path: "$.color[*].value", Change: "enum", From: "blue", To: "green"},
+				{Path: "$.item_name[*].marketplace_id", Change: "enum", To: "A2EUQ1WTGCTBG2"},
+				{Path: "$.item_name[*].value", Change: "constraint", From: "maxLength 200", To: "maxLength 250"},
+				{Path: "$.legacy", Change: "removed", From: "optional"},
+				{Path: "$.model_number", Change: "added", To: "required"},
+				{Path: "$.size", Change: "type", From: "string", To: "integer"},
+				{Path: "$", Change: "condition", From: `{"if":{"required":["color"]},"then":{"required":["size"]}}`},
+				{Path: "$", Change: "condition", To: `{"if":{"required":["color"]},"then":{"required":["model_number"]}}`},
+			},
+		},
+		{
+			name: "reversed",
+			from: to,
+			to:   from,
+			want: []definitionChange{
+				{Path: "$.brand", Change: "required", From: "optional", To: "required"},
+				{Path: "$.color", Change: "optional", From: "required", To: "optional"},
+				{Path: "$.color[*].value", Change: "enum", From: "green", To: "blue"},
+				{Path: "$.item_name[*].marketplace_id", Change: "enum", From: "A2EUQ1WTGCTBG2"},
+				{Path: "$.item_name[*].value", Change: "constraint", From: "maxLength 250", To: "maxLength 200"},
+				{Path: "$.legacy", Change: "added", To: "optional"},
+				{Path: "$.model_number", Change: "removed", From: "required"},
+				{Path: "$.size", Change: "type", From: "integer", To: "string"},
+				{Path: "$", Change: "condition", From: `{"if":{"required":["color"]},"then":{"required":["model_number"]}}`},
+				{Path: "$", Change: "condition", To: `{"if":{"required":["color"]},"then":{"required":["size"]}}`},
+			},
+		},
+		{
+			name: "same version",
+			from: from,
+			to:   compile(definitionDiffFrom),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffDefinitionSchemas(tt.from, tt.to)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("diffDefinitionSchemas() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,663 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/caner-cetin/halycon/internal/jsonschema"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type definitionExportConfig struct {
+	ProductType  string
+	Requirements string
+	Locale       string
+	Version      string
+	Refresh      bool
+	File         string
+	Format       string
+}
+
+var (
+	definitionExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "writes a product type definition as browsable Markdown or HTML, a JSON Schema bundle, or a table of properties",
+		Run:   WrapCommandWithResources(exportDefinition, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceProductTypeDefinitions}}),
+	}
+	definitionExportCfg definitionExportConfig
+)
+
+const (
+	definitionFormatMarkdown   = "md"
+	definitionFormatHTML       = "html"
+	definitionFormatJSONSchema = "jsonschema"
+)
+
+func getDefinitionExportCmd() *cobra.Command {
+	flags := definitionExportCmd.PersistentFlags()
+	flags.StringVarP(&definitionExportCfg.ProductType, "type", "t", "", "The Amazon product type name.")
+	flags.StringVarP(&definitionExportCfg.Requirements, "requirements", "r", "", "requirements of the definition (LISTING, LISTING_PRODUCT_ONLY or LISTING_OFFER_ONLY)")
+	flags.StringVar(&definitionExportCfg.Locale, "locale", "", "locale of display labels, such as en_US (default: the default locale of the marketplace)")
+	flags.StringVar(&definitionExportCfg.Version, "version", "", "product type version (default: latest)")
+	flags.BoolVar(&definitionExportCfg.Refresh, "refresh", false, "fetch the latest definition from Amazon even if the cached one has not expired")
+	flags.StringVar(&definitionExportCfg.File, "file", "", "export file path (default <type>_<version>.<format>)")
+	flags.StringVar(&definitionExportCfg.Format, "format", "", fmt.Sprintf("export format (md|html|jsonschema|%s), inferred from --file extension if not provided, defaults to md", export.FormatList()))
+	definitionExportCmd.MarkPersistentFlagRequired("type") //nolint:errcheck
+	return definitionExportCmd
+}
+
+// definitionDocument is a product type definition flattened for documentation, attributes are listed by property group.
+type definitionDocument struct {
+	ProductType    string
+	DisplayName    string
+	Version        string
+	Requirements   string
+	Locale         string
+	MarketplaceIds []string
+	Groups         []definitionGroup
+	Rules          []string
+}
+
+type definitionGroup struct {
+	Title       string
+	Description string
+	Attributes  []definitionAttribute
+}
+
+// definitionAttribute is a top level property of the schema, Properties are its nested properties.
+type definitionAttribute struct {
+	definitionProperty
+	Name       string
+	Properties []definitionProperty
+	Conditions []string
+}
+
+type definitionProperty struct {
+	Path        string
+	Title       string
+	Description string
+	Type        string
+	Required    bool
+	Constraints []string
+	Values      []string
+	Examples    []string
+}
+
+func exportDefinition(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	format, ext, err := resolveDefinitionExportFormat(definitionExportCfg.Format, definitionExportCfg.File)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to resolve export format")
+		return
+	}
+	params := productTypeDefinitionParams(definitionExportCfg.Requirements, definitionExportCfg.Locale)
+	definition, err := loadProductTypeDefinition(app, definitionExportCfg.ProductType, params, definitionExportCfg.Version, definitionExportCfg.Refresh)
+	if err != nil {
+		log.Error().Err(err).Str("product_type", definitionExportCfg.ProductType).Msg("failed to load product type definition")
+		return
+	}
+	schema, err := jsonschema.Compile(definition.Schema)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to compile schema")
+		return
+	}
+	path := definitionExportCfg.File
+	if path == "" {
+		path = fmt.Sprintf("%s_%s.%s", strings.ToLower(definitionExportCfg.ProductType), definition.Definition.ProductTypeVersion.Version, ext)
+	}
+	document := newDefinitionDocument(definition, schema)
+
+	var out []byte
+	switch format {
+	case definitionFormatMarkdown:
+		out = []byte(renderDefinitionMarkdown(document))
+	case definitionFormatHTML:
+		if out, err = renderDefinitionHTML(document); err != nil {
+			log.Error().Err(err).Msg("failed to render definition")
+			return
+		}
+	case definitionFormatJSONSchema:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, definition.Schema, "", "  "); err != nil {
+			log.Error().Err(err).Msg("failed to format schema")
+			return
+		}
+		out = buf.Bytes()
+	default:
+		if err := writeExport(exportConfig{File: path, Format: format}, "definition", definitionExportTable(document)); err != nil {
+			log.Error().Err(err).Msg("failed to export definition")
+		}
+		return
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		log.Error().Err(err).Str("file", path).Msg("failed to write definition")
+		return
+	}
+	color.Green("%s %s written to %s", definitionExportCfg.ProductType, definition.Definition.ProductTypeVersion.Version, path)
+}
+
+// resolveDefinitionExportFormat returns the format and the default file extension, format is inferred from the file extension
+// if not provided. Formats other than md, html and jsonschema are tabular export formats.
+func resolveDefinitionExportFormat(format string, file string) (string, string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		lower := strings.ToLower(file)
+		switch ext := filepath.Ext(lower); {
+		case ext == ".md" || ext == ".markdown":
+			format = definitionFormatMarkdown
+		case ext == ".html" || ext == ".htm":
+			format = definitionFormatHTML
+		case strings.HasSuffix(lower, ".schema.json"):
+			format = definitionFormatJSONSchema
+		default:
+			format = definitionFormatMarkdown
+			if inferred, ok := export.FormatFromPath(file); ok {
+				format = string(inferred)
+			}
+		}
+	}
+	switch format {
+	case definitionFormatMarkdown, definitionFormatHTML:
+		return format, format, nil
+	case definitionFormatJSONSchema:
+		return format, "schema.json", nil
+	}
+	parsed, err := export.ParseFormat(format)
+	if err != nil {
+		return "", "", fmt.Errorf("unknown format %s, must be one of md, html, jsonschema or %s", format, export.FormatList())
+	}
+	return string(parsed), string(parsed), nil
+}
+
+func newDefinitionDocument(definition cachedProductTypeDefinition, schema *jsonschema.Schema) definitionDocument {
+	document := definitionDocument{
+		ProductType:    definition.Definition.ProductType,
+		DisplayName:    definition.Definition.DisplayName,
+		Version:        definition.Definition.ProductTypeVersion.Version,
+		Requirements:   string(definition.Definition.Requirements),
+		Locale:         definition.Definition.Locale,
+		MarketplaceIds: definition.Definition.MarketplaceIds,
+	}
+	root := schema.Root()
+	properties, _ := root["properties"].(map[string]any)
+	required := asSlice(root["required"])
+
+	// conditions of rules are listed on every attribute the rule affects
+	conditions := make(map[string][]string)
+	for _, branch := range asSlice(root["allOf"]) {
+		rule, ok := branch.(map[string]any)
+		if !ok || rule["if"] == nil {
+			continue
+		}
+		text := describeDefinitionRule(schema, rule)
+		document.Rules = append(document.Rules, text)
+		for _, name := range definitionRuleProperties(schema, rule) {
+			conditions[name] = append(conditions[name], text)
+		}
+	}
+
+	grouped := make(map[string]bool)
+	groupNames := make([]string, 0, len(definition.Definition.PropertyGroups))
+	for name := range definition.Definition.PropertyGroups {
+		groupNames = append(groupNames, name)
+	}
+	slices.Sort(groupNames)
+	newAttribute := func(name string) definitionAttribute {
+		attribute := definitionAttribute{Name: name, Conditions: conditions[name]}
+		var nested []definitionProperty
+		walkDefinitionProperty(schema, properties[name], name, slices.Contains(required, any(name)), 0, &nested)
+		attribute.definitionProperty, attribute.Properties = nested[0], nested[1:]
+		return attribute
+	}
+	for _, groupName := range groupNames {
+		group := definition.Definition.PropertyGroups[groupName]
+		section := definitionGroup{Title: groupName}
+		if group.DisplayName != nil {
+			section.Title = *group.DisplayName
+		}
+		if group.Description != nil {
+			section.Description = *group.Description
+		}
+		if group.PropertyNames != nil {
+			for _, name := range *group.PropertyNames {
+				if _, ok := properties[name]; ok && !grouped[name] {
+					grouped[name] = true
+					section.Attributes = append(section.Attributes, newAttribute(name))
+				}
+			}
+		}
+		if len(section.Attributes) > 0 {
+			document.Groups = append(document.Groups, section)
+		}
+	}
+	var ungrouped []string
+	for name := range properties {
+		if !grouped[name] {
+			ungrouped = append(ungrouped, name)
+		}
+	}
+	if len(ungrouped) > 0 {
+		slices.Sort(ungrouped)
+		section := definitionGroup{Title: "Other"}
+		for _, name := range ungrouped {
+			section.Attributes = append(section.Attributes, newAttribute(name))
+		}
+		document.Groups = append(document.Groups, section)
+	}
+	return document
+}
+
+// walkDefinitionProperty appends a property and its nested properties. Items of arrays are not listed on their own, properties
+// of object items are listed under path[*], and constraints and values of scalar items are listed on the array.
+func walkDefinitionProperty(schema *jsonschema.Schema, node any, path string, required bool, depth int, properties *[]definitionProperty) {
+	object := mergeScaffoldSchema(schema, node)
+	property := definitionProperty{
+		Path:        path,
+		Required:    required,
+		Type:        scaffoldType(object),
+		Constraints: definitionConstraints(object),
+		Values:      definitionValues(schema, object),
+	}
+	property.Title, _ = object["title"].(string)
+	property.Description, _ = object["description"].(string)
+	for _, example := range asSlice(object["examples"]) {
+		property.Examples = append(property.Examples, fmt.Sprint(example))
+	}
+	children, childPath, childRequired := object, path, asSlice(object["required"])
+	if items, ok := object["items"]; ok {
+		itemObject := mergeScaffoldSchema(schema, items)
+		itemType := scaffoldType(itemObject)
+		property.Type = strings.TrimSpace("array of " + itemType)
+		if itemType == "object" {
+			children, childPath, childRequired = itemObject, path+"[*]", asSlice(itemObject["required"])
+		} else {
+			property.Constraints = append(property.Constraints, definitionConstraints(itemObject)...)
+			property.Values = append(property.Values, definitionValues(schema, itemObject)...)
+		}
+	}
+	*properties = append(*properties, property)
+	if depth >= scaffoldMaxDepth {
+		return
+	}
+	nested, _ := children["properties"].(map[string]any)
+	names := make([]string, 0, len(nested))
+	for name := range nested {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		walkDefinitionProperty(schema, nested[name], childPath+"."+name, slices.Contains(childRequired, any(name)), depth+1, properties)
+	}
+}
+
+func definitionConstraints(object map[string]any) []string {
+	var constraints []string
+	for _, keyword := range definitionConstraintKeywords {
+		if value := definitionKeywordValue(object, keyword); value != "" {
+			constraints = append(constraints, keyword+" "+value)
+		}
+	}
+	if unique, _ := object["uniqueItems"].(bool); unique {
+		constraints = append(constraints, "uniqueItems")
+	}
+	if selectors := asSlice(object["selectors"]); len(selectors) > 0 {
+		constraints = append(constraints, "selectors "+joinDefinitionValues(selectors))
+	}
+	if editable, ok := object["editable"].(bool); ok && !editable {
+		constraints = append(constraints, "not editable")
+	}
+	if hidden, _ := object["hidden"].(bool); hidden {
+		constraints = append(constraints, "hidden")
+	}
+	return constraints
+}
+
+// definitionValues lists the enum values of a schema and of its anyOf / oneOf branches, with their display names if given.
+func definitionValues(schema *jsonschema.Schema, object map[string]any) []string {
+	var values []string
+	names := asSlice(object["enumNames"])
+	for i, value := range asSlice(object["enum"]) {
+		text := fmt.Sprint(value)
+		if i < len(names) && fmt.Sprint(names[i]) != text {
+			text = fmt.Sprintf("%s (%v)", text, names[i])
+		}
+		values = append(values, text)
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		for _, branch := range asSlice(object[keyword]) {
+			values = append(values, definitionValues(schema, mergeScaffoldSchema(schema, branch))...)
+		}
+	}
+	return values
+}
+
+func joinDefinitionValues(values []any) string {
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		texts = append(texts, fmt.Sprint(value))
+	}
+	return strings.Join(texts, ", ")
+}
+
+// describeDefinitionRule describes an if / then / else rule, such as "when parentage_level is parent: requires variation_theme".
+func describeDefinitionRule(schema *jsonschema.Schema, rule map[string]any) string {
+	text := fmt.Sprintf("when %s: %s", describeDefinitionCondition(schema, rule["if"]), describeDefinitionEffect(schema, rule["then"]))
+	if otherwise, ok := rule["else"]; ok {
+		text += "; otherwise: " + describeDefinitionEffect(schema, otherwise)
+	}
+	return text
+}
+
+func describeDefinitionCondition(schema *jsonschema.Schema, node any) string {
+	object := mergeScaffoldSchema(schema, node)
+	var parts []string
+	properties, _ := object["properties"].(map[string]any)
+	for _, name := range asSlice(object["required"]) {
+		if _, ok := properties[fmt.Sprint(name)]; !ok {
+			parts = append(parts, fmt.Sprintf("%v is set", name))
+		}
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		property := mergeScaffoldSchema(schema, properties[name])
+		values := definitionConditionValues(schema, property)
+		switch {
+		case property["not"] != nil:
+			parts = append(parts, fmt.Sprintf("%s is not %s", name, cmp.Or(strings.Join(definitionConditionValues(schema, mergeScaffoldSchema(schema, property["not"])), " or "), "matching")))
+		case len(values) > 0:
+			parts = append(parts, fmt.Sprintf("%s is %s", name, strings.Join(values, " or ")))
+		default:
+			parts = append(parts, fmt.Sprintf("%s is set", name))
+		}
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		var branches []string
+		for _, branch := range asSlice(object[keyword]) {
+			branches = append(branches, describeDefinitionCondition(schema, branch))
+		}
+		if len(branches) > 0 {
+			parts = append(parts, "("+strings.Join(branches, " or ")+")")
+		}
+	}
+	if not, ok := object["not"]; ok {
+		parts = append(parts, "not ("+describeDefinitionCondition(schema, not)+")")
+	}
+	if len(parts) == 0 {
+		return "always"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// definitionConditionValues collects enum and const values anywhere under a condition, such as value of a contains clause.
+func definitionConditionValues(schema *jsonschema.Schema, object map[string]any) []string {
+	var values []string
+	for _, value := range asSlice(object["enum"]) {
+		values = append(values, fmt.Sprint(value))
+	}
+	if value, ok := object["const"]; ok {
+		values = append(values, fmt.Sprint(value))
+	}
+	for _, keyword := range []string{"contains", "items"} {
+		if child, ok := object[keyword]; ok {
+			values = append(values, definitionConditionValues(schema, mergeScaffoldSchema(schema, child))...)
+		}
+	}
+	if properties, ok := object["properties"].(map[string]any); ok {
+		for _, child := range properties {
+			values = append(values, definitionConditionValues(schema, mergeScaffoldSchema(schema, child))...)
+		}
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		for _, branch := range asSlice(object[keyword]) {
+			values = append(values, definitionConditionValues(schema, mergeScaffoldSchema(schema, branch))...)
+		}
+	}
+	slices.Sort(values)
+	return slices.Compact(values)
+}
+
+func describeDefinitionEffect(schema *jsonschema.Schema, node any) string {
+	object := mergeScaffoldSchema(schema, node)
+	var parts []string
+	if required := asSlice(object["required"]); len(required) > 0 {
+		parts = append(parts, "requires "+joinDefinitionValues(required))
+	}
+	if not, ok := object["not"].(map[string]any); ok {
+		if required := asSlice(not["required"]); len(required) > 0 {
+			parts = append(parts, "does not allow "+joinDefinitionValues(required))
+		}
+	}
+	properties, _ := object["properties"].(map[string]any)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if values := definitionConditionValues(schema, mergeScaffoldSchema(schema, properties[name])); len(values) > 0 {
+			parts = append(parts, fmt.Sprintf("restricts %s to %s", name, strings.Join(values, ", ")))
+		} else {
+			parts = append(parts, "constrains "+name)
+		}
+	}
+	if len(parts) == 0 {
+		return "no change"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// definitionRuleProperties returns the properties a rule requires, forbids or constrains.
+func definitionRuleProperties(schema *jsonschema.Schema, rule map[string]any) []string {
+	var names []string
+	for _, keyword := range []string{"then", "else"} {
+		object := mergeScaffoldSchema(schema, rule[keyword])
+		for _, name := range asSlice(object["required"]) {
+			names = append(names, fmt.Sprint(name))
+		}
+		if not, ok := object["not"].(map[string]any); ok {
+			for _, name := range asSlice(not["required"]) {
+				names = append(names, fmt.Sprint(name))
+			}
+		}
+		properties, _ := object["properties"].(map[string]any)
+		for name := range properties {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func renderDefinitionMarkdown(document definitionDocument) string {
+	var b strings.Builder
+	cell := func(value string) string {
+		return strings.NewReplacer("|", "\\|", "<", "&lt;", "\r\n", "<br>", "\n", "<br>").Replace(value)
+	}
+	fmt.Fprintf(&b, "# %s (`%s`)\n\n", document.DisplayName, document.ProductType)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n| Version | %s |\n| Requirements | %s |\n| Locale | %s |\n| Marketplaces | %s |\n\n",
+		document.Version, document.Requirements, document.Locale, strings.Join(document.MarketplaceIds, ", "))
+	for _, group := range document.Groups {
+		fmt.Fprintf(&b, "## %s\n\n", group.Title)
+		if group.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", group.Description)
+		}
+		for _, attribute := range group.Attributes {
+			fmt.Fprintf(&b, "### `%s`", attribute.Name)
+			if attribute.Title != "" {
+				fmt.Fprintf(&b, " %s", attribute.Title)
+			}
+			fmt.Fprintf(&b, " (%s)\n\n", definitionRequirement(attribute.Required))
+			if attribute.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", attribute.Description)
+			}
+			fmt.Fprintf(&b, "*   Type: %s\n", valueOrDash(attribute.Type))
+			if len(attribute.Constraints) > 0 {
+				fmt.Fprintf(&b, "*   Constraints: %s\n", strings.Join(attribute.Constraints, ", "))
+			}
+			if len(attribute.Values) > 0 {
+				fmt.Fprintf(&b, "*   Values: %s\n", strings.Join(attribute.Values, ", "))
+			}
+			if len(attribute.Examples) > 0 {
+				fmt.Fprintf(&b, "*   Examples: %s\n", strings.Join(attribute.Examples, ", "))
+			}
+			for _, condition := range attribute.Conditions {
+				fmt.Fprintf(&b, "*   Condition: %s\n", condition)
+			}
+			b.WriteString("\n")
+			if len(attribute.Properties) == 0 {
+				continue
+			}
+			b.WriteString("| Property | Title | Type | Required | Constraints | Values | Examples | Description |\n")
+			b.WriteString("|---|---|---|---|---|---|---|---|\n")
+			for _, property := range attribute.Properties {
+				fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s | %s | %s |\n",
+					property.Path, cell(property.Title), property.Type, definitionRequirement(property.Required),
+					cell(strings.Join(property.Constraints, ", ")), cell(strings.Join(property.Values, ", ")),
+					cell(strings.Join(property.Examples, ", ")), cell(property.Description))
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(document.Rules) > 0 {
+		b.WriteString("## Conditional Requirements\n\n")
+		for _, rule := range document.Rules {
+			fmt.Fprintf(&b, "*   %s\n", rule)
+		}
+	}
+	return b.String()
+}
+
+var definitionHTMLTemplate = template.Must(template.New("definition").Funcs(template.FuncMap{
+	"join":        strings.Join,
+	"requirement": definitionRequirement,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.DisplayName}} ({{.ProductType}})</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+code { background: #f3f3f3; padding: 0 3px; }
+.required { color: #b45309; font-weight: bold; }
+.muted { color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.DisplayName}} (<code>{{.ProductType}}</code>)</h1>
+<table>
+<tr><th>Version</th><td>{{.Version}}</td></tr>
+<tr><th>Requirements</th><td>{{.Requirements}}</td></tr>
+<tr><th>Locale</th><td>{{.Locale}}</td></tr>
+<tr><th>Marketplaces</th><td>{{join .MarketplaceIds ", "}}</td></tr>
+</table>
+<ul>
+{{- range $i, $group := .Groups}}
+<li><a href="#group-{{$i}}">{{$group.Title}}</a></li>
+{{- end}}
+{{- if .Rules}}
+<li><a href="#rules">Conditional Requirements</a></li>
+{{- end}}
+</ul>
+{{- range $i, $group := .Groups}}
+<h2 id="group-{{$i}}">{{$group.Title}}</h2>
+{{- if $group.Description}}
+<p>{{$group.Description}}</p>
+{{- end}}
+{{- range $group.Attributes}}
+<h3 id="{{.Name}}"><code>{{.Name}}</code> {{.Title}} <span class="{{requirement .Required}}">({{requirement .Required}})</span></h3>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<ul>
+<li>Type: {{.Type}}</li>
+{{- if .Constraints}}
+<li>Constraints: {{join .Constraints ", "}}</li>
+{{- end}}
+{{- if .Values}}
+<li>Values: {{join .Values ", "}}</li>
+{{- end}}
+{{- if .Examples}}
+<li>Examples: {{join .Examples ", "}}</li>
+{{- end}}
+{{- range .Conditions}}
+<li>Condition: {{.}}</li>
+{{- end}}
+</ul>
+{{- if .Properties}}
+<table>
+<tr><th>Property</th><th>Title</th><th>Type</th><th>Required</th><th>Constraints</th><th>Values</th><th>Examples</th><th>Description</th></tr>
+{{- range .Properties}}
+<tr><td><code>{{.Path}}</code></td><td>{{.Title}}</td><td>{{.Type}}</td><td class="{{requirement .Required}}">{{requirement .Required}}</td><td>{{join .Constraints ", "}}</td><td>{{join .Values ", "}}</td><td>{{join .Examples ", "}}</td><td class="muted">{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .Rules}}
+<h2 id="rules">Conditional Requirements</h2>
+<ul>
+{{- range .Rules}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+func renderDefinitionHTML(document definitionDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := definitionHTMLTemplate.Execute(&buf, document); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func definitionExportTable(document definitionDocument) export.Table {
+	table := export.Table{
+		Name: "definition",
+		Columns: []export.Column{
+			{Name: "Group", Key: "group", Type: export.String},
+			{Name: "Attribute", Key: "attribute", Type: export.String},
+			{Name: "Path", Key: "path", Type: export.String},
+			{Name: "Title", Key: "title", Type: export.String},
+			{Name: "Description", Key: "description", Type: export.String},
+			{Name: "Type", Key: "type", Type: export.String},
+			{Name: "Required", Key: "required", Type: export.Bool},
+			{Name: "Constraints", Key: "constraints", Type: export.String},
+			{Name: "Values", Key: "values", Type: export.String},
+			{Name: "Examples", Key: "examples", Type: export.String},
+			{Name: "Conditions", Key: "conditions", Type: export.String},
+		},
+	}
+	row := func(group string, attribute string, property definitionProperty, conditions []string) []any {
+		return []any{group, attribute, property.Path, property.Title, property.Description, property.Type, property.Required,
+			strings.Join(property.Constraints, ", "), strings.Join(property.Values, ", "), strings.Join(property.Examples, ", "), strings.Join(conditions, "\n")}
+	}
+	for _, group := range document.Groups {
+		for _, attribute := range group.Attributes {
+			table.Rows = append(table.Rows, row(group.Title, attribute.Name, attribute.definitionProperty, attribute.Conditions))
+			for _, property := range attribute.Properties {
+				table.Rows = append(table.Rows, row(group.Title, attribute.Name, property, nil))
+			}
+		}
+	}
+	return table
+}
@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal/jsonschema"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type definitionScaffoldConfig struct {
+	ProductType  string
+	Requirements string
+	Optional     bool
+	Output       string
+	Format       string
+}
+
+var (
+	definitionScaffoldCmd = &cobra.Command{
+		Use:   "scaffold",
+		Short: "writes a starter attributes file for listings create from the schema of a product type",
+		Run:   WrapCommandWithResources(scaffoldDefinition, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceProductTypeDefinitions}}),
+	}
+	definitionScaffoldCfg definitionScaffoldConfig
+)
+
+func getDefinitionScaffoldCmd() *cobra.Command {
+	flags := definitionScaffoldCmd.PersistentFlags()
+	flags.StringVarP(&definitionScaffoldCfg.ProductType, "type", "t", "", "The Amazon product type name.")
+	flags.StringVarP(&definitionScaffoldCfg.Requirements, "requirements", "r", "", "requirements of the schema (LISTING, LISTING_PRODUCT_ONLY or LISTING_OFFER_ONLY)")
+	flags.BoolVar(&definitionScaffoldCfg.Optional, "optional", false, "also include optional attributes and properties")
+	flags.StringVarP(&definitionScaffoldCfg.Output, "output", "o", "", "output file, format is inferred from the .json, .yaml or .yml extension (default: stdout)")
+	flags.StringVar(&definitionScaffoldCfg.Format, "format", "", "output format, yaml (default, with enum choices and constraints as comments) or json (with placeholders)")
+	definitionScaffoldCmd.MarkPersistentFlagRequired("type") //nolint:errcheck
+	return definitionScaffoldCmd
+}
+
+// scaffoldMaxDepth stops walking recursive schemas.
+const scaffoldMaxDepth = 8
+
+// scaffoldNode is a value of the scaffold, objects keep the order of their keys.
+type scaffoldNode struct {
+	Keys     []string
+	Fields   map[string]*scaffoldNode
+	Items    []*scaffoldNode
+	IsArray  bool
+	Value    any
+	Choices  []string
+	Comment  string
+	Required bool
+}
+
+func scaffoldDefinition(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	format := strings.ToLower(definitionScaffoldCfg.Format)
+	if format == "" {
+		format = "yaml"
+		if strings.EqualFold(filepath.Ext(definitionScaffoldCfg.Output), ".json") {
+			format = "json"
+		}
+	}
+	if format != "yaml" && format != "json" {
+		log.Error().Str("format", definitionScaffoldCfg.Format).Msg("format must be yaml or json")
+		return
+	}
+	schema, err := loadProductTypeSchema(app, definitionScaffoldCfg.ProductType, definitionScaffoldCfg.Requirements)
+	if err != nil {
+		log.Error().Err(err).Str("product_type", definitionScaffoldCfg.ProductType).Msg("failed to load product type schema")
+		return
+	}
+	scaffold := scaffoldSchema(schema, schema.Root(), definitionScaffoldCfg.Optional, 0)
+
+	var out []byte
+	if format == "json" {
+		var buf bytes.Buffer
+		writeScaffoldJSON(&buf, scaffold, "")
+		buf.WriteString("\n")
+		out = buf.Bytes()
+	} else {
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(scaffoldYAML(scaffold)); err != nil {
+			log.Error().Err(err).Msg("failed to encode scaffold")
+			return
+		}
+		out = buf.Bytes()
+	}
+	if definitionScaffoldCfg.Output == "" {
+		os.Stdout.Write(out) //nolint:errcheck
+		return
+	}
+	if err := os.WriteFile(definitionScaffoldCfg.Output, out, 0644); err != nil {
+		log.Error().Err(err).Str("file", definitionScaffoldCfg.Output).Msg("failed to write scaffold")
+		return
+	}
+	color.Green("%d attributes written to %s", len(scaffold.Keys), definitionScaffoldCfg.Output)
+	fmt.Printf("Fill the values and create the listing with: halycon listings create --type %s --input %s --sku YOUR_SKU\n", definitionScaffoldCfg.ProductType, definitionScaffoldCfg.Output)
+}
+
+// scaffoldSchema builds a starter value for a schema node. Objects contain their required properties, and optional ones
+// with includeOptional, arrays contain a single item. marketplace_id and language_tag are filled from the config.
+func scaffoldSchema(schema *jsonschema.Schema, node any, includeOptional bool, depth int) *scaffoldNode {
+	object := mergeScaffoldSchema(schema, node)
+	result := &scaffoldNode{Comment: scaffoldComment(object)}
+	if depth > scaffoldMaxDepth {
+		return result
+	}
+	switch scaffoldType(object) {
+	case "object":
+		result.Fields = make(map[string]*scaffoldNode)
+		properties, _ := object["properties"].(map[string]any)
+		var required []string
+		for _, name := range asSlice(object["required"]) {
+			if key, _ := name.(string); key != "" && properties[key] != nil && !slices.Contains(required, key) {
+				required = append(required, key)
+			}
+		}
+		var optional []string
+		for key := range properties {
+			if !slices.Contains(required, key) {
+				optional = append(optional, key)
+			}
+		}
+		slices.Sort(optional)
+		keys := required
+		if includeOptional {
+			keys = append(keys, optional...)
+		}
+		for _, key := range keys {
+			field := scaffoldSchema(schema, properties[key], includeOptional, depth+1)
+			field.Required = slices.Contains(required, key)
+			switch key {
+			case "marketplace_id":
+				field.Value, field.Choices = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID[0], nil
+			case "language_tag":
+				field.Value, field.Choices = cfg.Amazon.DefaultLanguageTag, nil
+			}
+			result.Keys = append(result.Keys, key)
+			result.Fields[key] = field
+		}
+	case "array":
+		result.IsArray = true
+		if items, ok := object["items"]; ok {
+			result.Items = []*scaffoldNode{scaffoldSchema(schema, items, includeOptional, depth+1)}
+		}
+	case "integer", "number":
+		result.Value = 0
+	case "boolean":
+		result.Value = false
+	default:
+		result.Value = ""
+	}
+	if result.Value != nil {
+		result.Choices = scaffoldChoices(schema, object)
+		if len(result.Choices) == 1 {
+			result.Value, result.Choices = result.Choices[0], nil
+		}
+	}
+	return result
+}
+
+// mergeScaffoldSchema follows $ref and merges allOf branches into a single schema object, later keywords win.
+func mergeScaffoldSchema(schema *jsonschema.Schema, node any) map[string]any {
+	object, _ := node.(map[string]any)
+	merged := make(map[string]any, len(object))
+	if ref, ok := object["$ref"].(string); ok {
+		if target, found := schema.Resolve(ref); found {
+			for key, value := range mergeScaffoldSchema(schema, target) {
+				merged[key] = value
+			}
+		}
+	}
+	for _, branch := range asSlice(object["allOf"]) {
+		for key, value := range mergeScaffoldSchema(schema, branch) {
+			merged[key] = value
+		}
+	}
+	for key, value := range object {
+		merged[key] = value
+	}
+	return merged
+}
+
+// scaffoldType returns the type of a schema, types of anyOf / oneOf branches are used for schemas without one.
+func scaffoldType(object map[string]any) string {
+	switch t := object["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, name := range t {
+			if n, _ := name.(string); n != "null" {
+				return n
+			}
+		}
+	}
+	if _, ok := object["properties"]; ok {
+		return "object"
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		for _, branch := range asSlice(object[keyword]) {
+			if b, ok := branch.(map[string]any); ok {
+				if t := scaffoldType(b); t != "" {
+					return t
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// scaffoldChoices collects enum values of a schema and of its anyOf / oneOf branches.
+func scaffoldChoices(schema *jsonschema.Schema, object map[string]any) []string {
+	var choices []string
+	for _, value := range asSlice(object["enum"]) {
+		choices = append(choices, fmt.Sprint(value))
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		for _, branch := range asSlice(object[keyword]) {
+			choices = append(choices, scaffoldChoices(schema, mergeScaffoldSchema(schema, branch))...)
+		}
+	}
+	return choices
+}
+
+func scaffoldComment(object map[string]any) string {
+	var parts []string
+	if title, _ := object["title"].(string); title != "" {
+		parts = append(parts, title)
+	}
+	var constraints []string
+	for _, keyword := range []string{"minLength", "maxLength", "maxUtf8ByteLength", "minimum", "maximum", "minItems", "maxItems", "maxUniqueItems"} {
+		if value, ok := object[keyword].(float64); ok {
+			constraints = append(constraints, fmt.Sprintf("%s %g", keyword, value))
+		}
+	}
+	if len(constraints) > 0 {
+		parts = append(parts, strings.Join(constraints, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func asSlice(value any) []any {
+	slice, _ := value.([]any)
+	return slice
+}
+
+// scaffoldChoicesLimit is the number of enum choices listed in comments and placeholders.
+const scaffoldChoicesLimit = 15
+
+func formatScaffoldChoices(choices []string) string {
+	if len(choices) > scaffoldChoicesLimit {
+		return fmt.Sprintf("one of %s and %d more", strings.Join(choices[:scaffoldChoicesLimit], ", "), len(choices)-scaffoldChoicesLimit)
+	}
+	return "one of " + strings.Join(choices, ", ")
+}
+
+// scaffoldYAML converts a scaffold to a YAML node, with titles, constraints and enum choices as comments.
+func scaffoldYAML(node *scaffoldNode) *yaml.Node {
+	switch {
+	case node.Fields != nil:
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range node.Keys {
+			field := node.Fields[key]
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
+			valueNode := scaffoldYAML(field)
+			comment := field.Comment
+			if !field.Required {
+				comment = strings.TrimSuffix("optional, "+comment, ", ")
+			}
+			if len(field.Choices) > 0 {
+				comment = strings.TrimPrefix(comment+", "+formatScaffoldChoices(field.Choices), ", ")
+			}
+			if valueNode.Kind == yaml.ScalarNode {
+				valueNode.LineComment = comment
+			} else {
+				keyNode.HeadComment = comment
+			}
+			mapping.Content = append(mapping.Content, keyNode, valueNode)
+		}
+		return mapping
+	case node.IsArray:
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range node.Items {
+			sequence.Content = append(sequence.Content, scaffoldYAML(item))
+		}
+		return sequence
+	}
+	scalar := &yaml.Node{}
+	scalar.Encode(node.Value) //nolint:errcheck
+	return scalar
+}
+
+// writeScaffoldJSON writes a scaffold as indented JSON, enum choices are written as placeholders since JSON has no comments.
+func writeScaffoldJSON(buf *bytes.Buffer, node *scaffoldNode, indent string) {
+	switch {
+	case node.Fields != nil:
+		if len(node.Keys) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+		buf.WriteString("{\n")
+		for i, key := range node.Keys {
+			encoded, _ := json.Marshal(key)
+			buf.WriteString(indent + "  " + string(encoded) + ": ")
+			writeScaffoldJSON(buf, node.Fields[key], indent+"  ")
+			if i < len(node.Keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	case node.IsArray:
+		if len(node.Items) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteString("[\n")
+		for i, item := range node.Items {
+			buf.WriteString(indent + "  ")
+			writeScaffoldJSON(buf, item, indent+"  ")
+			if i < len(node.Items)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "]")
+	default:
+		value := node.Value
+		if len(node.Choices) > 0 {
+			value = fmt.Sprintf("<%s>", formatScaffoldChoices(node.Choices))
+		}
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(value) //nolint:errcheck
+		// drop the newline written by Encode
+		buf.Truncate(buf.Len() - 1)
+	}
+}
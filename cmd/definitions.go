@@ -16,8 +16,11 @@ import (
 )
 
 type getProductTypeDefinitionConfig struct {
-	Params      product_type_definitions.GetDefinitionsProductTypeParams
-	ProductType string
+	ProductType  string
+	Requirements string
+	Locale       string
+	Version      string
+	Refresh      bool
 }
 
 var (
@@ -29,7 +32,7 @@ var (
 	getProductTypeDefinitionDetailed bool
 	getProductTypeDefinitionCmd      = &cobra.Command{
 		Use: "get",
-		Run: WrapCommandWithResources(getProductTypeDefinition, ResourceConfig{Resources: []ResourceType{ResourceAmazon}, Services: []ServiceType{ServiceProductTypeDefinitions}}),
+		Run: WrapCommandWithResources(getProductTypeDefinition, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceProductTypeDefinitions}}),
 	}
 	getProductTypeDefinitionCfg getProductTypeDefinitionConfig
 
@@ -56,9 +59,16 @@ func getDefinitionsCmd() *cobra.Command {
 
 	getProductTypeDefinitionCmd.PersistentFlags().StringVarP(&getProductTypeDefinitionCfg.ProductType, "type", "t", "", "The Amazon product type name.")
 	getProductTypeDefinitionCmd.PersistentFlags().BoolVar(&getProductTypeDefinitionDetailed, "detailed", false, "complete property information")
+	getProductTypeDefinitionCmd.PersistentFlags().StringVarP(&getProductTypeDefinitionCfg.Requirements, "requirements", "r", "", "requirements of the definition (LISTING, LISTING_PRODUCT_ONLY or LISTING_OFFER_ONLY)")
+	getProductTypeDefinitionCmd.PersistentFlags().StringVar(&getProductTypeDefinitionCfg.Locale, "locale", "", "locale of display labels, such as en_US (default: the default locale of the marketplace)")
+	getProductTypeDefinitionCmd.PersistentFlags().StringVar(&getProductTypeDefinitionCfg.Version, "version", "", "product type version (default: latest)")
+	getProductTypeDefinitionCmd.PersistentFlags().BoolVar(&getProductTypeDefinitionCfg.Refresh, "refresh", false, "fetch the latest definition from Amazon even if the cached one has not expired")
 
 	definitionCmd.AddCommand(searchProductTypeDefinitionCmd)
 	definitionCmd.AddCommand(getProductTypeDefinitionCmd)
+	definitionCmd.AddCommand(getDefinitionScaffoldCmd())
+	definitionCmd.AddCommand(getDefinitionDiffCmd())
+	definitionCmd.AddCommand(getDefinitionExportCmd())
 	return definitionCmd
 }
 
@@ -90,20 +100,20 @@ func searchProductTypeDefinition(cmd *cobra.Command, args []string) {
 }
 func getProductTypeDefinition(cmd *cobra.Command, args []string) {
 	app := GetApp(cmd)
-	getProductTypeDefinitionCfg.Params.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
-	status, err := app.Amazon.Client.GetProductTypeDefinition(cmd.Context(), getProductTypeDefinitionCfg.ProductType, &getProductTypeDefinitionCfg.Params)
+	params := productTypeDefinitionParams(getProductTypeDefinitionCfg.Requirements, getProductTypeDefinitionCfg.Locale)
+	definition, err := loadProductTypeDefinition(app, getProductTypeDefinitionCfg.ProductType, params, getProductTypeDefinitionCfg.Version, getProductTypeDefinitionCfg.Refresh)
 	if err != nil {
 		log.Error().Err(err).Send()
 		return
 	}
+	if definition.Cached {
+		log.Debug().Time("fetched_at", definition.FetchedAt).Msg("using cached definition, pass --refresh to fetch it again")
+	}
 
-	result := status.JSON200
-	displayProductSummary(result)
-	schemaURL := result.Schema.Link.Resource
-
-	schema, err := fetchAndParseSchema(schemaURL)
+	displayProductSummary(&definition.Definition)
+	schema, err := fastjson.ParseBytes(definition.Schema)
 	if err != nil {
-		log.Error().Err(err).Send()
+		log.Error().Err(err).Msg("failed to parse schema")
 		return
 	}
 
@@ -115,26 +125,31 @@ func displayProductSummary(payload *product_type_definitions.ProductTypeDefiniti
 	yellow := color.New(color.FgYellow).SprintFunc()
 	green := color.New(color.FgGreen).SprintFunc()
 
-	fmt.Printf("%s: %s  |  %s: %s  |  %s: %s | %s: %s\n\n",
+	fmt.Printf("%s: %s  |  %s: %s  |  %s: %s  |  %s: %s | %s: %s\n\n",
 		bold("Product"), cyan(payload.DisplayName),
+		bold("Version"), yellow(payload.ProductTypeVersion.Version),
 		bold("Requirements"), yellow(payload.Requirements),
 		bold("Locale"), green(payload.Locale),
 		bold("Schema"), cyan(payload.Schema.Link.Resource),
 	)
 }
 
-func fetchAndParseSchema(schemaURL string) (*fastjson.Value, error) {
+// downloadProductTypeSchema downloads the schema document from the link given with a product type definition.
+func downloadProductTypeSchema(schemaURL string) ([]byte, error) {
 	resp, err := http.DefaultClient.Get(schemaURL) //nolint:bodyclose
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch schema from %s: %w", schemaURL, err)
 	}
 	defer internal.CloseReader(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch schema from %s: %s", schemaURL, resp.Status)
+	}
 	schema_bytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Error().Str("url", schemaURL).Err(err).Msg("error while reading schema")
 		return nil, fmt.Errorf("failed to read schema body: %w", err)
 	}
-	return fastjson.MustParseBytes(schema_bytes), nil
+	return schema_bytes, nil
 }
 
 func displayAllSchemaDetails(schema *fastjson.Value, indentLevel int) {
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/pflag"
+)
+
+// exportConfig is the shared `--file` and `--format` flags of commands with tabular output.
+type exportConfig struct {
+	File   string
+	Format string
+}
+
+func addExportFlags(flags *pflag.FlagSet, exportCfg *exportConfig, prefix string) {
+	flags.StringVar(&exportCfg.File, "file", "", fmt.Sprintf("export file path (default %s_<timestamp>.<format>)", prefix))
+	flags.StringVar(&exportCfg.Format, "format", "", fmt.Sprintf("export format (%s), inferred from --file extension if not provided", export.FormatList()))
+}
+
+// Enabled reports whether any export flag was provided.
+func (c exportConfig) Enabled() bool {
+	return c.File != "" || c.Format != ""
+}
+
+// resolve returns the export path and format, format is inferred from the file extension and defaults to csv.
+func (c exportConfig) resolve(prefix string) (string, export.Format, error) {
+	format := export.FormatCSV
+	if c.Format != "" {
+		parsed, err := export.ParseFormat(c.Format)
+		if err != nil {
+			return "", "", err
+		}
+		format = parsed
+	} else if inferred, ok := export.FormatFromPath(c.File); ok {
+		format = inferred
+	}
+	path := c.File
+	if path == "" {
+		path = export.DefaultPath(prefix, format)
+	}
+	return path, format, nil
+}
+
+// writeExport writes table with the format and path resolved from export flags, and reports the written file.
+func writeExport(exportCfg exportConfig, prefix string, table export.Table) error {
+	path, format, err := exportCfg.resolve(prefix)
+	if err != nil {
+		return err
+	}
+	if err := export.Write(path, format, table); err != nil {
+		return fmt.Errorf("failed to export %s: %w", format, err)
+	}
+	log.Info().Str("file", path).Str("format", string(format)).Int("rows", len(table.Rows)).Msg("exported")
+
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#22C55E")).
+		Bold(true)
+	fmt.Printf("\n%s\n", successStyle.Render(fmt.Sprintf("✅ %s Export Complete", format.Title())))
+	fmt.Printf("📄 File: %s\n", path)
+	fmt.Printf("📊 Records: %d\n", len(table.Rows))
+	return nil
+}
@@ -3,11 +3,8 @@ package cmd
 import (
 	"context"
 	"database/sql"
-	"encoding/csv"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +12,8 @@ import (
 	"github.com/caner-cetin/halycon/internal"
 	"github.com/caner-cetin/halycon/internal/amazon/catalog"
 	"github.com/caner-cetin/halycon/internal/amazon/fba_inventory"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/caner-cetin/halycon/internal/export"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/fatih/color"
@@ -31,6 +30,7 @@ type BuildInventoryConfig struct {
 type QueryInventoryConfig struct {
 	Keyword string
 	Output  string
+	Export  exportConfig
 }
 
 type InventoryFilter struct {
@@ -64,10 +64,17 @@ var (
 
 func getInventoryCmd() *cobra.Command {
 	queryInventoryCmd.PersistentFlags().StringVarP(&queryInventoryCfg.Keyword, "keyword", "k", "", "keyword to query in product names (optional, will prompt if not provided)")
-	queryInventoryCmd.PersistentFlags().StringVarP(&queryInventoryCfg.Output, "output", "o", "", "output format (csv, json, table), optional, will prompt if not provided")
+	queryInventoryCmd.PersistentFlags().StringVarP(&queryInventoryCfg.Output, "output", "o", "", "output format (table or an export format), optional, will prompt if not provided")
+	queryInventoryCmd.PersistentFlags().MarkDeprecated("output", "use --format instead") //nolint:errcheck
+	addExportFlags(queryInventoryCmd.PersistentFlags(), &queryInventoryCfg.Export, "inventory")
+	queryInventoryCmd.PersistentFlags().Lookup("format").Usage = fmt.Sprintf("output format (table|%s), inferred from --file extension if not provided", export.FormatList())
 	buildInventoryCmd.PersistentFlags().BoolVarP(&buildInventoryCfg.ForceRebuild, "force-rebuild", "f", false, "forces to rebuild table even if inventory is already built")
 	inventoryCmd.AddCommand(queryInventoryCmd)
 	inventoryCmd.AddCommand(buildInventoryCmd)
+	inventoryCmd.AddCommand(getRestockInventoryCmd())
+	inventoryCmd.AddCommand(getInventoryAlertsCmd())
+	inventoryCmd.AddCommand(getBrowseInventoryCmd())
+	inventoryCmd.AddCommand(getInventorySnapshotsCmd())
 	return inventoryCmd
 }
 
@@ -202,8 +209,8 @@ func fetchInventorySummaries(app AppCtx) ([]fba_inventory.InventorySummary, []st
 func insertInventorySummaries(app AppCtx, summaries []fba_inventory.InventorySummary, upcMap map[string]string) error {
 	stmt, err := app.DB.PrepareContext(app.Ctx,
 		`INSERT INTO fba_inventory
-		(title, total_quantity, fulfillable_quantity, inbound_receiving_quantity, inbound_shipped_quantity, sku, asin, upc)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+		(title, total_quantity, fulfillable_quantity, inbound_receiving_quantity, inbound_shipped_quantity, sku, asin, upc, fnsku, marketplace_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
 	}
@@ -223,7 +230,9 @@ func insertInventorySummaries(app AppCtx, summaries []fba_inventory.InventorySum
 			*summary.InventoryDetails.InboundShippedQuantity,
 			*summary.SellerSku,
 			*summary.Asin,
-			upc)
+			upc,
+			internal.NullString(summary.FnSku),
+			cfg.Amazon.Auth.DefaultMerchant.MarketplaceID[0])
 		if err != nil {
 			return fmt.Errorf("failed to insert inventory item: %w", err)
 		}
@@ -232,20 +241,66 @@ func insertInventorySummaries(app AppCtx, summaries []fba_inventory.InventorySum
 	return nil
 }
 
-func buildFBAInventoryTable(app AppCtx) ([]fba_inventory.InventorySummary, map[string]string, error) {
+// recordInventorySnapshot appends the fetched summaries to the snapshot history,
+// which is what sell-through velocity for `inventory restock` is computed from.
+func recordInventorySnapshot(app AppCtx, summaries []fba_inventory.InventorySummary) error {
+	tx, err := app.DB.BeginTx(app.Ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer internal.Rollback(tx)
+	query := app.Query.WithTx(tx)
+
+	snapshotAt := time.Now().UTC()
+	for _, summary := range summaries {
+		if summary.SellerSku == nil {
+			continue
+		}
+		params := db.InsertFBAInventorySnapshotParams{
+			SnapshotAt:    snapshotAt,
+			Sku:           *summary.SellerSku,
+			Asin:          internal.NullString(summary.Asin),
+			Title:         internal.NullString(summary.ProductName),
+			TotalQuantity: internal.NullInt64(summary.TotalQuantity),
+		}
+		if details := summary.InventoryDetails; details != nil {
+			params.FulfillableQuantity = internal.NullInt64(details.FulfillableQuantity)
+			params.InboundWorkingQuantity = internal.NullInt64(details.InboundWorkingQuantity)
+			params.InboundShippedQuantity = internal.NullInt64(details.InboundShippedQuantity)
+			params.InboundReceivingQuantity = internal.NullInt64(details.InboundReceivingQuantity)
+			if details.ReservedQuantity != nil {
+				params.ReservedQuantity = internal.NullInt64(details.ReservedQuantity.TotalReservedQuantity)
+			}
+			if details.UnfulfillableQuantity != nil {
+				params.UnfulfillableQuantity = internal.NullInt64(details.UnfulfillableQuantity.TotalUnfulfillableQuantity)
+			}
+		}
+		if err := query.InsertFBAInventorySnapshot(app.Ctx, params); err != nil {
+			return fmt.Errorf("failed to insert inventory snapshot for %s: %w", *summary.SellerSku, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit inventory snapshot: %w", err)
+	}
+	log.Info().Int("sku_count", len(summaries)).Time("snapshot_at", snapshotAt).Msg("recorded inventory snapshot")
+	return nil
+}
+
+func buildFBAInventoryTable(app AppCtx) ([]fba_inventory.InventorySummary, error) {
 	cnt, err := app.Query.FbaInventoryCount(app.Ctx)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, nil, fmt.Errorf("failed to get inventory count: %w", err)
+		return nil, fmt.Errorf("failed to get inventory count: %w", err)
 	}
 
 	if cnt == 0 || buildInventoryCfg.ForceRebuild {
 		if _, err := app.DB.ExecContext(app.Ctx, "DELETE FROM fba_inventory;"); err != nil {
-			return nil, nil, fmt.Errorf("failed to clear inventory table: %w", err)
+			return nil, fmt.Errorf("failed to clear inventory table: %w", err)
 		}
 
 		summaries, collectedASINs, err := fetchInventorySummaries(app)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		log.Info().Int("asin_count", len(collectedASINs)).Msg("fetching UPC data for ASINs")
@@ -255,121 +310,35 @@ func buildFBAInventoryTable(app AppCtx) ([]fba_inventory.InventorySummary, map[s
 		}
 
 		if err := insertInventorySummaries(app, summaries, upcMap); err != nil {
-			return nil, nil, err
+			return nil, err
+		}
+
+		if err := recordInventorySnapshot(app, summaries); err != nil {
+			return nil, err
 		}
 
 		color.Green("fba inventory table built successfully")
-		return summaries, upcMap, nil
+		return summaries, nil
 	} else {
 		color.Magenta("inventory already built, use [--force-rebuild / -f] to force rebuilding process")
-		return nil, make(map[string]string), nil
+		return nil, nil
 	}
 }
 
-func populateFTSFromDatabase(app AppCtx, stmt *sql.Stmt) error {
-	rows, err := app.DB.QueryContext(app.Ctx, `SELECT
-		title, total_quantity, fulfillable_quantity, inbound_receiving_quantity, inbound_shipped_quantity, upc
-		FROM fba_inventory;`)
-	if err != nil {
-		return fmt.Errorf("failed to query inventory data: %w", err)
-	}
-	defer internal.CloseRows(rows)
-
-	for rows.Next() {
-		var title string
-		var totalQty, fulfillableQty, inboundReceivingQty, inboundShippedQty int
-		var upcNull sql.NullString
-
-		if err := rows.Scan(&title, &totalQty, &fulfillableQty, &inboundReceivingQty, &inboundShippedQty, &upcNull); err != nil {
-			log.Error().Err(err).Msg("failed to scan inventory row")
-			continue
-		}
-
-		upc := ""
-		if upcNull.Valid {
-			upc = upcNull.String
-		}
-
-		if _, err := stmt.ExecContext(app.Ctx, title, totalQty, fulfillableQty, inboundReceivingQty, inboundShippedQty, upc); err != nil {
-			return fmt.Errorf("failed to insert into FTS table: %w", err)
-		}
-	}
-
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error during rows iteration: %w", err)
-	}
-
-	return nil
-}
-
-func populateFTSFromSummaries(app AppCtx, stmt *sql.Stmt, summaries []fba_inventory.InventorySummary, upcMap map[string]string) error {
-	for _, summary := range summaries {
-		var upc string
-		if summary.Asin != nil {
-			upc = upcMap[*summary.Asin]
-		}
-
-		if _, err := stmt.ExecContext(app.Ctx,
-			*summary.ProductName,
-			*summary.TotalQuantity,
-			*summary.InventoryDetails.FulfillableQuantity,
-			*summary.InventoryDetails.InboundReceivingQuantity,
-			*summary.InventoryDetails.InboundShippedQuantity,
-			upc); err != nil {
-			return fmt.Errorf("failed to insert into FTS table: %w", err)
-		}
+// buildFTSTable rebuilds the search index from fba_inventory. Index is external content and kept in sync
+// by triggers on fba_inventory, so this is only needed when forced or after a schema change.
+func buildFTSTable(app AppCtx) error {
+	if !buildInventoryCfg.ForceRebuild {
+		color.Magenta("FTS inventory index already built")
+		return nil
 	}
-	return nil
-}
-
-func buildFTSTable(app AppCtx, summaries []fba_inventory.InventorySummary, upcMap map[string]string) error {
-	cntRow := app.DB.QueryRow(`SELECT COUNT(title) FROM fts_title_quantity;`)
-	var vtCnt int
-	if err := cntRow.Scan(&vtCnt); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			vtCnt = 0
-		} else {
-			return fmt.Errorf("failed to query fts table count: %w", err)
-		}
+	if _, err := app.DB.ExecContext(app.Ctx, `INSERT INTO fts_inventory(fts_inventory) VALUES('rebuild');`); err != nil {
+		return fmt.Errorf("failed to rebuild FTS index: %w", err)
 	}
-
-	if vtCnt == 0 || buildInventoryCfg.ForceRebuild {
-		tx, err := app.DB.BeginTx(app.Ctx, nil)
-		if err != nil {
-			return fmt.Errorf("failed to begin transaction: %w", err)
-		}
-		defer internal.Rollback(tx)
-
-		if _, err := tx.ExecContext(app.Ctx, "DELETE FROM fts_title_quantity;"); err != nil {
-			return fmt.Errorf("failed to clear FTS table: %w", err)
-		}
-
-		stmt, err := tx.PrepareContext(app.Ctx, `INSERT INTO fts_title_quantity
-    (title, total_quantity, fulfillable_quantity, inbound_receiving_quantity, inbound_shipped_quantity, upc)
-    VALUES (?, ?, ?, ?, ?, ?);`)
-		if err != nil {
-			return fmt.Errorf("failed to prepare FTS insert statement: %w", err)
-		}
-		defer internal.CloseStmt(stmt)
-
-		if len(summaries) == 0 {
-			if err := populateFTSFromDatabase(app, stmt); err != nil {
-				return err
-			}
-		} else {
-			if err := populateFTSFromSummaries(app, stmt, summaries, upcMap); err != nil {
-				return err
-			}
-		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Errorf("failed to commit FTS build transaction: %w", err)
-		}
-		color.Green("FTS title quantity table built successfully")
-	} else {
-		color.Magenta("FTS title quantity table already built")
+	if _, err := app.DB.ExecContext(app.Ctx, `INSERT INTO fts_inventory(fts_inventory) VALUES('optimize');`); err != nil {
+		return fmt.Errorf("failed to optimize FTS index: %w", err)
 	}
-
+	color.Green("FTS inventory index built successfully")
 	return nil
 }
 
@@ -381,16 +350,23 @@ func buildInventory(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	summaries, upcMap, err := buildFBAInventoryTable(app)
+	summaries, err := buildFBAInventoryTable(app)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to build FBA inventory table")
 		return
 	}
 
-	if err := buildFTSTable(app, summaries, upcMap); err != nil {
+	if err := buildFTSTable(app); err != nil {
 		log.Error().Err(err).Msg("failed to build FTS table")
 		return
 	}
+
+	if summaries != nil {
+		if err := evaluateInventoryAlerts(app, false); err != nil {
+			log.Error().Err(err).Msg("failed to evaluate inventory alerts")
+			return
+		}
+	}
 }
 
 func queryInventory(cmd *cobra.Command, args []string) {
@@ -415,6 +391,18 @@ func queryInventory(cmd *cobra.Command, args []string) {
 	if queryInventoryCfg.Output != "" {
 		filter.OutputFormat = queryInventoryCfg.Output
 	}
+	exportCfg := queryInventoryCfg.Export
+	if strings.EqualFold(exportCfg.Format, "table") {
+		if exportCfg.File != "" {
+			log.Error().Msg("--file can not be used with --format table")
+			return
+		}
+		filter.OutputFormat = "table"
+	} else if exportCfg.Enabled() {
+		filter.OutputFormat = exportCfg.Format
+	} else if filter.OutputFormat != "table" {
+		exportCfg.Format = filter.OutputFormat
+	}
 
 	rows, err := queryInventoryWithFilter(app, filter)
 	if err != nil {
@@ -447,72 +435,46 @@ func queryInventory(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	switch filter.OutputFormat {
-	case "csv":
-		outputToCSV(rows)
-	case "json":
-		outputToJSON(rows)
-	default:
+	if filter.OutputFormat == "table" {
 		outputToTable(rows, filter)
-	}
-}
-
-func outputToCSV(table []FTSTitleQuantityRow) {
-	fileName := fmt.Sprintf("inventory_%s.csv", time.Now().Format("2006-01-02_15-04-05"))
-	file, err := os.Create(fileName)
-	if err != nil {
-		log.Error().Err(err).Msg("failed to create CSV file")
 		return
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	header := []string{
-		"Title",
-		"Total Quantity",
-		"Fulfillable Quantity",
-		"Inbound Receiving Quantity",
-		"Inbound Shipped Quantity",
-		"UPC",
-	}
-	if err := writer.Write(header); err != nil {
-		log.Error().Err(err).Msg("failed to write CSV header")
-		return
+	if err := writeExport(exportCfg, "inventory", inventorySearchExportTable(rows)); err != nil {
+		log.Error().Err(err).Msg("failed to export inventory")
 	}
+}
 
-	for _, row := range table {
-		record := []string{
+func inventorySearchExportTable(rows []InventorySearchRow) export.Table {
+	table := export.Table{
+		Name: "inventory",
+		Columns: []export.Column{
+			{Name: "Title", Key: "title", Type: export.String},
+			{Name: "Total Quantity", Key: "total_quantity", Type: export.Int},
+			{Name: "Fulfillable Quantity", Key: "fulfillable_quantity", Type: export.Int},
+			{Name: "Inbound Receiving Quantity", Key: "inbound_receiving_quantity", Type: export.Int},
+			{Name: "Inbound Shipped Quantity", Key: "inbound_shipped_quantity", Type: export.Int},
+			{Name: "UPC", Key: "upc", Type: export.String},
+			{Name: "SKU", Key: "sku", Type: export.String},
+			{Name: "ASIN", Key: "asin", Type: export.String},
+			{Name: "FNSKU", Key: "fnsku", Type: export.String},
+			{Name: "Marketplace ID", Key: "marketplace_id", Type: export.String},
+		},
+	}
+	for _, row := range rows {
+		table.Rows = append(table.Rows, []any{
 			row.Title,
-			strconv.Itoa(row.TotalQuantity),
-			strconv.Itoa(row.FulfillableQuantity),
-			strconv.Itoa(row.InboundReceivingQuantity),
-			strconv.Itoa(row.InboundShippedQuantity),
+			row.TotalQuantity,
+			row.FulfillableQuantity,
+			row.InboundReceivingQuantity,
+			row.InboundShippedQuantity,
 			row.UPC,
-		}
-		if err := writer.Write(record); err != nil {
-			log.Error().Err(err).Msg("failed to write CSV row")
-			return
-		}
+			row.SKU,
+			row.ASIN,
+			row.FNSKU,
+			row.MarketplaceID,
+		})
 	}
-
-	successStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#22C55E")).
-		Bold(true)
-
-	fmt.Printf("\n%s\n", successStyle.Render("✅ CSV Export Complete"))
-	fmt.Printf("📄 File: %s\n", fileName)
-	fmt.Printf("📊 Records: %d\n", len(table))
-}
-
-type FTSTitleQuantityRow struct {
-	Title                    string
-	TotalQuantity            int
-	FulfillableQuantity      int
-	InboundReceivingQuantity int
-	InboundShippedQuantity   int
-	UPC                      string
+	return table
 }
 
 func configureInventoryFilter() (*InventoryFilter, error) {
@@ -522,9 +484,9 @@ func configureInventoryFilter() (*InventoryFilter, error) {
 		huh.NewGroup(
 			huh.NewInput().
 				Title("Search Keywords").
-				Description("Enter keywords to search in product titles (use * for wildcard)").
+				Description("Search titles, SKU, ASIN, FNSKU and UPC (prefix matched, column filters like sku:ABC* and OR / NOT supported)").
 				Value(&filter.Keyword).
-				Placeholder("e.g., iPhone, Samsung Galaxy, *phone*"),
+				Placeholder("e.g., iPhone, sku:ABC*, asin:B0 OR upc:0123"),
 
 			huh.NewSelect[string]().
 				Title("Quantity Filter").
@@ -583,6 +545,7 @@ func configureInventoryFilter() (*InventoryFilter, error) {
 				Title("Sort By").
 				Description("How do you want to sort the results?").
 				Options(
+					huh.NewOption("Relevance (best match first)", "relevance"),
 					huh.NewOption("Product Title (A-Z)", "title_asc"),
 					huh.NewOption("Product Title (Z-A)", "title_desc"),
 					huh.NewOption("Total Quantity (Low to High)", "quantity_asc"),
@@ -599,6 +562,9 @@ func configureInventoryFilter() (*InventoryFilter, error) {
 					huh.NewOption("Interactive Table", "table"),
 					huh.NewOption("CSV File", "csv"),
 					huh.NewOption("JSON File", "json"),
+					huh.NewOption("NDJSON File", "ndjson"),
+					huh.NewOption("Excel Workbook", "xlsx"),
+					huh.NewOption("Parquet File", "parquet"),
 				).
 				Value(&filter.OutputFormat),
 
@@ -652,96 +618,7 @@ func configureInventoryFilter() (*InventoryFilter, error) {
 	return filter, nil
 }
 
-func queryInventoryWithFilter(app AppCtx, filter *InventoryFilter) ([]FTSTitleQuantityRow, error) {
-	var query strings.Builder
-	var args []interface{}
-
-	query.WriteString(`SELECT title, total_quantity, fulfillable_quantity, inbound_receiving_quantity, inbound_shipped_quantity, upc
-FROM fts_title_quantity`)
-
-	var conditions []string
-
-	if filter.Keyword != "" {
-		conditions = append(conditions, `rowid IN (
-			SELECT rowid FROM fts_title_quantity WHERE title MATCH ?
-		)`)
-		args = append(args, filter.Keyword)
-	}
-
-	if filter.MinQuantity > 0 || filter.MaxQuantity < 999999 {
-		if filter.MinQuantity == filter.MaxQuantity {
-			conditions = append(conditions, "total_quantity = ?")
-			args = append(args, filter.MinQuantity)
-		} else {
-			if filter.MinQuantity > 0 {
-				conditions = append(conditions, "total_quantity >= ?")
-				args = append(args, filter.MinQuantity)
-			}
-			if filter.MaxQuantity < 999999 {
-				conditions = append(conditions, "total_quantity <= ?")
-				args = append(args, filter.MaxQuantity)
-			}
-		}
-	}
-
-	if len(conditions) > 0 {
-		query.WriteString(" WHERE ")
-		query.WriteString(strings.Join(conditions, " AND "))
-	}
-
-	switch filter.SortBy {
-	case "title_asc":
-		query.WriteString(" ORDER BY title ASC")
-	case "title_desc":
-		query.WriteString(" ORDER BY title DESC")
-	case "quantity_asc":
-		query.WriteString(" ORDER BY total_quantity ASC")
-	case "quantity_desc":
-		query.WriteString(" ORDER BY total_quantity DESC")
-	case "fulfillable_asc":
-		query.WriteString(" ORDER BY fulfillable_quantity ASC")
-	case "fulfillable_desc":
-		query.WriteString(" ORDER BY fulfillable_quantity DESC")
-	default:
-		query.WriteString(" ORDER BY title ASC")
-	}
-
-	rows, err := app.DB.QueryContext(app.Ctx, query.String(), args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query inventory: %w", err)
-	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.Error().Err(err).Msg("failed to close rows")
-		}
-	}()
-
-	var table []FTSTitleQuantityRow
-	for rows.Next() {
-		var row FTSTitleQuantityRow
-		var upcNull sql.NullString
-		if err := rows.Scan(&row.Title,
-			&row.TotalQuantity,
-			&row.FulfillableQuantity,
-			&row.InboundReceivingQuantity,
-			&row.InboundShippedQuantity,
-			&upcNull); err != nil {
-			return nil, fmt.Errorf("failed to scan inventory row: %w", err)
-		}
-
-		if upcNull.Valid {
-			row.UPC = upcNull.String
-		} else {
-			row.UPC = ""
-		}
-
-		table = append(table, row)
-	}
-
-	return table, nil
-}
-
-func showInventoryPreview(rows []FTSTitleQuantityRow, filter *InventoryFilter) {
+func showInventoryPreview(rows []InventorySearchRow, filter *InventoryFilter) {
 	previewStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#3B82F6")).
 		Bold(true).
@@ -761,13 +638,15 @@ func showInventoryPreview(rows []FTSTitleQuantityRow, filter *InventoryFilter) {
 		fmt.Printf("📝 All results:\n\n")
 	}
 
+	plainStyle := lipgloss.NewStyle()
+	matchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true)
 	for i, row := range rows {
-		fmt.Printf("  %d. %s\n", i+1, truncateString(row.Title, 60))
+		fmt.Printf("  %d. %s\n", i+1, renderHighlighted(row.Highlight.Snippet, truncateString(row.Title, 60), plainStyle, matchStyle))
 		upcDisplay := row.UPC
 		if upcDisplay == "" {
 			upcDisplay = "N/A"
 		}
-		fmt.Printf("     Total: %d | Fulfillable: %d | UPC: %s\n", row.TotalQuantity, row.FulfillableQuantity, upcDisplay)
+		fmt.Printf("     SKU: %s | Total: %d | Fulfillable: %d | UPC: %s\n", row.SKU, row.TotalQuantity, row.FulfillableQuantity, upcDisplay)
 		if i < len(rows)-1 {
 			fmt.Println()
 		}
@@ -812,7 +691,7 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-func outputToTable(table []FTSTitleQuantityRow, filter *InventoryFilter) {
+func outputToTable(table []InventorySearchRow, filter *InventoryFilter) {
 	titleStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#22C55E")).
 		Bold(true).
@@ -834,8 +713,23 @@ func outputToTable(table []FTSTitleQuantityRow, filter *InventoryFilter) {
 	divider := dividerStyle.Render("────────────────────────────────────────────────────────────────────────────────")
 	fmt.Println(divider)
 
+	headingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#22C55E")).
+		Bold(true)
+
+	matchStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#F59E0B")).
+		Bold(true).
+		Underline(true)
+
 	for i, row := range table {
-		fmt.Printf("%s\n", titleStyle.Render(fmt.Sprintf("%d. %s", i+1, row.Title)))
+		fmt.Printf("%s%s\n\n", headingStyle.Render(fmt.Sprintf("%d. ", i+1)), renderHighlighted(row.Highlight.Title, row.Title, headingStyle, matchStyle))
+
+		fmt.Printf("   %s %s\n", labelStyle.Render("SKU:"), renderHighlighted(row.Highlight.SKU, row.SKU, valueStyle, matchStyle))
+		fmt.Printf("   %s %s\n", labelStyle.Render("ASIN:"), renderHighlighted(row.Highlight.ASIN, row.ASIN, valueStyle, matchStyle))
+		if row.FNSKU != "" {
+			fmt.Printf("   %s %s\n", labelStyle.Render("FNSKU:"), renderHighlighted(row.Highlight.FNSKU, row.FNSKU, valueStyle, matchStyle))
+		}
 
 		fmt.Printf("   %s %s\n", labelStyle.Render("Total Quantity:"), valueStyle.Render(fmt.Sprintf("%d", row.TotalQuantity)))
 		fmt.Printf("   %s %s\n", labelStyle.Render("Fulfillable:"), valueStyle.Render(fmt.Sprintf("%d", row.FulfillableQuantity)))
@@ -845,7 +739,7 @@ func outputToTable(table []FTSTitleQuantityRow, filter *InventoryFilter) {
 		if upcDisplay == "" {
 			upcDisplay = "N/A"
 		}
-		fmt.Printf("   %s %s\n", labelStyle.Render("UPC:"), valueStyle.Render(upcDisplay))
+		fmt.Printf("   %s %s\n", labelStyle.Render("UPC:"), renderHighlighted(row.Highlight.UPC, upcDisplay, valueStyle, matchStyle))
 
 		if i < len(table)-1 {
 			fmt.Println(divider)
@@ -860,26 +754,3 @@ func outputToTable(table []FTSTitleQuantityRow, filter *InventoryFilter) {
 
 	fmt.Printf("%s\n", summaryStyle.Render(fmt.Sprintf("📋 Total items found: %d", len(table))))
 }
-
-func outputToJSON(table []FTSTitleQuantityRow) {
-	fileName := fmt.Sprintf("inventory_%s.json", time.Now().Format("2006-01-02_15-04-05"))
-
-	jsonData, err := json.MarshalIndent(table, "", "  ")
-	if err != nil {
-		log.Error().Err(err).Msg("failed to marshal JSON")
-		return
-	}
-
-	if err := os.WriteFile(fileName, jsonData, 0644); err != nil {
-		log.Error().Err(err).Msg("failed to write JSON file")
-		return
-	}
-
-	successStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#22C55E")).
-		Bold(true)
-
-	fmt.Printf("\n%s\n", successStyle.Render("✅ JSON Export Complete"))
-	fmt.Printf("📄 File: %s\n", fileName)
-	fmt.Printf("📊 Records: %d\n", len(table))
-}
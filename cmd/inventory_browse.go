@@ -0,0 +1,738 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/catalog"
+	"github.com/caner-cetin/halycon/internal/amazon/listings"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type browseInventoryConfig struct {
+	Keyword  string
+	Quantity int
+	Output   string
+}
+
+var (
+	browseInventoryCmd = &cobra.Command{
+		Use:   "browse",
+		Short: "full screen inventory browser with live search, details and export of selected SKUs",
+		Run:   WrapCommandWithResources(browseInventory, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceCatalog, ServiceListings}}),
+	}
+	browseInventoryCfg browseInventoryConfig
+)
+
+func getBrowseInventoryCmd() *cobra.Command {
+	flags := browseInventoryCmd.PersistentFlags()
+	flags.StringVarP(&browseInventoryCfg.Keyword, "keyword", "k", "", "initial search query")
+	flags.IntVar(&browseInventoryCfg.Quantity, "quantity", 1, "default quantity of selected SKUs in shipment drafts")
+	flags.StringVarP(&browseInventoryCfg.Output, "output", "o", "", "output path of exported drafts (default shipment_draft_<timestamp>.csv / listings_patch_<timestamp>)")
+	return browseInventoryCmd
+}
+
+type browseSortColumn int
+
+const (
+	browseSortRelevance browseSortColumn = iota
+	browseSortTitle
+	browseSortSKU
+	browseSortASIN
+	browseSortTotal
+	browseSortFulfillable
+	browseSortInbound
+)
+
+var browseSortColumnNames = []string{"relevance", "title", "sku", "asin", "total", "fulfillable", "inbound"}
+
+type browseAction int
+
+const (
+	browseActionNone browseAction = iota
+	browseActionShipmentDraft
+	browseActionListingsPatch
+)
+
+// browseDetail is the catalog and listing information of a single SKU, fetched on demand.
+type browseDetail struct {
+	Catalog     *catalog.Item
+	CatalogErr  error
+	Listing     *listings.Item
+	ListingErr  error
+	ProductType string
+}
+
+type browseSearchMsg struct {
+	seq  int
+	rows []InventorySearchRow
+	err  error
+}
+
+type browseDetailMsg struct {
+	sku    string
+	detail browseDetail
+}
+
+type browseModel struct {
+	app   AppCtx
+	input textinput.Model
+
+	rows      []InventorySearchRow
+	searchSeq int
+	searchErr error
+	cursor    int
+	offset    int
+	sortBy    browseSortColumn
+	sortDesc  bool
+
+	// selected maps selected SKUs to their quantity in shipment drafts
+	selected        map[string]int
+	selectedRows    map[string]InventorySearchRow
+	defaultQuantity int
+
+	details map[string]*browseDetail
+	loading map[string]bool
+
+	width  int
+	height int
+	action browseAction
+	status string
+}
+
+func newBrowseModel(app AppCtx, keyword string, defaultQuantity int) browseModel {
+	input := textinput.New()
+	input.Prompt = "🔍 "
+	input.Placeholder = "search title, sku:ABC*, asin:B0..., upc:..."
+	input.SetValue(keyword)
+	input.Focus()
+	return browseModel{
+		app:             app,
+		input:           input,
+		selected:        make(map[string]int),
+		selectedRows:    make(map[string]InventorySearchRow),
+		defaultQuantity: defaultQuantity,
+		details:         make(map[string]*browseDetail),
+		loading:         make(map[string]bool),
+		width:           120,
+		height:          30,
+	}
+}
+
+func (m browseModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.search())
+}
+
+func (m *browseModel) search() tea.Cmd {
+	m.searchSeq++
+	seq := m.searchSeq
+	app := m.app
+	keyword := m.input.Value()
+	return func() tea.Msg {
+		rows, err := queryInventoryWithFilter(app, &InventoryFilter{Keyword: keyword, MaxQuantity: 999999})
+		return browseSearchMsg{seq: seq, rows: rows, err: err}
+	}
+}
+
+func (m *browseModel) fetchDetail(row InventorySearchRow) tea.Cmd {
+	if _, exists := m.details[row.SKU]; exists || m.loading[row.SKU] {
+		return nil
+	}
+	m.loading[row.SKU] = true
+	app := m.app
+	return func() tea.Msg {
+		return browseDetailMsg{sku: row.SKU, detail: fetchBrowseDetail(app, row)}
+	}
+}
+
+func fetchBrowseDetail(app AppCtx, row InventorySearchRow) browseDetail {
+	var detail browseDetail
+	if row.ASIN != "" {
+		var params catalog.GetCatalogItemParams
+		params.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
+		params.IncludedData = &[]catalog.GetCatalogItemParamsIncludedData{"summaries", "attributes", "productTypes"}
+		status, err := app.Amazon.Client.GetCatalogItem(app.Ctx, row.ASIN, &params)
+		if err != nil {
+			detail.CatalogErr = err
+		} else {
+			detail.Catalog = status.JSON200
+		}
+	}
+	var params listings.GetListingsItemParams
+	params.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
+	params.IncludedData = &[]listings.GetListingsItemParamsIncludedData{"summaries", "issues", "fulfillmentAvailability"}
+	params.IssueLocale = internal.Ptr(cfg.Amazon.DefaultLanguageTag)
+	status, err := app.Amazon.Client.GetListingsItem(app.Ctx, &params, cfg.Amazon.Auth.DefaultMerchant.SellerToken, row.SKU)
+	if err != nil {
+		detail.ListingErr = err
+	} else {
+		detail.Listing = status.JSON200
+	}
+	if detail.Listing != nil && detail.Listing.Summaries != nil && len(*detail.Listing.Summaries) > 0 {
+		detail.ProductType = (*detail.Listing.Summaries)[0].ProductType
+	}
+	if detail.ProductType == "" && detail.Catalog != nil && detail.Catalog.ProductTypes != nil {
+		for _, productType := range *detail.Catalog.ProductTypes {
+			if productType.ProductType != nil {
+				detail.ProductType = *productType.ProductType
+				break
+			}
+		}
+	}
+	return detail
+}
+
+func (m *browseModel) sortRows() {
+	if m.sortBy == browseSortRelevance {
+		return
+	}
+	less := func(a, b InventorySearchRow) bool {
+		switch m.sortBy {
+		case browseSortTitle:
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case browseSortSKU:
+			return a.SKU < b.SKU
+		case browseSortASIN:
+			return a.ASIN < b.ASIN
+		case browseSortTotal:
+			return a.TotalQuantity < b.TotalQuantity
+		case browseSortFulfillable:
+			return a.FulfillableQuantity < b.FulfillableQuantity
+		case browseSortInbound:
+			return a.InboundReceivingQuantity+a.InboundShippedQuantity < b.InboundReceivingQuantity+b.InboundShippedQuantity
+		}
+		return false
+	}
+	sort.SliceStable(m.rows, func(i, j int) bool {
+		if m.sortDesc {
+			return less(m.rows[j], m.rows[i])
+		}
+		return less(m.rows[i], m.rows[j])
+	})
+}
+
+func (m *browseModel) tableHeight() int {
+	// title, search, header, divider, footer and status lines
+	return max(m.height-7, 3)
+}
+
+func (m *browseModel) moveCursor(delta int) {
+	if len(m.rows) == 0 {
+		m.cursor, m.offset = 0, 0
+		return
+	}
+	m.cursor = min(max(m.cursor+delta, 0), len(m.rows)-1)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.tableHeight() {
+		m.offset = m.cursor - m.tableHeight() + 1
+	}
+}
+
+func (m *browseModel) currentRow() (InventorySearchRow, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return InventorySearchRow{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+func (m *browseModel) toggleSelection(row InventorySearchRow) {
+	if _, exists := m.selected[row.SKU]; exists {
+		delete(m.selected, row.SKU)
+		delete(m.selectedRows, row.SKU)
+		return
+	}
+	m.selected[row.SKU] = m.defaultQuantity
+	m.selectedRows[row.SKU] = row
+}
+
+func (m browseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.moveCursor(0)
+		return m, nil
+	case browseSearchMsg:
+		// results of outdated queries are dropped, user kept typing
+		if msg.seq != m.searchSeq {
+			return m, nil
+		}
+		m.searchErr = msg.err
+		if msg.err == nil {
+			m.rows = msg.rows
+			m.sortRows()
+		}
+		m.cursor, m.offset = 0, 0
+		return m, nil
+	case browseDetailMsg:
+		delete(m.loading, msg.sku)
+		detail := msg.detail
+		m.details[msg.sku] = &detail
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.action = browseActionNone
+			return m, tea.Quit
+		case "up", "ctrl+p":
+			m.moveCursor(-1)
+			return m, nil
+		case "down", "ctrl+n":
+			m.moveCursor(1)
+			return m, nil
+		case "pgup":
+			m.moveCursor(-m.tableHeight())
+			return m, nil
+		case "pgdown":
+			m.moveCursor(m.tableHeight())
+			return m, nil
+		}
+		if m.input.Focused() {
+			switch msg.String() {
+			case "esc", "tab", "enter":
+				m.input.Blur()
+				if row, ok := m.currentRow(); ok && msg.String() == "enter" {
+					return m, m.fetchDetail(row)
+				}
+				return m, nil
+			}
+			previous := m.input.Value()
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			if m.input.Value() != previous {
+				return m, tea.Batch(cmd, m.search())
+			}
+			return m, cmd
+		}
+		switch msg.String() {
+		case "q", "esc":
+			m.action = browseActionNone
+			return m, tea.Quit
+		case "/", "tab":
+			m.input.Focus()
+			return m, textinput.Blink
+		case "k":
+			m.moveCursor(-1)
+		case "j":
+			m.moveCursor(1)
+		case "g", "home":
+			m.moveCursor(-len(m.rows))
+		case "G", "end":
+			m.moveCursor(len(m.rows))
+		case "enter", "d":
+			if row, ok := m.currentRow(); ok {
+				return m, m.fetchDetail(row)
+			}
+		case " ", "x":
+			if row, ok := m.currentRow(); ok {
+				m.toggleSelection(row)
+				m.moveCursor(1)
+			}
+		case "a":
+			// selects all results, or clears selection if all results are already selected
+			allSelected := len(m.rows) > 0
+			for _, row := range m.rows {
+				if _, exists := m.selected[row.SKU]; !exists {
+					allSelected = false
+					break
+				}
+			}
+			for _, row := range m.rows {
+				_, exists := m.selected[row.SKU]
+				if exists == allSelected {
+					m.toggleSelection(row)
+				}
+			}
+		case "+", "=":
+			if row, ok := m.currentRow(); ok {
+				if _, exists := m.selected[row.SKU]; !exists {
+					m.toggleSelection(row)
+				} else {
+					m.selected[row.SKU]++
+				}
+			}
+		case "-":
+			if row, ok := m.currentRow(); ok {
+				if quantity, exists := m.selected[row.SKU]; exists {
+					if quantity <= 1 {
+						m.toggleSelection(row)
+					} else {
+						m.selected[row.SKU]--
+					}
+				}
+			}
+		case "s":
+			m.sortBy = (m.sortBy + 1) % browseSortColumn(len(browseSortColumnNames))
+			if m.sortBy == browseSortRelevance {
+				m.sortDesc = false
+				return m, m.search()
+			}
+			m.sortRows()
+		case "r":
+			m.sortDesc = !m.sortDesc
+			if m.sortBy == browseSortRelevance {
+				m.sortBy = browseSortTitle
+			}
+			m.sortRows()
+		case "e":
+			if len(m.selected) == 0 {
+				m.status = "select SKUs with space before exporting"
+				return m, nil
+			}
+			m.action = browseActionShipmentDraft
+			return m, tea.Quit
+		case "p":
+			if len(m.selected) == 0 {
+				m.status = "select SKUs with space before exporting"
+				return m, nil
+			}
+			m.action = browseActionListingsPatch
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+var (
+	browseTitleStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED")).Bold(true)
+	browseHeaderStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#06B6D4")).Bold(true)
+	browseCursorStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#374151")).Foreground(lipgloss.Color("#F3F4F6"))
+	browseSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#22C55E"))
+	browseMutedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	browseLabelStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#06B6D4")).Bold(true)
+	browseErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+	browseMatchStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Bold(true)
+	browsePaneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#6B7280")).Padding(0, 1)
+)
+
+func (m browseModel) View() string {
+	tableWidth := m.width * 3 / 5
+	detailWidth := m.width - tableWidth - 4
+
+	var b strings.Builder
+	direction := "▲"
+	if m.sortDesc {
+		direction = "▼"
+	}
+	b.WriteString(browseTitleStyle.Render("📦 Inventory Browser"))
+	b.WriteString(browseMutedStyle.Render(fmt.Sprintf("  %d results · %d selected · sort: %s %s", len(m.rows), len(m.selected), browseSortColumnNames[m.sortBy], direction)))
+	b.WriteString("\n")
+	b.WriteString(m.input.View())
+	b.WriteString("\n")
+
+	table := m.viewTable(tableWidth)
+	detail := browsePaneStyle.Width(detailWidth).Height(m.tableHeight() + 1).MaxHeight(m.tableHeight() + 3).Render(m.viewDetail(detailWidth - 2))
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, table, " ", detail))
+	b.WriteString("\n")
+
+	if m.searchErr != nil {
+		b.WriteString(browseErrorStyle.Render(truncateString(m.searchErr.Error(), m.width)))
+	} else if m.status != "" {
+		b.WriteString(browseErrorStyle.Render(m.status))
+	}
+	b.WriteString("\n")
+	if m.input.Focused() {
+		b.WriteString(browseMutedStyle.Render("type to search · ↑/↓ move · enter details · esc/tab leave search · ctrl+c quit"))
+	} else {
+		b.WriteString(browseMutedStyle.Render("j/k move · space select · a all · +/- qty · enter details · s sort · r reverse · / search · e shipment draft · p listings patch · q quit"))
+	}
+	return b.String()
+}
+
+func (m browseModel) viewTable(width int) string {
+	const (
+		skuWidth  = 18
+		asinWidth = 10
+		numWidth  = 6
+	)
+	titleWidth := max(width-skuWidth-asinWidth-4*numWidth-9, 10)
+	format := fmt.Sprintf("%%-2s %%-%ds %%-%ds %%-%ds %%%dd %%%dd %%%dd %%%ds", skuWidth, asinWidth, titleWidth, numWidth, numWidth, numWidth, numWidth)
+	headerFormat := fmt.Sprintf("%%-2s %%-%ds %%-%ds %%-%ds %%%ds %%%ds %%%ds %%%ds", skuWidth, asinWidth, titleWidth, numWidth, numWidth, numWidth, numWidth)
+
+	var lines []string
+	lines = append(lines, browseHeaderStyle.Render(fmt.Sprintf(headerFormat, "", "SKU", "ASIN", "Title", "Total", "Fulfil", "Inbnd", "Qty")))
+	lines = append(lines, browseMutedStyle.Render(strings.Repeat("─", width)))
+	end := min(m.offset+m.tableHeight(), len(m.rows))
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		mark, qty := "", ""
+		quantity, selected := m.selected[row.SKU]
+		if selected {
+			mark, qty = "●", fmt.Sprintf("%d", quantity)
+		}
+		line := fmt.Sprintf(format, mark, truncateString(row.SKU, skuWidth), truncateString(row.ASIN, asinWidth), truncateString(row.Title, titleWidth),
+			row.TotalQuantity, row.FulfillableQuantity, row.InboundReceivingQuantity+row.InboundShippedQuantity, qty)
+		switch {
+		case i == m.cursor:
+			line = browseCursorStyle.Render(line)
+		case selected:
+			line = browseSelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(m.rows) == 0 {
+		lines = append(lines, browseMutedStyle.Render("no matching inventory items, is inventory built?"))
+	}
+	for len(lines) < m.tableHeight()+2 {
+		lines = append(lines, "")
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+func (m browseModel) viewDetail(width int) string {
+	row, ok := m.currentRow()
+	if !ok {
+		return browseMutedStyle.Render("no item")
+	}
+	var lines []string
+	field := func(label string, value string) {
+		if value != "" {
+			lines = append(lines, browseLabelStyle.Render(label+": ")+value)
+		}
+	}
+	lines = append(lines, renderHighlighted(row.Highlight.Title, row.Title, lipgloss.NewStyle().Bold(true), browseMatchStyle))
+	if row.Highlight.Snippet != "" && row.Highlight.Snippet != row.Highlight.Title {
+		field("Match", renderHighlighted(row.Highlight.Snippet, "", lipgloss.NewStyle(), browseMatchStyle))
+	}
+	field("SKU", row.SKU)
+	field("ASIN", row.ASIN)
+	field("FNSKU", row.FNSKU)
+	field("UPC", row.UPC)
+	field("Marketplace", row.MarketplaceID)
+	lines = append(lines, browseLabelStyle.Render("Quantity: ")+fmt.Sprintf("total %d · fulfillable %d · receiving %d · shipped %d",
+		row.TotalQuantity, row.FulfillableQuantity, row.InboundReceivingQuantity, row.InboundShippedQuantity))
+	lines = append(lines, "")
+
+	detail, fetched := m.details[row.SKU]
+	switch {
+	case m.loading[row.SKU]:
+		lines = append(lines, browseMutedStyle.Render("loading catalog and listing..."))
+	case !fetched:
+		lines = append(lines, browseMutedStyle.Render("press enter to load catalog attributes and listing status"))
+	default:
+		lines = append(lines, browseTitleStyle.Render("Listing"))
+		if detail.ListingErr != nil {
+			lines = append(lines, browseErrorStyle.Render(truncateString(detail.ListingErr.Error(), width*2)))
+		} else if detail.Listing != nil {
+			field("Product Type", detail.ProductType)
+			if detail.Listing.Summaries != nil {
+				for _, summary := range *detail.Listing.Summaries {
+					var statuses []string
+					for _, status := range summary.Status {
+						statuses = append(statuses, string(status))
+					}
+					if len(statuses) == 0 {
+						statuses = append(statuses, "INACTIVE")
+					}
+					field("Status", fmt.Sprintf("%s (%s)", strings.Join(statuses, ", "), summary.MarketplaceId))
+				}
+			}
+			if detail.Listing.FulfillmentAvailability != nil {
+				for _, availability := range *detail.Listing.FulfillmentAvailability {
+					quantity := "-"
+					if availability.Quantity != nil {
+						quantity = fmt.Sprintf("%d", *availability.Quantity)
+					}
+					field("Fulfillment", fmt.Sprintf("%s (%s)", availability.FulfillmentChannelCode, quantity))
+				}
+			}
+			if detail.Listing.Issues != nil && len(*detail.Listing.Issues) > 0 {
+				lines = append(lines, browseErrorStyle.Render(fmt.Sprintf("%d issue(s):", len(*detail.Listing.Issues))))
+				for i, issue := range *detail.Listing.Issues {
+					if i == 3 {
+						lines = append(lines, browseMutedStyle.Render("..."))
+						break
+					}
+					lines = append(lines, fmt.Sprintf("  %s %s", string(issue.Severity), truncateString(issue.Message, width-4)))
+				}
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, browseTitleStyle.Render("Catalog"))
+		if detail.CatalogErr != nil {
+			lines = append(lines, browseErrorStyle.Render(truncateString(detail.CatalogErr.Error(), width*2)))
+		} else if detail.Catalog != nil {
+			if detail.Catalog.Summaries != nil {
+				for _, summary := range *detail.Catalog.Summaries {
+					if summary.BrandName != nil {
+						field("Brand", *summary.BrandName)
+					}
+				}
+			}
+			if detail.Catalog.Attributes != nil {
+				keys := make([]string, 0, len(*detail.Catalog.Attributes))
+				for key := range *detail.Catalog.Attributes {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					field(key, truncateString(catalogAttributeValue((*detail.Catalog.Attributes)[key]), max(width-len(key)-2, 10)))
+				}
+			}
+		}
+	}
+
+	maxLines := m.tableHeight() + 1
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines-1], browseMutedStyle.Render("..."))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// catalogAttributeValue flattens the first entry of a catalog attribute (`[{"value": ..., "marketplace_id": ...}]`) for display.
+func catalogAttributeValue(attribute interface{}) string {
+	entries, ok := attribute.([]interface{})
+	if !ok || len(entries) == 0 {
+		return fmt.Sprintf("%v", attribute)
+	}
+	entry, ok := entries[0].(map[string]interface{})
+	if !ok {
+		return fmt.Sprintf("%v", entries[0])
+	}
+	value := ""
+	if v, exists := entry["value"]; exists {
+		value = fmt.Sprintf("%v", v)
+	} else {
+		encoded, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Sprintf("%v", entry)
+		}
+		value = string(encoded)
+	}
+	if unit, exists := entry["unit"]; exists {
+		value += fmt.Sprintf(" %v", unit)
+	}
+	if len(entries) > 1 {
+		value += fmt.Sprintf(" (+%d)", len(entries)-1)
+	}
+	return value
+}
+
+func browseInventory(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+
+	// logs would be drawn over the alternate screen, errors are displayed inside the browser instead
+	logger := log.Logger
+	log.Logger = zerolog.Nop()
+	program := tea.NewProgram(newBrowseModel(app, browseInventoryCfg.Keyword, max(browseInventoryCfg.Quantity, 1)), tea.WithAltScreen())
+	final, err := program.Run()
+	log.Logger = logger
+	if err != nil {
+		log.Error().Err(err).Msg("failed to run inventory browser")
+		return
+	}
+	model := final.(browseModel)
+
+	skus := make([]string, 0, len(model.selected))
+	for sku := range model.selected {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+
+	switch model.action {
+	case browseActionShipmentDraft:
+		output := browseInventoryCfg.Output
+		if output == "" {
+			output = fmt.Sprintf("shipment_draft_%s.csv", time.Now().Format("2006-01-02_15-04-05"))
+		}
+		rows := make([]ShipmentInputRow, 0, len(skus))
+		for _, sku := range skus {
+			row := model.selectedRows[sku]
+			rows = append(rows, ShipmentInputRow{ASIN: row.ASIN, SKU: sku, Title: row.Title, Quantity: model.selected[sku]})
+		}
+		if err := writeShipmentInputCSV(output, rows); err != nil {
+			log.Error().Err(err).Str("file", output).Msg("failed to write shipment draft")
+			return
+		}
+		fmt.Printf("%s %s (%d SKUs), use with: halycon shipment create -i %s\n", color.GreenString("Shipment draft:"), output, len(rows), output)
+	case browseActionListingsPatch:
+		output := browseInventoryCfg.Output
+		if output == "" {
+			output = fmt.Sprintf("listings_patch_%s", time.Now().Format("2006-01-02_15-04-05"))
+		}
+		if err := os.MkdirAll(output, 0755); err != nil {
+			log.Error().Err(err).Str("dir", output).Msg("failed to create listings patch directory")
+			return
+		}
+		fmt.Printf("%s %s (%d SKUs), edit patches and apply with:\n", color.GreenString("Listings patch drafts:"), output, len(skus))
+		for _, sku := range skus {
+			row := model.selectedRows[sku]
+			detail, fetched := model.details[sku]
+			if !fetched {
+				fetchedDetail := fetchBrowseDetail(app, row)
+				detail = &fetchedDetail
+			}
+			path := filepath.Join(output, sanitizeFileName(sku)+".json")
+			if err := writeListingsPatchDraft(path, row, detail.ProductType); err != nil {
+				log.Error().Err(err).Str("sku", sku).Msg("failed to write listings patch draft")
+				return
+			}
+			if detail.ProductType == "" {
+				log.Warn().Str("sku", sku).Str("file", path).Msg("could not determine product type, fill productType before patching")
+			}
+			// file names are sanitized, so the path is printed instead of being derived from the SKU
+			fmt.Printf("  halycon listings patch --sku %s -i %s\n", sku, path)
+		}
+	}
+}
+
+// writeListingsPatchDraft writes a patch file in `listings patch --input` format, replacing item name
+// with the current title as a starting point for editing.
+func writeListingsPatchDraft(path string, row InventorySearchRow, productType string) error {
+	if productType == "" {
+		productType = "PRODUCT_TYPE"
+	}
+	marketplaceID := row.MarketplaceID
+	if marketplaceID == "" {
+		marketplaceID = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID[0]
+	}
+	draft := map[string]interface{}{
+		"sku":         row.SKU,
+		"productType": productType,
+		"patches": []map[string]interface{}{
+			{
+				"op":   "replace",
+				"path": "/attributes/item_name",
+				"value": []map[string]interface{}{
+					{"value": row.Title, "language_tag": cfg.Amazon.DefaultLanguageTag, "marketplace_id": marketplaceID},
+				},
+			},
+		},
+	}
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch draft: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write patch draft: %w", err)
+	}
+	return nil
+}
+
+// sanitizeFileName replaces path separators and other characters that are not safe in file names.
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
+)
+
+// markers highlight() and snippet() wrap matched terms with, rendered with lipgloss afterwards
+const (
+	highlightOpen  = "\x02"
+	highlightClose = "\x03"
+)
+
+// searchColumns maps column filters accepted in search queries (`sku:ABC*`) to fts_inventory columns.
+var searchColumns = map[string]string{
+	"title":          "title",
+	"sku":            "sku",
+	"asin":           "asin",
+	"fnsku":          "fnsku",
+	"upc":            "upc",
+	"marketplace":    "marketplace_id",
+	"marketplace_id": "marketplace_id",
+}
+
+// InventorySearchRow is a single fba_inventory row matched by a search.
+type InventorySearchRow struct {
+	Title                    string
+	TotalQuantity            int
+	FulfillableQuantity      int
+	InboundReceivingQuantity int
+	InboundShippedQuantity   int
+	UPC                      string
+	SKU                      string
+	ASIN                     string
+	FNSKU                    string
+	MarketplaceID            string
+	Highlight                InventorySearchHighlight `json:"-"`
+}
+
+// InventorySearchHighlight holds the highlight() and snippet() output of a matched row,
+// empty if inventory is listed without a keyword.
+type InventorySearchHighlight struct {
+	Title   string
+	SKU     string
+	ASIN    string
+	FNSKU   string
+	UPC     string
+	Snippet string
+}
+
+type searchTerm struct {
+	// Operator is AND, OR or NOT, if set rest of the fields are empty.
+	Operator string
+	Column   string
+	Value    string
+	Prefix   bool
+}
+
+// parseSearchTerms splits keyword into terms, accepting column filters (`sku:ABC`), quoted phrases,
+// trailing `*` and AND/OR/NOT operators. Leading `*` is dropped, FTS5 only supports prefix queries,
+// and so are operators that are not between two terms.
+func parseSearchTerms(keyword string) []searchTerm {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range keyword {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	terms := make([]searchTerm, 0, len(tokens))
+	for _, token := range tokens {
+		if token == "AND" || token == "OR" || token == "NOT" {
+			terms = append(terms, searchTerm{Operator: token})
+			continue
+		}
+		var term searchTerm
+		if column, value, found := strings.Cut(token, ":"); found {
+			if mapped, exists := searchColumns[strings.ToLower(column)]; exists {
+				term.Column = mapped
+				token = value
+			}
+		}
+		token = strings.TrimLeft(token, "*")
+		quoted := strings.HasPrefix(token, `"`)
+		if strings.HasSuffix(token, "*") {
+			term.Prefix = true
+			token = strings.TrimRight(token, "*")
+		} else if !quoted {
+			// unquoted terms are matched as prefixes so partially typed SKUs and words match
+			term.Prefix = true
+		}
+		term.Value = strings.Trim(token, `"`)
+		if term.Value == "" {
+			continue
+		}
+		terms = append(terms, term)
+	}
+
+	// FTS5 operators need a term on both sides, drop the ones that do not have one (`OR`, `foo OR`, `NOT foo`)
+	// and keep the last of consecutive operators, so `foo AND NOT bar` is searched as `foo NOT bar`
+	operands := terms[:0]
+	for _, term := range terms {
+		if term.Operator != "" {
+			if len(operands) == 0 {
+				continue
+			}
+			if operands[len(operands)-1].Operator != "" {
+				operands[len(operands)-1] = term
+				continue
+			}
+		}
+		operands = append(operands, term)
+	}
+	if len(operands) > 0 && operands[len(operands)-1].Operator != "" {
+		operands = operands[:len(operands)-1]
+	}
+	return operands
+}
+
+func quoteFTSString(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+// buildFTSMatchQuery renders terms as an FTS5 MATCH expression. alternatives replaces the value of
+// the term at the same index with an OR group, used for typo tolerant matching.
+func buildFTSMatchQuery(terms []searchTerm, alternatives map[int][]string) string {
+	parts := make([]string, 0, len(terms))
+	previousIsTerm := false
+	for i, term := range terms {
+		if term.Operator != "" {
+			parts = append(parts, term.Operator)
+			previousIsTerm = false
+			continue
+		}
+		// implicit AND is not accepted after parenthesized groups
+		if previousIsTerm {
+			parts = append(parts, "AND")
+		}
+		previousIsTerm = true
+		values := []string{term.Value}
+		if alts, exists := alternatives[i]; exists {
+			values = alts
+		}
+		quoted := make([]string, 0, len(values))
+		for _, value := range values {
+			q := quoteFTSString(value)
+			if term.Prefix {
+				q += "*"
+			}
+			quoted = append(quoted, q)
+		}
+		expr := quoted[0]
+		if len(quoted) > 1 {
+			expr = "(" + strings.Join(quoted, " OR ") + ")"
+		}
+		if term.Column != "" {
+			expr = term.Column + ":" + expr
+		}
+		parts = append(parts, expr)
+	}
+	return strings.Join(parts, " ")
+}
+
+// fuzzySearchAlternatives finds indexed terms within a small edit distance of each search term,
+// comparing against both whole terms and their prefixes. Returns nil if nothing close is found.
+func fuzzySearchAlternatives(app AppCtx, terms []searchTerm) (map[int][]string, error) {
+	rows, err := app.DB.QueryContext(app.Ctx, `SELECT term FROM fts_inventory_vocab;`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search vocabulary: %w", err)
+	}
+	defer internal.CloseRows(rows)
+	var vocabulary []string
+	for rows.Next() {
+		var term string
+		if err := rows.Scan(&term); err != nil {
+			return nil, fmt.Errorf("failed to scan search vocabulary: %w", err)
+		}
+		vocabulary = append(vocabulary, term)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", err)
+	}
+
+	alternatives := make(map[int][]string)
+	for i, term := range terms {
+		value := []rune(strings.ToLower(term.Value))
+		if term.Operator != "" || len(value) < 3 || strings.ContainsFunc(term.Value, unicode.IsSpace) {
+			continue
+		}
+		maxDistance := 1
+		if len(value) > 5 {
+			maxDistance = 2
+		}
+		type candidate struct {
+			term     string
+			distance int
+		}
+		var candidates []candidate
+		for _, vocab := range vocabulary {
+			v := []rune(vocab)
+			distance := levenshtein(value, v)
+			if term.Prefix && len(v) > len(value) {
+				distance = min(distance, levenshtein(value, v[:len(value)]))
+			}
+			if distance > 0 && distance <= maxDistance {
+				candidates = append(candidates, candidate{term: vocab, distance: distance})
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].distance < candidates[b].distance })
+		if len(candidates) > 10 {
+			candidates = candidates[:10]
+		}
+		alts := []string{term.Value}
+		for _, c := range candidates {
+			if !slices.Contains(alts, c.term) {
+				alts = append(alts, c.term)
+			}
+		}
+		alternatives[i] = alts
+	}
+	if len(alternatives) == 0 {
+		return nil, nil
+	}
+	return alternatives, nil
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func queryInventoryWithFilter(app AppCtx, filter *InventoryFilter) ([]InventorySearchRow, error) {
+	if filter.Keyword == "" {
+		return searchInventory(app, filter, "")
+	}
+	terms := parseSearchTerms(filter.Keyword)
+	if len(terms) == 0 {
+		return searchInventory(app, filter, "")
+	}
+	table, err := searchInventory(app, filter, buildFTSMatchQuery(terms, nil))
+	if err != nil || len(table) > 0 {
+		return table, err
+	}
+
+	alternatives, err := fuzzySearchAlternatives(app, terms)
+	if err != nil {
+		return nil, err
+	}
+	if alternatives == nil {
+		return table, nil
+	}
+	match := buildFTSMatchQuery(terms, alternatives)
+	log.Info().Str("query", match).Msg("no exact matches, showing similar terms")
+	return searchInventory(app, filter, match)
+}
+
+// searchInventory lists fba_inventory rows matching the FTS5 expression, ranked with bm25,
+// or all rows if match is empty.
+func searchInventory(app AppCtx, filter *InventoryFilter, match string) ([]InventorySearchRow, error) {
+	var query strings.Builder
+	var args []interface{}
+
+	query.WriteString(`SELECT COALESCE(i.title, ''), COALESCE(i.total_quantity, 0), COALESCE(i.fulfillable_quantity, 0),
+	COALESCE(i.inbound_receiving_quantity, 0), COALESCE(i.inbound_shipped_quantity, 0), COALESCE(i.upc, ''),
+	COALESCE(i.sku, ''), COALESCE(i.asin, ''), COALESCE(i.fnsku, ''), COALESCE(i.marketplace_id, ''),`)
+	if match != "" {
+		query.WriteString(`
+	COALESCE(highlight(fts_inventory, 0, char(2), char(3)), ''),
+	COALESCE(highlight(fts_inventory, 1, char(2), char(3)), ''),
+	COALESCE(highlight(fts_inventory, 2, char(2), char(3)), ''),
+	COALESCE(highlight(fts_inventory, 3, char(2), char(3)), ''),
+	COALESCE(highlight(fts_inventory, 4, char(2), char(3)), ''),
+	COALESCE(snippet(fts_inventory, -1, char(2), char(3), '…', 10), '')
+FROM fts_inventory
+JOIN fba_inventory i ON i.id = fts_inventory.rowid`)
+	} else {
+		query.WriteString(`
+	'', '', '', '', '', ''
+FROM fba_inventory i`)
+	}
+
+	var conditions []string
+	if match != "" {
+		conditions = append(conditions, "fts_inventory MATCH ?")
+		args = append(args, match)
+	}
+
+	if filter.MinQuantity > 0 || filter.MaxQuantity < 999999 {
+		if filter.MinQuantity == filter.MaxQuantity {
+			conditions = append(conditions, "i.total_quantity = ?")
+			args = append(args, filter.MinQuantity)
+		} else {
+			if filter.MinQuantity > 0 {
+				conditions = append(conditions, "i.total_quantity >= ?")
+				args = append(args, filter.MinQuantity)
+			}
+			if filter.MaxQuantity < 999999 {
+				conditions = append(conditions, "i.total_quantity <= ?")
+				args = append(args, filter.MaxQuantity)
+			}
+		}
+	}
+
+	if len(conditions) > 0 {
+		query.WriteString(" WHERE ")
+		query.WriteString(strings.Join(conditions, " AND "))
+	}
+
+	switch filter.SortBy {
+	case "title_asc":
+		query.WriteString(" ORDER BY i.title ASC")
+	case "title_desc":
+		query.WriteString(" ORDER BY i.title DESC")
+	case "quantity_asc":
+		query.WriteString(" ORDER BY i.total_quantity ASC")
+	case "quantity_desc":
+		query.WriteString(" ORDER BY i.total_quantity DESC")
+	case "fulfillable_asc":
+		query.WriteString(" ORDER BY i.fulfillable_quantity ASC")
+	case "fulfillable_desc":
+		query.WriteString(" ORDER BY i.fulfillable_quantity DESC")
+	default:
+		// rank is bm25 with column weights configured in the migration
+		if match != "" {
+			query.WriteString(" ORDER BY fts_inventory.rank")
+		} else {
+			query.WriteString(" ORDER BY i.title ASC")
+		}
+	}
+
+	rows, err := app.DB.QueryContext(app.Ctx, query.String(), args...)
+	if err != nil {
+		return nil, wrapSearchError(filter, match, fmt.Errorf("failed to query inventory: %w", err))
+	}
+	defer internal.CloseRows(rows)
+
+	var table []InventorySearchRow
+	for rows.Next() {
+		var row InventorySearchRow
+		if err := rows.Scan(&row.Title,
+			&row.TotalQuantity,
+			&row.FulfillableQuantity,
+			&row.InboundReceivingQuantity,
+			&row.InboundShippedQuantity,
+			&row.UPC,
+			&row.SKU,
+			&row.ASIN,
+			&row.FNSKU,
+			&row.MarketplaceID,
+			&row.Highlight.Title,
+			&row.Highlight.SKU,
+			&row.Highlight.ASIN,
+			&row.Highlight.FNSKU,
+			&row.Highlight.UPC,
+			&row.Highlight.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory row: %w", err)
+		}
+		table = append(table, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, wrapSearchError(filter, match, fmt.Errorf("error during rows iteration: %w", err))
+	}
+
+	return table, nil
+}
+
+// wrapSearchError points at the search query if FTS5 failed to parse it.
+func wrapSearchError(filter *InventoryFilter, match string, err error) error {
+	if match != "" && strings.Contains(err.Error(), "fts5") {
+		return fmt.Errorf("invalid search query %q (%s): %w", filter.Keyword, match, err)
+	}
+	return err
+}
+
+// renderHighlighted renders the highlight() / snippet() output with matched terms in match style,
+// falls back to plain if there is no highlight for the column.
+func renderHighlighted(highlighted string, plain string, base lipgloss.Style, match lipgloss.Style) string {
+	if highlighted == "" {
+		return base.Render(plain)
+	}
+	var b strings.Builder
+	rest := highlighted
+	for rest != "" {
+		start := strings.Index(rest, highlightOpen)
+		if start == -1 {
+			b.WriteString(base.Render(rest))
+			break
+		}
+		if start > 0 {
+			b.WriteString(base.Render(rest[:start]))
+		}
+		rest = rest[start+len(highlightOpen):]
+		end := strings.Index(rest, highlightClose)
+		if end == -1 {
+			end = len(rest)
+		}
+		b.WriteString(match.Render(rest[:end]))
+		rest = rest[min(end+len(highlightClose), len(rest)):]
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSearchTerms(t *testing.T) {
+	tests := []struct {
+		keyword string
+		want    []searchTerm
+	}{
+		{
+			keyword: "iph case",
+			want:    []searchTerm{{Value: "iph", Prefix: true}, {Value: "case", Prefix: true}},
+		},
+		{
+			keyword: "sku:ABC* ASIN:B0 marketplace:ATVPDKIKX0DER",
+			want: []searchTerm{
+				{Column: "sku", Value: "ABC", Prefix: true},
+				{Column: "asin", Value: "B0", Prefix: true},
+				{Column: "marketplace_id", Value: "ATVPDKIKX0DER", Prefix: true},
+			},
+		},
+		{
+			keyword: "color:red",
+			want:    []searchTerm{{Value: "color:red", Prefix: true}},
+		},
+		{
+			keyword: `"usb-c"   "fast charger"* title:"wall plug"`,
+			want: []searchTerm{
+				{Value: "usb-c"},
+				{Value: "fast charger", Prefix: true},
+				{Column: "title", Value: "wall plug"},
+			},
+		},
+		{
+			keyword: "**case sku:* \"\"",
+			want:    []searchTerm{{Value: "case", Prefix: true}},
+		},
+		{
+			keyword: "charger OR cable NOT sku:OLD*",
+			want: []searchTerm{
+				{Value: "charger", Prefix: true},
+				{Operator: "OR"},
+				{Value: "cable", Prefix: true},
+				{Operator: "NOT"},
+				{Column: "sku", Value: "OLD", Prefix: true},
+			},
+		},
+		{
+			keyword: "or and",
+			want:    []searchTerm{{Value: "or", Prefix: true}, {Value: "and", Prefix: true}},
+		},
+		{
+			keyword: "OR",
+			want:    []searchTerm{},
+		},
+		{
+			keyword: "foo OR",
+			want:    []searchTerm{{Value: "foo", Prefix: true}},
+		},
+		{
+			keyword: "NOT foo",
+			want:    []searchTerm{{Value: "foo", Prefix: true}},
+		},
+		{
+			keyword: "foo AND AND bar",
+			want:    []searchTerm{{Value: "foo", Prefix: true}, {Operator: "AND"}, {Value: "bar", Prefix: true}},
+		},
+		{
+			keyword: "foo AND NOT bar",
+			want:    []searchTerm{{Value: "foo", Prefix: true}, {Operator: "NOT"}, {Value: "bar", Prefix: true}},
+		},
+		{
+			keyword: "foo OR sku: OR bar NOT",
+			want:    []searchTerm{{Value: "foo", Prefix: true}, {Operator: "OR"}, {Value: "bar", Prefix: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			if got := parseSearchTerms(tt.keyword); !slices.Equal(got, tt.want) {
+				t.Errorf("parseSearchTerms(%q) = %+v, want %+v", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFTSMatchQuery(t *testing.T) {
+	tests := []struct {
+		keyword      string
+		alternatives map[int][]string
+		want         string
+	}{
+		{
+			keyword: "iph case",
+			want:    `"iph"* AND "case"*`,
+		},
+		{
+			keyword: `sku:ABC* "usb-c" title:"wall plug"`,
+			want:    `sku:"ABC"* AND "usb-c" AND title:"wall plug"`,
+		},
+		{
+			keyword: `say "hi"`,
+			want:    `"say"* AND "hi"`,
+		},
+		{
+			keyword: `12"`,
+			want:    `"12"*`,
+		},
+		{
+			keyword: `a"b`,
+			want:    `"a""b"*`,
+		},
+		{
+			keyword: "charger OR cable NOT sku:OLD*",
+			want:    `"charger"* OR "cable"* NOT sku:"OLD"*`,
+		},
+		{
+			keyword: "OR foo AND AND bar NOT",
+			want:    `"foo"* AND "bar"*`,
+		},
+		{
+			keyword: "NOT",
+			want:    "",
+		},
+		{
+			keyword:      "iphnoe case",
+			alternatives: map[int][]string{0: {"iphone", "iphones"}},
+			want:         `("iphone"* OR "iphones"*) AND "case"*`,
+		},
+		{
+			keyword:      `case OR sku:"ABX-1"`,
+			alternatives: map[int][]string{2: {"abc-1", "abd-1"}},
+			want:         `"case"* OR sku:("abc-1" OR "abd-1")`,
+		},
+		{
+			keyword:      "chargr cable",
+			alternatives: map[int][]string{0: {"charger"}},
+			want:         `"charger"* AND "cable"*`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keyword, func(t *testing.T) {
+			if got := buildFTSMatchQuery(parseSearchTerms(tt.keyword), tt.alternatives); got != tt.want {
+				t.Errorf("buildFTSMatchQuery(%q) = %s, want %s", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
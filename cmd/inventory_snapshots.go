@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type inventorySnapshotsConfig struct {
+	Export exportConfig
+}
+
+type inventorySnapshotsDiffConfig struct {
+	From    string
+	To      string
+	ShowAll bool
+	Export  exportConfig
+}
+
+// InventorySnapshotDiff is the change of a single SKU between two snapshots.
+type InventorySnapshotDiff struct {
+	SKU   string
+	ASIN  string
+	Title string
+	// Status is one of added, removed, changed or unchanged.
+	Status string
+	From   InventorySnapshotQuantities
+	To     InventorySnapshotQuantities
+}
+
+type InventorySnapshotQuantities struct {
+	Total         int
+	Fulfillable   int
+	Inbound       int
+	Reserved      int
+	Unfulfillable int
+}
+
+const snapshotTimeLayout = "2006-01-02 15:04:05"
+
+var (
+	inventorySnapshotsCmd = &cobra.Command{
+		Use:   "snapshots",
+		Short: "lists recorded inventory snapshots",
+		Run:   WrapCommandWithResources(listInventorySnapshots, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+	inventorySnapshotsCfg     inventorySnapshotsConfig
+	inventorySnapshotsDiffCmd = &cobra.Command{
+		Use:   "diff",
+		Short: "compares quantities of every SKU between two inventory snapshots",
+		Run:   WrapCommandWithResources(diffInventorySnapshots, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+	inventorySnapshotsDiffCfg inventorySnapshotsDiffConfig
+)
+
+func getInventorySnapshotsCmd() *cobra.Command {
+	addExportFlags(inventorySnapshotsCmd.Flags(), &inventorySnapshotsCfg.Export, "inventory_snapshots")
+
+	flags := inventorySnapshotsDiffCmd.Flags()
+	flags.StringVar(&inventorySnapshotsDiffCfg.From, "from", "1", "older snapshot, either an index from `inventory snapshots` (0 is the latest) or a time, latest snapshot at or before the time is used")
+	flags.StringVar(&inventorySnapshotsDiffCfg.To, "to", "0", "newer snapshot, same format as --from")
+	flags.BoolVar(&inventorySnapshotsDiffCfg.ShowAll, "all", false, "also include SKUs with no changes")
+	addExportFlags(flags, &inventorySnapshotsDiffCfg.Export, "inventory_diff")
+	inventorySnapshotsCmd.AddCommand(inventorySnapshotsDiffCmd)
+	return inventorySnapshotsCmd
+}
+
+func listInventorySnapshots(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	snapshots, err := app.Query.ListFBAInventorySnapshots(app.Ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list inventory snapshots")
+		return
+	}
+	if len(snapshots) == 0 {
+		log.Error().Msg("no inventory snapshots found, run `inventory build --force-rebuild` periodically to record history")
+		return
+	}
+
+	fmt.Printf("%-6s %-20s %8s %10s %12s\n", "Index", "Snapshot", "SKUs", "Total", "Fulfillable")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------"))
+	for i, snapshot := range snapshots {
+		fmt.Printf("%-6d %-20s %8d %10d %12d\n", i, snapshot.SnapshotAt.Local().Format(snapshotTimeLayout), snapshot.SkuCount, snapshot.TotalQuantity, snapshot.FulfillableQuantity)
+	}
+
+	if inventorySnapshotsCfg.Export.Enabled() {
+		table := export.Table{
+			Name: "snapshots",
+			Columns: []export.Column{
+				{Name: "Index", Key: "index", Type: export.Int},
+				{Name: "Snapshot At", Key: "snapshot_at", Type: export.Time},
+				{Name: "SKU Count", Key: "sku_count", Type: export.Int},
+				{Name: "Total Quantity", Key: "total_quantity", Type: export.Int},
+				{Name: "Fulfillable Quantity", Key: "fulfillable_quantity", Type: export.Int},
+			},
+		}
+		for i, snapshot := range snapshots {
+			table.Rows = append(table.Rows, []any{i, snapshot.SnapshotAt, snapshot.SkuCount, snapshot.TotalQuantity, snapshot.FulfillableQuantity})
+		}
+		if err := writeExport(inventorySnapshotsCfg.Export, "inventory_snapshots", table); err != nil {
+			log.Error().Err(err).Msg("failed to export inventory snapshots")
+		}
+	}
+}
+
+func diffInventorySnapshots(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	snapshots, err := app.Query.ListFBAInventorySnapshots(app.Ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list inventory snapshots")
+		return
+	}
+	if len(snapshots) < 2 {
+		log.Error().Int("snapshots", len(snapshots)).Msg("at least two inventory snapshots are required, run `inventory build --force-rebuild` periodically to record history")
+		return
+	}
+	from, err := resolveInventorySnapshot(snapshots, inventorySnapshotsDiffCfg.From)
+	if err != nil {
+		log.Error().Err(err).Str("from", inventorySnapshotsDiffCfg.From).Msg("failed to resolve snapshot")
+		return
+	}
+	to, err := resolveInventorySnapshot(snapshots, inventorySnapshotsDiffCfg.To)
+	if err != nil {
+		log.Error().Err(err).Str("to", inventorySnapshotsDiffCfg.To).Msg("failed to resolve snapshot")
+		return
+	}
+	if from.After(to) {
+		from, to = to, from
+	}
+
+	fromRows, err := app.Query.GetFBAInventorySnapshotsAt(app.Ctx, from)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to query inventory snapshot")
+		return
+	}
+	toRows, err := app.Query.GetFBAInventorySnapshotsAt(app.Ctx, to)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to query inventory snapshot")
+		return
+	}
+
+	diffs := computeInventorySnapshotDiff(fromRows, toRows)
+	fmt.Printf("%s %s -> %s\n\n", color.CyanString("Comparing"), from.Local().Format(snapshotTimeLayout), to.Local().Format(snapshotTimeLayout))
+	displayInventorySnapshotDiff(diffs, inventorySnapshotsDiffCfg.ShowAll)
+
+	if inventorySnapshotsDiffCfg.Export.Enabled() {
+		if err := writeExport(inventorySnapshotsDiffCfg.Export, "inventory_diff", inventorySnapshotDiffExportTable(diffs, from, to, inventorySnapshotsDiffCfg.ShowAll)); err != nil {
+			log.Error().Err(err).Msg("failed to export inventory diff")
+		}
+	}
+}
+
+// resolveInventorySnapshot resolves an index or a time to a snapshot time, snapshots are expected to be ordered from latest to oldest.
+func resolveInventorySnapshot(snapshots []db.ListFBAInventorySnapshotsRow, value string) (time.Time, error) {
+	if index, err := strconv.Atoi(value); err == nil {
+		if index < 0 || index >= len(snapshots) {
+			return time.Time{}, fmt.Errorf("snapshot index %d is out of range, there are %d snapshots", index, len(snapshots))
+		}
+		return snapshots[index].SnapshotAt, nil
+	}
+	var at time.Time
+	var err error
+	for _, layout := range []string{time.RFC3339, snapshotTimeLayout, "2006-01-02 15:04", time.DateOnly} {
+		if at, err = time.ParseInLocation(layout, value, time.Local); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a snapshot index or a time such as %s", snapshotTimeLayout)
+	}
+	if len(value) == len(time.DateOnly) {
+		// a date includes the whole day
+		at = at.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	for _, snapshot := range snapshots {
+		if !snapshot.SnapshotAt.After(at) {
+			return snapshot.SnapshotAt, nil
+		}
+	}
+	return time.Time{}, errors.New("no snapshot at or before given time")
+}
+
+func snapshotQuantities(row db.FbaInventorySnapshot) InventorySnapshotQuantities {
+	return InventorySnapshotQuantities{
+		Total:         int(row.TotalQuantity.Int64),
+		Fulfillable:   int(row.FulfillableQuantity.Int64),
+		Inbound:       int(row.InboundWorkingQuantity.Int64 + row.InboundShippedQuantity.Int64 + row.InboundReceivingQuantity.Int64),
+		Reserved:      int(row.ReservedQuantity.Int64),
+		Unfulfillable: int(row.UnfulfillableQuantity.Int64),
+	}
+}
+
+// computeInventorySnapshotDiff pairs SKUs of both snapshots, sorted by the absolute change of total quantity.
+func computeInventorySnapshotDiff(from []db.FbaInventorySnapshot, to []db.FbaInventorySnapshot) []InventorySnapshotDiff {
+	diffs := make(map[string]*InventorySnapshotDiff, len(to))
+	for _, row := range from {
+		diffs[row.Sku] = &InventorySnapshotDiff{SKU: row.Sku, ASIN: row.Asin.String, Title: row.Title.String, Status: "removed", From: snapshotQuantities(row)}
+	}
+	for _, row := range to {
+		diff, ok := diffs[row.Sku]
+		if !ok {
+			diffs[row.Sku] = &InventorySnapshotDiff{SKU: row.Sku, ASIN: row.Asin.String, Title: row.Title.String, Status: "added", To: snapshotQuantities(row)}
+			continue
+		}
+		diff.ASIN, diff.Title = row.Asin.String, row.Title.String
+		diff.To = snapshotQuantities(row)
+		if diff.From == diff.To {
+			diff.Status = "unchanged"
+		} else {
+			diff.Status = "changed"
+		}
+	}
+
+	result := make([]InventorySnapshotDiff, 0, len(diffs))
+	for _, diff := range diffs {
+		result = append(result, *diff)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		di, dj := abs(result[i].To.Total-result[i].From.Total), abs(result[j].To.Total-result[j].From.Total)
+		if di != dj {
+			return di > dj
+		}
+		return result[i].SKU < result[j].SKU
+	})
+	return result
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func formatDelta(v int) string {
+	if v == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", v)
+}
+
+func displayInventorySnapshotDiff(diffs []InventorySnapshotDiff, showAll bool) {
+	fmt.Printf("%-30s %-12s %-10s %14s %14s %14s %14s\n", "SKU", "ASIN", "Status", "Total", "Fulfill", "Inbound", "Unfulfill")
+	fmt.Println(color.HiBlackString("%s", "---------------------------------------------------------------------------------------------------------------"))
+	changed := 0
+	for _, d := range diffs {
+		if d.Status != "unchanged" {
+			changed++
+		} else if !showAll {
+			continue
+		}
+		line := fmt.Sprintf("%-30s %-12s %-10s %14s %14s %14s %14s",
+			truncateString(d.SKU, 30), d.ASIN, d.Status,
+			fmt.Sprintf("%d (%s)", d.To.Total, formatDelta(d.To.Total-d.From.Total)),
+			fmt.Sprintf("%d (%s)", d.To.Fulfillable, formatDelta(d.To.Fulfillable-d.From.Fulfillable)),
+			fmt.Sprintf("%d (%s)", d.To.Inbound, formatDelta(d.To.Inbound-d.From.Inbound)),
+			fmt.Sprintf("%d (%s)", d.To.Unfulfillable, formatDelta(d.To.Unfulfillable-d.From.Unfulfillable)),
+		)
+		switch d.Status {
+		case "added":
+			fmt.Println(color.GreenString(line))
+		case "removed":
+			fmt.Println(color.RedString(line))
+		case "unchanged":
+			fmt.Println(color.HiBlackString(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+	fmt.Printf("\n%d of %d SKUs changed\n", changed, len(diffs))
+}
+
+func inventorySnapshotDiffExportTable(diffs []InventorySnapshotDiff, from time.Time, to time.Time, showAll bool) export.Table {
+	table := export.Table{
+		Name: "diff",
+		Columns: []export.Column{
+			{Name: "SKU", Key: "sku", Type: export.String},
+			{Name: "ASIN", Key: "asin", Type: export.String},
+			{Name: "Title", Key: "title", Type: export.String},
+			{Name: "Status", Key: "status", Type: export.String},
+			{Name: "From", Key: "from", Type: export.Time},
+			{Name: "To", Key: "to", Type: export.Time},
+			{Name: "Total Before", Key: "total_before", Type: export.Int},
+			{Name: "Total After", Key: "total_after", Type: export.Int},
+			{Name: "Total Change", Key: "total_change", Type: export.Int},
+			{Name: "Fulfillable Before", Key: "fulfillable_before", Type: export.Int},
+			{Name: "Fulfillable After", Key: "fulfillable_after", Type: export.Int},
+			{Name: "Fulfillable Change", Key: "fulfillable_change", Type: export.Int},
+			{Name: "Inbound Before", Key: "inbound_before", Type: export.Int},
+			{Name: "Inbound After", Key: "inbound_after", Type: export.Int},
+			{Name: "Inbound Change", Key: "inbound_change", Type: export.Int},
+			{Name: "Reserved Before", Key: "reserved_before", Type: export.Int},
+			{Name: "Reserved After", Key: "reserved_after", Type: export.Int},
+			{Name: "Reserved Change", Key: "reserved_change", Type: export.Int},
+			{Name: "Unfulfillable Before", Key: "unfulfillable_before", Type: export.Int},
+			{Name: "Unfulfillable After", Key: "unfulfillable_after", Type: export.Int},
+			{Name: "Unfulfillable Change", Key: "unfulfillable_change", Type: export.Int},
+		},
+	}
+	for _, d := range diffs {
+		if !showAll && d.Status == "unchanged" {
+			continue
+		}
+		table.Rows = append(table.Rows, []any{
+			d.SKU, d.ASIN, d.Title, d.Status, from, to,
+			d.From.Total, d.To.Total, d.To.Total - d.From.Total,
+			d.From.Fulfillable, d.To.Fulfillable, d.To.Fulfillable - d.From.Fulfillable,
+			d.From.Inbound, d.To.Inbound, d.To.Inbound - d.From.Inbound,
+			d.From.Reserved, d.To.Reserved, d.To.Reserved - d.From.Reserved,
+			d.From.Unfulfillable, d.To.Unfulfillable, d.To.Unfulfillable - d.From.Unfulfillable,
+		})
+	}
+	return table
+}
@@ -3,7 +3,11 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/valyala/fastjson"
+	yaml "gopkg.in/yaml.v3"
 )
 
 type createListingsConfig struct {
@@ -22,6 +27,8 @@ type createListingsConfig struct {
 	Requirements          string
 	AutofillMarketplaceId bool
 	AutofillLanguageTag   bool
+	SkipValidation        bool
+	DryRun                bool
 }
 
 type getListingConfig struct {
@@ -30,20 +37,23 @@ type getListingConfig struct {
 }
 
 type patchListingConfig struct {
-	EditFile string
-	Params   listings.PatchListingsItemParams
-	Body     listings.PatchListingsItemJSONRequestBody
+	EditFile       string
+	SkipValidation bool
+	DryRun         bool
+	Params         listings.PatchListingsItemParams
+	Body           listings.PatchListingsItemJSONRequestBody
 }
 
 type deleteListingConfig struct {
 	Params        listings.DeleteListingsItemParams
 	DeleteRelated bool
+	DryRun        bool
 }
 
 var (
 	createListingsCmd = &cobra.Command{
 		Use: "create",
-		Run: WrapCommandWithResources(createListings, ResourceConfig{Resources: []ResourceType{ResourceAmazon}, Services: []ServiceType{ServiceListings}}),
+		Run: WrapCommandWithResources(createListings, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceListings, ServiceProductTypeDefinitions}}),
 	}
 	createListingsCfg createListingsConfig
 	getListingCmd     = &cobra.Command{
@@ -58,7 +68,7 @@ var (
 	deleteListingCfg deleteListingConfig
 	patchListingCmd  = &cobra.Command{
 		Use: "patch",
-		Run: WrapCommandWithResources(patchListing, ResourceConfig{Resources: []ResourceType{ResourceAmazon}, Services: []ServiceType{ServiceListings}}),
+		Run: WrapCommandWithResources(patchListing, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceListings, ServiceProductTypeDefinitions}}),
 	}
 	patchListingCfg patchListingConfig
 	listingsCmd     = &cobra.Command{
@@ -70,24 +80,31 @@ var (
 func getListingsCmd() *cobra.Command {
 	createListingsCmd.PersistentFlags().BoolVar(&createListingsCfg.AutofillMarketplaceId, "fill-marketplace-id", false, "adds {\"marketplace_id\": ...} to every json object in attributes")
 	createListingsCmd.PersistentFlags().BoolVar(&createListingsCfg.AutofillLanguageTag, "fill-language-tag", false, "adds {\"language_tag\": ...} to every json object in attributes")
-	createListingsCmd.PersistentFlags().StringVarP(&createListingsCfg.Input, "input", "i", "", "Attributes JSON file")
+	createListingsCmd.PersistentFlags().StringVarP(&createListingsCfg.Input, "input", "i", "", "Attributes JSON or YAML file")
 	createListingsCmd.MarkFlagRequired("input")
 	createListingsCmd.PersistentFlags().StringVarP(&createListingsCfg.ProductType, "type", "p", "", "product type")
 	createListingsCmd.PersistentFlags().StringVarP(&createListingsCfg.Requirements, "requirements", "r", "", "")
+	createListingsCmd.PersistentFlags().BoolVar(&createListingsCfg.SkipValidation, "skip-validation", false, "submit without validating attributes against the product type schema first")
+	createListingsCmd.PersistentFlags().BoolVar(&createListingsCfg.DryRun, "dry-run", false, "print the request and the issues Amazon would report without creating the listing (mode=VALIDATION_PREVIEW)")
 	createListingsCmd.PersistentFlags().StringVar(&createListingsCfg.IssueLocale, "issue-locale", "", "Locale for issue localization. Default: When no locale is provided, the default locale of the first marketplace is used. Localization defaults to en_US when a localized message is not available in the specified locale.")
 
 	getListingCmd.PersistentFlags().BoolVar(&getListingCfg.DisplayAttritubes, "attributes", false, "logs listing attributes line by line if given")
 	getListingCmd.PersistentFlags().BoolVar(&getListingCfg.Related, "related", false, "also display products related with this product (variations etc...)")
 
 	patchListingCmd.PersistentFlags().StringVarP(&patchListingCfg.EditFile, "input", "i", "", "json file containing edits")
+	patchListingCmd.PersistentFlags().BoolVar(&patchListingCfg.SkipValidation, "skip-validation", false, "submit without validating patched attributes against the product type schema first")
+	patchListingCmd.PersistentFlags().BoolVar(&patchListingCfg.DryRun, "dry-run", false, "print the request and the issues Amazon would report without patching the listing (mode=VALIDATION_PREVIEW)")
 
 	deleteListingCmd.PersistentFlags().BoolVar(&getListingCfg.Related, "related", false, "also delete related (child // parent) listings")
+	deleteListingCmd.PersistentFlags().BoolVar(&deleteListingCfg.DryRun, "dry-run", false, "print the listings and requests that would be deleted without deleting anything")
 
 	listingsCmd.PersistentFlags().StringVarP(&listingOperationSku, "sku", "s", "", "")
 	listingsCmd.AddCommand(createListingsCmd)
 	listingsCmd.AddCommand(getListingCmd)
 	listingsCmd.AddCommand(deleteListingCmd)
 	listingsCmd.AddCommand(patchListingCmd)
+	listingsCmd.AddCommand(getValidateListingCmd())
+	listingsCmd.AddCommand(getBulkListingsCmd())
 	return listingsCmd
 }
 
@@ -104,22 +121,83 @@ func createListings(cmd *cobra.Command, args []string) {
 	var body listings.ListingsItemPutRequest
 	body.ProductType = createListingsCfg.ProductType
 	body.Requirements = internal.Ptr(listings.ListingsItemPutRequestRequirements(createListingsCfg.Requirements))
+	attr_interface, err := loadListingAttributes(createListingsCfg.Input, createListingsCfg.AutofillMarketplaceId, createListingsCfg.AutofillLanguageTag)
+	if err != nil {
+		log.Error().Err(err).Str("input", createListingsCfg.Input).Msg("failed to read attributes")
+		return
+	}
+	if !createListingsCfg.SkipValidation && !checkListingAttributes(app, body.ProductType, createListingsCfg.Requirements, attr_interface) {
+		return
+	}
+
+	body.Attributes = attr_interface
+
+	if createListingsCfg.DryRun {
+		params.Mode = internal.Ptr(listings.PutListingsItemParamsMode(listingsModeValidationPreview))
+		query := listingRequestQuery(params.MarketplaceIds, params.IssueLocale)
+		query.Set("includedData", "issues")
+		query.Set("mode", listingsModeValidationPreview)
+		if err := displayListingRequest(http.MethodPut, listingOperationSku, query, body); err != nil {
+			log.Error().Err(err).Msg("failed to display request")
+			return
+		}
+	}
+	status, err := app.Amazon.Client.PutListingsItem(cmd.Context(), cfg.Amazon.Auth.DefaultMerchant.SellerToken, listingOperationSku, &params, body)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	result := status.JSON200
+	if createListingsCfg.DryRun {
+		displayListingPreview(*result)
+		return
+	}
+	log.Info().
+		Str("status", string(result.Status)).
+		Str("submission_id", result.SubmissionId).
+		Str("sku", result.Sku).
+		Send()
+	logListingIssues(*result.Issues)
+}
+
+// loadListingAttributes reads an attributes JSON or YAML file, optionally filling marketplace_id and language_tag of every attribute value.
+func loadListingAttributes(input string, fillMarketplaceId bool, fillLanguageTag bool) (map[string]interface{}, error) {
+	attr_bytes, err := internal.ReadFile(input)
+	if err != nil {
+		return nil, err
+	}
+	if ext := strings.ToLower(filepath.Ext(input)); ext == ".yaml" || ext == ".yml" {
+		var attr_yaml map[string]interface{}
+		if err := yaml.Unmarshal(attr_bytes, &attr_yaml); err != nil {
+			return nil, fmt.Errorf("failed to parse attributes: %w", err)
+		}
+		if attr_bytes, err = json.Marshal(attr_yaml); err != nil {
+			return nil, err
+		}
+	}
+	return prepareListingAttributes(attr_bytes, fillMarketplaceId, fillLanguageTag)
+}
+
+// prepareListingAttributes parses attributes JSON, optionally filling marketplace_id and language_tag of every attribute value.
+func prepareListingAttributes(attr_bytes []byte, fillMarketplaceId bool, fillLanguageTag bool) (map[string]interface{}, error) {
 	var marketplace_id *fastjson.Value
 	var language_tag *fastjson.Value
-	if createListingsCfg.AutofillMarketplaceId {
+	if fillMarketplaceId {
 		marketplace_id = fastjson.MustParse(fmt.Sprintf(`"%s"`, cfg.Amazon.Auth.DefaultMerchant.MarketplaceID[0]))
 	}
-	if createListingsCfg.AutofillLanguageTag {
+	if fillLanguageTag {
 		language_tag = fastjson.MustParse(fmt.Sprintf(`"%s"`, cfg.Amazon.DefaultLanguageTag))
 	}
 	var should_fill_marketplace_id = marketplace_id != nil
 	var should_fill_language_tag = language_tag != nil
-	attr_bytes, err := internal.ReadFile(createListingsCfg.Input)
+	parsed, err := fastjson.ParseBytes(attr_bytes)
 	if err != nil {
-		log.Error().Err(err).Send()
-		return
+		return nil, fmt.Errorf("failed to parse attributes: %w", err)
+	}
+	attrs, err := parsed.Object()
+	if err != nil {
+		return nil, fmt.Errorf("attributes must be an object: %w", err)
 	}
-	attrs := fastjson.MustParseBytes(attr_bytes).GetObject()
 	attrs.Visit(func(key []byte, v *fastjson.Value) {
 		for _, attr_detail := range v.GetArray() {
 			attr_detail_obj := attr_detail.GetObject()
@@ -141,39 +219,21 @@ func createListings(cmd *cobra.Command, args []string) {
 		}
 	})
 	// sanity check
-	brand_obj := attrs.Get("brand").
-		GetArray()[0].
-		GetObject()
-	brand_bytes := brand_obj.Get("value").GetStringBytes()
-	brand := string(brand_bytes)
-	brand = strings.TrimSpace(brand)
-	brand_obj.Set("value", fastjson.MustParse(fmt.Sprintf(`"%s"`, brand)))
+	if brands := attrs.Get("brand").GetArray(); len(brands) > 0 && brands[0].GetObject() != nil {
+		brand_obj := brands[0].GetObject()
+		brand := strings.TrimSpace(string(brand_obj.Get("value").GetStringBytes()))
+		brand_obj.Set("value", fastjson.MustParse(strconv.Quote(brand)))
+	}
 	schema := attrs.Get("$schema")
 	if schema != nil {
 		attrs.Del("$schema")
 	}
 
 	var attr_interface map[string]interface{}
-	err = json.Unmarshal(attrs.MarshalTo(nil), &attr_interface)
-	if err != nil {
-		log.Error().Err(err).Send()
-		return
+	if err := json.Unmarshal(attrs.MarshalTo(nil), &attr_interface); err != nil {
+		return nil, err
 	}
-
-	body.Attributes = attr_interface
-
-	status, err := app.Amazon.Client.PutListingsItem(cmd.Context(), cfg.Amazon.Auth.DefaultMerchant.SellerToken, listingOperationSku, &params, body)
-	if err != nil {
-		log.Error().Err(err).Send()
-		return
-	}
-	result := status.JSON200
-	log.Info().
-		Str("status", string(result.Status)).
-		Str("submission_id", result.SubmissionId).
-		Str("sku", result.Sku).
-		Send()
-	logListingIssues(*result.Issues)
+	return attr_interface, nil
 }
 
 func getListing(cmd *cobra.Command, args []string) {
@@ -399,7 +459,7 @@ func deleteListing(cmd *cobra.Command, args []string) {
 	app := GetApp(cmd)
 	var getListingParams listings.GetListingsItemParams
 	getListingParams.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
-	getListingParams.IncludedData = &[]listings.GetListingsItemParamsIncludedData{"relationships"}
+	getListingParams.IncludedData = &[]listings.GetListingsItemParamsIncludedData{"relationships", "summaries"}
 	getListingParams.IssueLocale = internal.Ptr("en_US")
 	status, err := app.Amazon.Client.GetListingsItem(cmd.Context(), &getListingParams, cfg.Amazon.Auth.DefaultMerchant.SellerToken, listingOperationSku)
 	if err != nil {
@@ -410,12 +470,20 @@ func deleteListing(cmd *cobra.Command, args []string) {
 	deleteListingCfg.Params.IssueLocale = internal.Ptr("en_US")
 
 	result := status.JSON200
+	if deleteListingCfg.DryRun {
+		fmt.Println(color.YellowString("dry run, nothing is deleted"))
+		displayListingSummaries(*result)
+	}
 	if getListingCfg.Related && result.Relationships != nil {
 		for _, relationship := range *result.Relationships {
 			for _, rls := range relationship.Relationships {
 				if rls.ChildSkus != nil {
 					fmt.Printf("%s %s\n", color.CyanString("Related:"), color.YellowString("Deleting child SKUs: %s", strings.Join(*rls.ChildSkus, ",")))
 					for _, child := range *rls.ChildSkus {
+						if deleteListingCfg.DryRun {
+							displayListingDeleteRequest(child)
+							continue
+						}
 						_, err := app.Amazon.Client.DeleteListingsItem(cmd.Context(), &deleteListingCfg.Params, cfg.Amazon.Auth.DefaultMerchant.SellerToken, child)
 						if err != nil {
 							log.Error().Err(err).Str("sku", child).Msg("error deleting child sku")
@@ -426,6 +494,10 @@ func deleteListing(cmd *cobra.Command, args []string) {
 				if rls.ParentSkus != nil {
 					fmt.Printf("%s %s\n", color.CyanString("Related:"), color.YellowString("Deleting parent SKUs: %s", strings.Join(*rls.ParentSkus, ",")))
 					for _, parent := range *rls.ParentSkus {
+						if deleteListingCfg.DryRun {
+							displayListingDeleteRequest(parent)
+							continue
+						}
 						_, err := app.Amazon.Client.DeleteListingsItem(cmd.Context(), &deleteListingCfg.Params, cfg.Amazon.Auth.DefaultMerchant.SellerToken, parent)
 						if err != nil {
 							log.Error().Err(err).Str("sku", parent).Msg("error deleting parent sku")
@@ -437,8 +509,10 @@ func deleteListing(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	deleteListingCfg.Params.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
-	deleteListingCfg.Params.IssueLocale = internal.Ptr("en_US")
+	if deleteListingCfg.DryRun {
+		displayListingDeleteRequest(listingOperationSku)
+		return
+	}
 	deleteStatus, err := app.Amazon.Client.DeleteListingsItem(cmd.Context(), &deleteListingCfg.Params, cfg.Amazon.Auth.DefaultMerchant.SellerToken, listingOperationSku)
 	if err != nil {
 		log.Error().Err(err).Msg("error deleting listing")
@@ -456,79 +530,96 @@ func deleteListing(cmd *cobra.Command, args []string) {
 func patchListing(cmd *cobra.Command, args []string) {
 	app := GetApp(cmd)
 	logger := log.With().Str("path", patchListingCfg.EditFile).Logger()
-	patch_byte, err := internal.ReadFile(patchListingCfg.EditFile)
+	productType, patch_ops, err := readListingPatches(patchListingCfg.EditFile)
 	if err != nil {
 		logger.Error().Err(err).Msg("error reading patch file")
 		return
 	}
-	edit, err := fastjson.ParseBytes(patch_byte)
+	if !patchListingCfg.SkipValidation && !checkListingPatches(app, productType, patch_ops) {
+		return
+	}
+	patchListingCfg.Body.ProductType = productType
+	patchListingCfg.Params.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
+	patchListingCfg.Params.IncludedData = &[]listings.PatchListingsItemParamsIncludedData{"issues"}
+	patchListingCfg.Params.IssueLocale = internal.Ptr("en_US")
+	patchListingCfg.Body.Patches = patch_ops
+	if patchListingCfg.DryRun {
+		patchListingCfg.Params.Mode = internal.Ptr(listings.PatchListingsItemParamsMode(listingsModeValidationPreview))
+		query := listingRequestQuery(patchListingCfg.Params.MarketplaceIds, patchListingCfg.Params.IssueLocale)
+		query.Set("includedData", "issues")
+		query.Set("mode", listingsModeValidationPreview)
+		if err := displayListingRequest(http.MethodPatch, listingOperationSku, query, patchListingCfg.Body); err != nil {
+			log.Error().Err(err).Msg("failed to display request")
+			return
+		}
+	}
+	status, err := app.Amazon.Client.PatchListingsItem(cmd.Context(), &patchListingCfg.Params, patchListingCfg.Body, cfg.Amazon.Auth.DefaultMerchant.SellerToken, listingOperationSku)
 	if err != nil {
-		logger.Error().Err(err).Msg("error parsing patch file")
+		log.Error().Err(err).Send()
 		return
 	}
+	result := status.JSON200
+	if patchListingCfg.DryRun {
+		displayListingPreview(*result)
+		return
+	}
+	if result.Issues != nil {
+		logListingIssues(*status.JSON200.Issues)
+	}
+
+}
+
+// readListingPatches reads a patch file with productType and patches keys.
+func readListingPatches(path string) (string, []listings.PatchOperation, error) {
+	patch_byte, err := internal.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	edit, err := fastjson.ParseBytes(patch_byte)
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing patch file: %w", err)
+	}
 	productTypeVal := edit.Get("productType")
 	if productTypeVal == nil {
-		logger.Error().Msg("no product type found (looking for key: productType)")
-		return
+		return "", nil, errors.New("no product type found (looking for key: productType)")
 	}
 	patchesVal := edit.Get("patches")
 	if patchesVal == nil {
-		logger.Error().Msg("no patch found (looking for key: patches)")
+		return "", nil, errors.New("no patch found (looking for key: patches)")
 	}
-	patchListingCfg.Body.ProductType = "WALLET"
 	patches := patchesVal.GetArray()
 	if patches == nil {
-		log.Error().Msg("patch is not array")
-		return
+		return "", nil, errors.New("patch is not array")
 	}
 	var patch_ops = make([]listings.PatchOperation, 0, len(patches))
 	for i, patch := range patches {
 		var patch_op listings.PatchOperation
-		ev := log.With().Int("index", i).Logger()
 		op_str := patch.GetStringBytes("op")
 		if op_str == nil {
 			bold := color.New(color.Bold)
-			ev.Error().Msg(fmt.Sprintf("patch is missing op or not string (looking for key: op), valid values are %s, %s and %s", bold.Sprint("add"), bold.Sprint("replace"), bold.Sprint("delete")))
-			return
+			return "", nil, fmt.Errorf("patch %d is missing op or not string (looking for key: op), valid values are %s, %s and %s", i, bold.Sprint("add"), bold.Sprint("replace"), bold.Sprint("delete"))
 		}
 		path := patch.GetStringBytes("path")
 		if path == nil {
-			ev.Error().Msg("patch is missing path or not string (looking for key: path)")
-			return
+			return "", nil, fmt.Errorf("patch %d is missing path or not string (looking for key: path)", i)
 		}
 		value := patch.Get("value")
 		if value == nil {
-			ev.Error().Msg("patch is missing value (looking for key: value)")
-			return
+			return "", nil, fmt.Errorf("patch %d is missing value (looking for key: value)", i)
 		}
 		if value.Type() != fastjson.TypeArray {
-			ev.Error().Msg("patchs value is not array")
-			return
+			return "", nil, fmt.Errorf("value of patch %d is not array", i)
 		}
 		var val *[]map[string]interface{}
 		if err := json.Unmarshal(value.MarshalTo(nil), &val); err != nil {
-			log.Error().Err(err).Send()
-			return
+			return "", nil, fmt.Errorf("patch %d: %w", i, err)
 		}
 		patch_op.Value = val
 		patch_op.Op = listings.PatchOperationOp(string(op_str))
 		patch_op.Path = string(path)
 		patch_ops = append(patch_ops, patch_op)
 	}
-	patchListingCfg.Params.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
-	patchListingCfg.Params.IncludedData = &[]listings.PatchListingsItemParamsIncludedData{"issues"}
-	patchListingCfg.Params.IssueLocale = internal.Ptr("en_US")
-	patchListingCfg.Body.Patches = patch_ops
-	status, err := app.Amazon.Client.PatchListingsItem(cmd.Context(), &patchListingCfg.Params, patchListingCfg.Body, cfg.Amazon.Auth.DefaultMerchant.SellerToken, listingOperationSku)
-	if err != nil {
-		log.Error().Err(err).Send()
-		return
-	}
-	result := status.JSON200
-	if result.Issues != nil {
-		logListingIssues(*status.JSON200.Issues)
-	}
-
+	return string(productTypeVal.GetStringBytes()), patch_ops, nil
 }
 
 func logListingIssues(issues []listings.Issue) {
@@ -551,3 +642,68 @@ func logListingIssues(issues []listings.Issue) {
 		ev.Msg(issue.Message)
 	}
 }
+
+// listingsModeValidationPreview validates a listings submission without applying it.
+const listingsModeValidationPreview = "VALIDATION_PREVIEW"
+
+func listingRequestQuery(marketplaceIds []string, issueLocale *string) url.Values {
+	query := url.Values{}
+	query.Set("marketplaceIds", strings.Join(marketplaceIds, ","))
+	if issueLocale != nil {
+		query.Set("issueLocale", *issueLocale)
+	}
+	return query
+}
+
+// displayListingRequest prints a listings items request with its body, as it is sent to Amazon.
+func displayListingRequest(method string, sku string, query url.Values, body any) error {
+	fmt.Printf("%s /listings/2021-08-01/items/%s/%s?%s\n", color.CyanString(method), cfg.Amazon.Auth.DefaultMerchant.SellerToken, url.PathEscape(sku), query.Encode())
+	if body == nil {
+		return nil
+	}
+	encoded, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n\n", encoded)
+	return nil
+}
+
+func displayListingDeleteRequest(sku string) {
+	displayListingRequest(http.MethodDelete, sku, listingRequestQuery(deleteListingCfg.Params.MarketplaceIds, deleteListingCfg.Params.IssueLocale), nil) //nolint:errcheck
+}
+
+// displayListingPreview prints the result of a submission validated with VALIDATION_PREVIEW.
+func displayListingPreview(result listings.ListingsItemSubmissionResponse) {
+	if result.Issues != nil {
+		logListingIssues(*result.Issues)
+	}
+	issues := 0
+	if result.Issues != nil {
+		issues = len(*result.Issues)
+	}
+	if result.Status == "VALID" && issues == 0 {
+		color.Green("dry run: %s is valid, Amazon would accept the submission without issues", result.Sku)
+		return
+	}
+	fmt.Println(color.YellowString("dry run: %s is %s with %d issues, nothing is submitted", result.Sku, result.Status, issues))
+}
+
+// displayListingSummaries prints the product type, status and name of a listing in every marketplace.
+func displayListingSummaries(item listings.Item) {
+	if item.Summaries == nil {
+		return
+	}
+	for _, summary := range *item.Summaries {
+		statuses := make([]string, 0, len(summary.Status))
+		for _, status := range summary.Status {
+			statuses = append(statuses, string(status))
+		}
+		fmt.Printf("%s %s  %s %s  %s %s  %s %s\n",
+			color.HiBlackString("SKU:"), item.Sku, color.HiBlackString("Marketplace:"), summary.MarketplaceId,
+			color.HiBlackString("Product Type:"), summary.ProductType, color.HiBlackString("Status:"), valueOrDash(strings.Join(statuses, ",")))
+		if summary.ItemName != nil {
+			fmt.Printf("  %s\n", *summary.ItemName)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/listings"
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/caner-cetin/halycon/internal/jsonschema"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/xuri/excelize/v2"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type bulkListingsConfig struct {
+	Input                 string
+	Sheet                 string
+	Mapping               string
+	ProductType           string
+	Requirements          string
+	Concurrency           int
+	AutofillMarketplaceId bool
+	AutofillLanguageTag   bool
+	SkipValidation        bool
+	DryRun                bool
+	Export                exportConfig
+}
+
+var (
+	bulkListingsCmd = &cobra.Command{
+		Use:   "bulk",
+		Short: "creates or updates a listing for every row of a csv or xlsx file, with columns mapped to attributes",
+		Run:   WrapCommandWithResources(bulkListings, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceListings, ServiceProductTypeDefinitions}}),
+	}
+	bulkListingsCfg bulkListingsConfig
+)
+
+func getBulkListingsCmd() *cobra.Command {
+	flags := bulkListingsCmd.PersistentFlags()
+	flags.StringVarP(&bulkListingsCfg.Input, "input", "i", "", "csv or xlsx file with a header row and a row per listing")
+	flags.StringVar(&bulkListingsCfg.Sheet, "sheet", "", "sheet of the xlsx file (default: the first sheet)")
+	flags.StringVarP(&bulkListingsCfg.Mapping, "mapping", "m", "", "yaml file mapping columns to attribute paths")
+	flags.StringVarP(&bulkListingsCfg.ProductType, "type", "p", "", "product type")
+	flags.StringVarP(&bulkListingsCfg.Requirements, "requirements", "r", "", "requirements of the listings (LISTING, LISTING_PRODUCT_ONLY or LISTING_OFFER_ONLY)")
+	flags.IntVar(&bulkListingsCfg.Concurrency, "concurrency", 5, "number of listings submitted at the same time, requests stay within the rate limit of the Listings API")
+	flags.BoolVar(&bulkListingsCfg.AutofillMarketplaceId, "fill-marketplace-id", false, "adds {\"marketplace_id\": ...} to every json object in attributes")
+	flags.BoolVar(&bulkListingsCfg.AutofillLanguageTag, "fill-language-tag", false, "adds {\"language_tag\": ...} to every json object in attributes")
+	flags.BoolVar(&bulkListingsCfg.SkipValidation, "skip-validation", false, "submit without validating attributes against the product type schema first")
+	flags.BoolVar(&bulkListingsCfg.DryRun, "dry-run", false, "report the issues Amazon would raise without creating or changing listings (mode=VALIDATION_PREVIEW)")
+	addExportFlags(flags, &bulkListingsCfg.Export, "listings_bulk")
+	bulkListingsCmd.MarkPersistentFlagRequired("input")   //nolint:errcheck
+	bulkListingsCmd.MarkPersistentFlagRequired("mapping") //nolint:errcheck
+	bulkListingsCmd.MarkPersistentFlagRequired("type")    //nolint:errcheck
+	return bulkListingsCmd
+}
+
+// listingsBulkMapping maps the columns of a spreadsheet to attribute paths such as item_name[0].value.
+type listingsBulkMapping struct {
+	// SKU is the column holding the seller SKU of every row.
+	SKU     string            `yaml:"sku"`
+	Columns map[string]string `yaml:"columns"`
+	// Attributes are set on every row, keyed by attribute path.
+	Attributes map[string]any `yaml:"attributes"`
+}
+
+// listingAttributePathSegment is a property of an attribute path, Index is -1 for properties that are not arrays.
+type listingAttributePathSegment struct {
+	Name  string
+	Index int
+}
+
+type listingsBulkRow struct {
+	Row        int
+	SKU        string
+	Attributes map[string]interface{}
+}
+
+// listingsBulkResult is the outcome of a row. Status is the submission status returned by Amazon, SKIPPED for rows that are
+// not submitted because they failed local validation, or ERROR for rows that could not be built or submitted.
+type listingsBulkResult struct {
+	Row          int
+	SKU          string
+	Status       string
+	SubmissionID string
+	Issues       []string
+}
+
+const (
+	listingsBulkStatusSkipped = "SKIPPED"
+	listingsBulkStatusError   = "ERROR"
+)
+
+var listingAttributePathSegmentPattern = regexp.MustCompile(`^([A-Za-z0-9_]+)(?:\[(\d+)\])?$`)
+
+func bulkListings(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	mapping, err := readListingsBulkMapping(bulkListingsCfg.Mapping)
+	if err != nil {
+		log.Error().Err(err).Str("mapping", bulkListingsCfg.Mapping).Msg("failed to read mapping")
+		return
+	}
+	records, err := readListingsBulkRecords(bulkListingsCfg.Input, bulkListingsCfg.Sheet)
+	if err != nil {
+		log.Error().Err(err).Str("input", bulkListingsCfg.Input).Msg("failed to read input")
+		return
+	}
+	schema, err := loadProductTypeSchema(app, bulkListingsCfg.ProductType, bulkListingsCfg.Requirements)
+	if err != nil {
+		log.Error().Err(err).Str("product_type", bulkListingsCfg.ProductType).Msg("failed to load product type schema")
+		return
+	}
+	rows, results, err := buildListingsBulkRows(schema, mapping, records, bulkListingsCfg.AutofillMarketplaceId, bulkListingsCfg.AutofillLanguageTag)
+	if err != nil {
+		log.Error().Err(err).Str("input", bulkListingsCfg.Input).Msg("failed to map columns")
+		return
+	}
+	if !bulkListingsCfg.SkipValidation {
+		for i, row := range rows {
+			if results[i].Status != "" {
+				continue
+			}
+			for _, problem := range schema.Validate(row.Attributes) {
+				results[i].Issues = append(results[i].Issues, fmt.Sprintf("%s: %s", problem.Path, problem.Message))
+			}
+			if len(results[i].Issues) > 0 {
+				results[i].Status = listingsBulkStatusSkipped
+				log.Warn().Int("row", row.Row).Str("sku", row.SKU).Int("problems", len(results[i].Issues)).Msg("attributes do not match the product type schema, skipping")
+			}
+		}
+	}
+
+	pending := 0
+	for _, result := range results {
+		if result.Status == "" {
+			pending++
+		}
+	}
+	fmt.Printf("%s %d of %d rows with %d workers\n", color.CyanString("Submitting"), pending, len(rows), max(bulkListingsCfg.Concurrency, 1))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(bulkListingsCfg.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = submitListingsBulkRow(app, rows[i])
+			}
+		}()
+	}
+	for i := range rows {
+		if results[i].Status == "" {
+			jobs <- i
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	displayListingsBulkResults(results)
+	if err := writeExport(bulkListingsCfg.Export, "listings_bulk", listingsBulkExportTable(results)); err != nil {
+		log.Error().Err(err).Msg("failed to write results")
+	}
+}
+
+func readListingsBulkMapping(path string) (listingsBulkMapping, error) {
+	var mapping listingsBulkMapping
+	data, err := internal.ReadFile(path)
+	if err != nil {
+		return mapping, err
+	}
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return mapping, fmt.Errorf("failed to parse mapping: %w", err)
+	}
+	if mapping.SKU == "" {
+		return mapping, errors.New("mapping has no sku column (looking for key: sku)")
+	}
+	if len(mapping.Columns) == 0 {
+		return mapping, errors.New("mapping has no columns (looking for key: columns)")
+	}
+	return mapping, nil
+}
+
+// readListingsBulkRecords reads the rows of a csv file, or of a sheet of an xlsx file, including the header row.
+func readListingsBulkRecords(path string, sheet string) ([][]string, error) {
+	data, err := internal.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records [][]string
+	if strings.EqualFold(filepath.Ext(path), ".xlsx") {
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open xlsx: %w", err)
+		}
+		defer f.Close()
+		if sheet == "" {
+			sheet = f.GetSheetList()[0]
+		}
+		if records, err = f.GetRows(sheet); err != nil {
+			return nil, fmt.Errorf("failed to read sheet %s: %w", sheet, err)
+		}
+	} else {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		if records, err = reader.ReadAll(); err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+	}
+	if len(records) < 2 {
+		return nil, errors.New("input needs a header row and at least one row")
+	}
+	return records, nil
+}
+
+// parseListingAttributePath parses a path such as list_price[0].value_with_tax.
+func parseListingAttributePath(path string) ([]listingAttributePathSegment, error) {
+	var segments []listingAttributePathSegment
+	for _, part := range strings.Split(path, ".") {
+		match := listingAttributePathSegmentPattern.FindStringSubmatch(strings.TrimSpace(part))
+		if match == nil {
+			return nil, fmt.Errorf("invalid attribute path %s, expected a path such as item_name[0].value", path)
+		}
+		segment := listingAttributePathSegment{Name: match[1], Index: -1}
+		if match[2] != "" {
+			segment.Index, _ = strconv.Atoi(match[2])
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// buildListingsBulkRows builds the attributes of every row. Empty cells are left out, and values are converted to the type
+// the schema declares at their path. Rows that cannot be built are returned with an ERROR result.
+func buildListingsBulkRows(schema *jsonschema.Schema, mapping listingsBulkMapping, records [][]string, fillMarketplaceId bool, fillLanguageTag bool) ([]listingsBulkRow, []listingsBulkResult, error) {
+	header := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	column := func(name string) (int, error) {
+		index, ok := header[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, fmt.Errorf("column %s of the mapping is not in the header", name)
+		}
+		return index, nil
+	}
+	skuColumn, err := column(mapping.SKU)
+	if err != nil {
+		return nil, nil, err
+	}
+	type mappedColumn struct {
+		Index    int
+		Path     string
+		Segments []listingAttributePathSegment
+		Type     string
+	}
+	var columns []mappedColumn
+	for name, path := range mapping.Columns {
+		index, err := column(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		segments, err := parseListingAttributePath(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns = append(columns, mappedColumn{Index: index, Path: path, Segments: segments, Type: listingAttributePathType(schema, segments)})
+	}
+	slices.SortFunc(columns, func(a, b mappedColumn) int { return cmp.Compare(a.Path, b.Path) })
+	constants := make([]string, 0, len(mapping.Attributes))
+	for path := range mapping.Attributes {
+		constants = append(constants, path)
+	}
+	slices.Sort(constants)
+
+	var rows []listingsBulkRow
+	var results []listingsBulkResult
+	for i, record := range records[1:] {
+		cell := func(index int) string {
+			if index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		if !slices.ContainsFunc(record, func(s string) bool { return strings.TrimSpace(s) != "" }) {
+			continue
+		}
+		row := listingsBulkRow{Row: i + 2, SKU: cell(skuColumn)}
+		result := listingsBulkResult{Row: row.Row, SKU: row.SKU}
+		attributes := make(map[string]any)
+		for _, path := range constants {
+			segments, err := parseListingAttributePath(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			value := mapping.Attributes[path]
+			if text, ok := value.(string); ok {
+				value, err = convertListingAttributeValue(text, listingAttributePathType(schema, segments))
+				if err != nil {
+					return nil, nil, fmt.Errorf("attribute %s: %w", path, err)
+				}
+			}
+			setListingAttributePath(attributes, segments, value)
+		}
+		for _, c := range columns {
+			text := cell(c.Index)
+			if text == "" {
+				continue
+			}
+			value, err := convertListingAttributeValue(text, c.Type)
+			if err != nil {
+				result.Issues = append(result.Issues, fmt.Sprintf("%s: %s", c.Path, err))
+				continue
+			}
+			setListingAttributePath(attributes, c.Segments, value)
+		}
+		if row.SKU == "" {
+			result.Issues = append(result.Issues, fmt.Sprintf("%s column is empty", mapping.SKU))
+		}
+		if len(result.Issues) == 0 {
+			encoded, err := json.Marshal(compactListingAttributes(attributes))
+			if err == nil {
+				row.Attributes, err = prepareListingAttributes(encoded, fillMarketplaceId, fillLanguageTag)
+			}
+			if err != nil {
+				result.Issues = append(result.Issues, err.Error())
+			}
+		}
+		if len(result.Issues) > 0 {
+			result.Status = listingsBulkStatusError
+			log.Warn().Int("row", row.Row).Str("sku", row.SKU).Strs("problems", result.Issues).Msg("failed to build attributes, skipping")
+		}
+		rows = append(rows, row)
+		results = append(results, result)
+	}
+	return rows, results, nil
+}
+
+// listingAttributePathType returns the type the schema declares at a path, or an empty string if the path is not in the schema.
+func listingAttributePathType(schema *jsonschema.Schema, segments []listingAttributePathSegment) string {
+	node := mergeScaffoldSchema(schema, schema.Root())
+	for _, segment := range segments {
+		properties, _ := node["properties"].(map[string]any)
+		property, ok := properties[segment.Name]
+		if !ok {
+			return ""
+		}
+		node = mergeScaffoldSchema(schema, property)
+		if segment.Index >= 0 {
+			if items, ok := node["items"]; ok {
+				node = mergeScaffoldSchema(schema, items)
+			}
+		}
+	}
+	return scaffoldType(node)
+}
+
+func convertListingAttributeValue(text string, typ string) (any, error) {
+	switch typ {
+	case "integer":
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an integer", text)
+		}
+		return value, nil
+	case "number":
+		value, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", "."), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number", text)
+		}
+		return value, nil
+	case "boolean":
+		switch strings.ToLower(text) {
+		case "true", "yes", "1":
+			return true, nil
+		case "false", "no", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%s is not a boolean", text)
+	}
+	return text, nil
+}
+
+func setListingAttributePath(container map[string]any, segments []listingAttributePathSegment, value any) {
+	segment, last := segments[0], len(segments) == 1
+	if segment.Index < 0 {
+		if last {
+			container[segment.Name] = value
+			return
+		}
+		child, _ := container[segment.Name].(map[string]any)
+		if child == nil {
+			child = make(map[string]any)
+			container[segment.Name] = child
+		}
+		setListingAttributePath(child, segments[1:], value)
+		return
+	}
+	list, _ := container[segment.Name].([]any)
+	for len(list) <= segment.Index {
+		list = append(list, nil)
+	}
+	if last {
+		list[segment.Index] = value
+	} else {
+		child, _ := list[segment.Index].(map[string]any)
+		if child == nil {
+			child = make(map[string]any)
+			list[segment.Index] = child
+		}
+		setListingAttributePath(child, segments[1:], value)
+	}
+	container[segment.Name] = list
+}
+
+// compactListingAttributes drops array items that no column filled, such as bullet_point[0] when only bullet_point[1] has a value.
+func compactListingAttributes(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			v[key] = compactListingAttributes(child)
+		}
+		return v
+	case []any:
+		compacted := make([]any, 0, len(v))
+		for _, child := range v {
+			if child != nil {
+				compacted = append(compacted, compactListingAttributes(child))
+			}
+		}
+		return compacted
+	}
+	return value
+}
+
+func submitListingsBulkRow(app AppCtx, row listingsBulkRow) listingsBulkResult {
+	result := listingsBulkResult{Row: row.Row, SKU: row.SKU}
+	var params listings.PutListingsItemParams
+	params.MarketplaceIds = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
+	params.IncludedData = internal.Ptr([]listings.PutListingsItemParamsIncludedData{"issues"})
+	if bulkListingsCfg.DryRun {
+		params.Mode = internal.Ptr(listings.PutListingsItemParamsMode(listingsModeValidationPreview))
+	}
+	body := listings.ListingsItemPutRequest{ProductType: bulkListingsCfg.ProductType, Attributes: row.Attributes}
+	if bulkListingsCfg.Requirements != "" {
+		body.Requirements = internal.Ptr(listings.ListingsItemPutRequestRequirements(bulkListingsCfg.Requirements))
+	}
+	status, err := app.Amazon.Client.PutListingsItem(app.Ctx, cfg.Amazon.Auth.DefaultMerchant.SellerToken, row.SKU, &params, body)
+	if err != nil {
+		log.Error().Err(err).Int("row", row.Row).Str("sku", row.SKU).Msg("failed to submit listing")
+		result.Status = listingsBulkStatusError
+		result.Issues = []string{err.Error()}
+		return result
+	}
+	response := status.JSON200
+	result.Status = string(response.Status)
+	result.SubmissionID = response.SubmissionId
+	if response.Issues != nil {
+		for _, issue := range *response.Issues {
+			text := fmt.Sprintf("%s %s: %s", issue.Severity, issue.Code, issue.Message)
+			if issue.AttributeNames != nil {
+				text += fmt.Sprintf(" (%s)", strings.Join(*issue.AttributeNames, ","))
+			}
+			result.Issues = append(result.Issues, text)
+		}
+	}
+	log.Info().Int("row", row.Row).Str("sku", row.SKU).Str("status", result.Status).Str("submission_id", result.SubmissionID).Int("issues", len(result.Issues)).Send()
+	return result
+}
+
+func displayListingsBulkResults(results []listingsBulkResult) {
+	fmt.Printf("\n%-6s %-30s %-12s %-40s %s\n", "Row", "SKU", "Status", "Submission ID", "Issues")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------------------------"))
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+		issue := "-"
+		if len(result.Issues) > 0 {
+			issue = fmt.Sprintf("%d, %s", len(result.Issues), result.Issues[0])
+		}
+		line := fmt.Sprintf("%-6d %-30s %-12s %-40s %s", result.Row, truncateString(result.SKU, 30), result.Status, valueOrDash(result.SubmissionID), truncateString(issue, 80))
+		switch result.Status {
+		case "ACCEPTED", "VALID":
+			fmt.Println(color.GreenString(line))
+		case listingsBulkStatusSkipped, listingsBulkStatusError, "INVALID":
+			fmt.Println(color.RedString(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	summary := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	fmt.Printf("\n%d rows: %s\n", len(results), strings.Join(summary, ", "))
+}
+
+func listingsBulkExportTable(results []listingsBulkResult) export.Table {
+	table := export.Table{
+		Name: "results",
+		Columns: []export.Column{
+			{Name: "Row", Key: "row", Type: export.Int},
+			{Name: "SKU", Key: "sku", Type: export.String},
+			{Name: "Status", Key: "status", Type: export.String},
+			{Name: "Submission ID", Key: "submission_id", Type: export.String},
+			{Name: "Issue Count", Key: "issue_count", Type: export.Int},
+			{Name: "Issues", Key: "issues", Type: export.String},
+		},
+	}
+	for _, result := range results {
+		var submissionId any
+		if result.SubmissionID != "" {
+			submissionId = result.SubmissionID
+		}
+		table.Rows = append(table.Rows, []any{result.Row, result.SKU, result.Status, submissionId, len(result.Issues), strings.Join(result.Issues, "\n")})
+	}
+	return table
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/caner-cetin/halycon/internal/jsonschema"
+)
+
+const listingsBulkSchema = `{
+  "$defs": {"price": {"type": "number"}},
+  "properties": {
+    "item_name": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "string"}}}},
+    "list_price": {"type": "array", "items": {"type": "object", "properties": {
+      "value_with_tax": {"$ref": "#/$defs/price"},
+      "currency": {"type": "string"}
+    }}},
+    "number_of_items": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "integer"}}}},
+    "is_fragile": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "boolean"}}}},
+    "bullet_point": {"type": "array", "items": {"type": "object", "properties": {"value": {"type": "string"}}}}
+  }
+}`
+
+func compileListingsBulkSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+	schema, err := jsonschema.Compile([]byte(listingsBulkSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestParseListingAttributePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []listingAttributePathSegment
+		wantErr bool
+	}{
+		{path: "brand", want: []listingAttributePathSegment{{Name: "brand", Index: -1}}},
+		{
+			path: "list_price[0].value_with_tax",
+			want: []listingAttributePathSegment{{Name: "list_price", Index: 0}, {Name: "value_with_tax", Index: -1}},
+		},
+		{
+			path: "item_package_dimensions[1].length[2].value",
+			want: []listingAttributePathSegment{
+				{Name: "item_package_dimensions", Index: 1},
+				{Name: "length", Index: 2},
+				{Name: "value", Index: -1},
+			},
+		},
+		{path: " bullet_point[10] . value ", want: []listingAttributePathSegment{{Name: "bullet_point", Index: 10}, {Name: "value", Index: -1}}},
+		{path: "", wantErr: true},
+		{path: "item_name..value", wantErr: true},
+		{path: "item_name[-1].value", wantErr: true},
+		{path: "item_name[].value", wantErr: true},
+		{path: "item-name[0]", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parseListingAttributePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseListingAttributePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseListingAttributePath(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertListingAttributeValue(t *testing.T) {
+	schema := compileListingsBulkSchema(t)
+	tests := []struct {
+		path    string
+		text    string
+		want    any
+		wantErr bool
+	}{
+		{path: "number_of_items[0].value", text: "12", want: int64(12)},
+		{path: "number_of_items[0].value", text: "1.5", wantErr: true},
+		{path: "list_price[0].value_with_tax", text: "19,99", want: 19.99},
+		{path: "list_price[0].value_with_tax", text: "20", want: 20.0},
+		{path: "list_price[0].value_with_tax", text: "free", wantErr: true},
+		{path: "list_price[0].currency", text: "100", want: "100"},
+		{path: "is_fragile[0].value", text: "Yes", want: true},
+		{path: "is_fragile[0].value", text: "0", want: false},
+		{path: "is_fragile[0].value", text: "maybe", wantErr: true},
+		{path: "item_name[0].value", text: "true", want: "true"},
+		{path: "unknown[0].value", text: "42", want: "42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+"="+tt.text, func(t *testing.T) {
+			segments, err := parseListingAttributePath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := convertListingAttributeValue(tt.text, listingAttributePathType(schema, segments))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertListingAttributeValue(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("convertListingAttributeValue(%q) = %#v, want %#v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetListingAttributePath(t *testing.T) {
+	type value struct {
+		path  string
+		value any
+	}
+	tests := []struct {
+		name   string
+		values []value
+		want   map[string]any
+	}{
+		{
+			name:   "property",
+			values: []value{{"brand", "Acme"}},
+			want:   map[string]any{"brand": "Acme"},
+		},
+		{
+			name:   "nested array",
+			values: []value{{"list_price[0].value_with_tax", 19.99}, {"list_price[0].currency", "USD"}},
+			want:   map[string]any{"list_price": []any{map[string]any{"value_with_tax": 19.99, "currency": "USD"}}},
+		},
+		{
+			name:   "sparse index",
+			values: []value{{"bullet_point[2].value", "third"}},
+			want:   map[string]any{"bullet_point": []any{nil, nil, map[string]any{"value": "third"}}},
+		},
+		{
+			name:   "arrays of arrays",
+			values: []value{{"dimensions[0].length[1].value", int64(3)}, {"dimensions[0].length[0].value", int64(2)}},
+			want: map[string]any{"dimensions": []any{map[string]any{"length": []any{
+				map[string]any{"value": int64(2)},
+				map[string]any{"value": int64(3)},
+			}}}},
+		},
+		{
+			name:   "scalar array",
+			values: []value{{"keywords[1]", "b"}, {"keywords[0]", "a"}},
+			want:   map[string]any{"keywords": []any{"a", "b"}},
+		},
+		{
+			name:   "overwrite",
+			values: []value{{"item_name[0].value", "old"}, {"item_name[0].value", "new"}},
+			want:   map[string]any{"item_name": []any{map[string]any{"value": "new"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]any)
+			for _, v := range tt.values {
+				segments, err := parseListingAttributePath(v.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				setListingAttributePath(got, segments, v.value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setListingAttributePath() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactListingAttributes(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{
+			name:  "sparse index",
+			value: map[string]any{"bullet_point": []any{nil, map[string]any{"value": "second"}, nil, map[string]any{"value": "fourth"}}},
+			want:  map[string]any{"bullet_point": []any{map[string]any{"value": "second"}, map[string]any{"value": "fourth"}}},
+		},
+		{
+			name:  "nested arrays",
+			value: map[string]any{"dimensions": []any{nil, map[string]any{"length": []any{nil, map[string]any{"value": 3.0}}}}},
+			want:  map[string]any{"dimensions": []any{map[string]any{"length": []any{map[string]any{"value": 3.0}}}}},
+		},
+		{
+			name:  "empty array",
+			value: map[string]any{"keywords": []any{nil, nil}},
+			want:  map[string]any{"keywords": []any{}},
+		},
+		{
+			name:  "dense",
+			value: map[string]any{"brand": "Acme", "keywords": []any{"a", false, int64(0)}},
+			want:  map[string]any{"brand": "Acme", "keywords": []any{"a", false, int64(0)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compactListingAttributes(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compactListingAttributes() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildListingsBulkRows(t *testing.T) {
+	previousMarketplaces, previousLanguageTag := cfg.Amazon.Auth.DefaultMerchant.MarketplaceID, cfg.Amazon.DefaultLanguageTag
+	t.Cleanup(func() {
+		cfg.Amazon.Auth.DefaultMerchant.MarketplaceID, cfg.Amazon.DefaultLanguageTag = previousMarketplaces, previousLanguageTag
+	})
+	cfg.Amazon.Auth.DefaultMerchant.MarketplaceID, cfg.Amazon.DefaultLanguageTag = []string{"ATVPDKIKX0DER"}, "en_US"
+
+	schema := compileListingsBulkSchema(t)
+	mapping := listingsBulkMapping{
+		SKU: "SKU",
+		Columns: map[string]string{
+			"Title":    "item_name[0].value",
+			"Price":    "list_price[0].value_with_tax",
+			"Count":    "number_of_items[0].value",
+			"Bullet 1": "bullet_point[0].value",
+			"Bullet 2": "bullet_point[1].value",
+		},
+		Attributes: map[string]any{"list_price[0].currency": "USD", "is_fragile[0].value": "no"},
+	}
+	records := [][]string{
+		{"sku", "title", "price", "count", "bullet 1", "bullet 2"},
+		{"A-1", "Charger", "19,99", "2", "", "Fast"},
+		{"", "", "", "", "", ""},
+		{"B-2", "Cable", "free", "1", "", ""},
+		{"", "Plug", "5", "1"},
+	}
+
+	tests := []struct {
+		name              string
+		fillMarketplaceId bool
+		fillLanguageTag   bool
+		wantA1            map[string]any
+	}{
+		{
+			name: "no autofill",
+			wantA1: map[string]any{
+				"item_name":       []any{map[string]any{"value": "Charger"}},
+				"list_price":      []any{map[string]any{"value_with_tax": 19.99, "currency": "USD"}},
+				"number_of_items": []any{map[string]any{"value": 2.0}},
+				"is_fragile":      []any{map[string]any{"value": false}},
+				"bullet_point":    []any{map[string]any{"value": "Fast"}},
+			},
+		},
+		{
+			name:              "autofill",
+			fillMarketplaceId: true,
+			fillLanguageTag:   true,
+			wantA1: map[string]any{
+				"item_name":       []any{map[string]any{"value": "Charger", "marketplace_id": "ATVPDKIKX0DER", "language_tag": "en_US"}},
+				"list_price":      []any{map[string]any{"value_with_tax": 19.99, "currency": "USD", "marketplace_id": "ATVPDKIKX0DER", "language_tag": "en_US"}},
+				"number_of_items": []any{map[string]any{"value": 2.0, "marketplace_id": "ATVPDKIKX0DER", "language_tag": "en_US"}},
+				"is_fragile":      []any{map[string]any{"value": false, "marketplace_id": "ATVPDKIKX0DER", "language_tag": "en_US"}},
+				"bullet_point":    []any{map[string]any{"value": "Fast", "marketplace_id": "ATVPDKIKX0DER", "language_tag": "en_US"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, results, err := buildListingsBulkRows(schema, mapping, records, tt.fillMarketplaceId, tt.fillLanguageTag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantResults := []listingsBulkResult{
+				{Row: 2, SKU: "A-1"},
+				{Row: 4, SKU: "B-2", Status: listingsBulkStatusError, Issues: []string{"list_price[0].value_with_tax: free is not a number"}},
+				{Row: 5, Status: listingsBulkStatusError, Issues: []string{"SKU column is empty"}},
+			}
+			if !slices.EqualFunc(results, wantResults, func(a, b listingsBulkResult) bool { return reflect.DeepEqual(a, b) }) {
+				t.Fatalf("buildListingsBulkRows() results = %+v, want %+v", results, wantResults)
+			}
+			if len(rows) != len(wantResults) {
+				t.Fatalf("buildListingsBulkRows() returned %d rows, want %d", len(rows), len(wantResults))
+			}
+			if !reflect.DeepEqual(rows[0].Attributes, tt.wantA1) {
+				t.Errorf("attributes of A-1 = %#v, want %#v", rows[0].Attributes, tt.wantA1)
+			}
+			if rows[1].Attributes != nil || rows[2].Attributes != nil {
+				t.Errorf("rows with issues have attributes: %#v, %#v", rows[1].Attributes, rows[2].Attributes)
+			}
+		})
+	}
+
+	t.Run("unknown column", func(t *testing.T) {
+		unknown := mapping
+		unknown.Columns = map[string]string{"Color": "color[0].value"}
+		if _, _, err := buildListingsBulkRows(schema, unknown, records, false, false); err == nil {
+			t.Error("buildListingsBulkRows() with a column missing from the header returned no error")
+		}
+	})
+}
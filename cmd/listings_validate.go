@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/listings"
+	"github.com/caner-cetin/halycon/internal/jsonschema"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type validateListingConfig struct {
+	Input                 string
+	ProductType           string
+	Requirements          string
+	AutofillMarketplaceId bool
+	AutofillLanguageTag   bool
+}
+
+var (
+	validateListingCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "validates listing attributes or a patch file against the product type schema without submitting them",
+		Run:   WrapCommandWithResources(validateListings, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceProductTypeDefinitions}}),
+	}
+	validateListingCfg validateListingConfig
+)
+
+func getValidateListingCmd() *cobra.Command {
+	flags := validateListingCmd.PersistentFlags()
+	flags.StringVarP(&validateListingCfg.Input, "input", "i", "", "attributes JSON or YAML file as given to listings create, or a patch file as given to listings patch")
+	flags.StringVarP(&validateListingCfg.ProductType, "type", "p", "", "product type, required for attributes files")
+	flags.StringVarP(&validateListingCfg.Requirements, "requirements", "r", "", "requirements of the schema (LISTING, LISTING_PRODUCT_ONLY or LISTING_OFFER_ONLY)")
+	flags.BoolVar(&validateListingCfg.AutofillMarketplaceId, "fill-marketplace-id", false, "adds {\"marketplace_id\": ...} to every json object in attributes")
+	flags.BoolVar(&validateListingCfg.AutofillLanguageTag, "fill-language-tag", false, "adds {\"language_tag\": ...} to every json object in attributes")
+	validateListingCmd.MarkPersistentFlagRequired("input") //nolint:errcheck
+	return validateListingCmd
+}
+
+func validateListings(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	raw, err := internal.ReadFile(validateListingCfg.Input)
+	if err != nil {
+		log.Error().Err(err).Str("input", validateListingCfg.Input).Msg("failed to read input")
+		return
+	}
+	var probe struct {
+		Patches json.RawMessage `json:"patches"`
+	}
+	if ext := strings.ToLower(filepath.Ext(validateListingCfg.Input)); ext != ".yaml" && ext != ".yml" {
+		if err := json.Unmarshal(raw, &probe); err != nil {
+			log.Error().Err(err).Str("input", validateListingCfg.Input).Msg("failed to parse input")
+			return
+		}
+	}
+
+	var problems []jsonschema.Error
+	if probe.Patches != nil {
+		productType, patches, err := readListingPatches(validateListingCfg.Input)
+		if err != nil {
+			log.Error().Err(err).Str("input", validateListingCfg.Input).Msg("failed to read patches")
+			return
+		}
+		schema, err := loadProductTypeSchema(app, productType, validateListingCfg.Requirements)
+		if err != nil {
+			log.Error().Err(err).Str("product_type", productType).Msg("failed to load product type schema")
+			return
+		}
+		if problems, err = validateListingPatches(schema, patches); err != nil {
+			log.Error().Err(err).Msg("failed to validate patches")
+			return
+		}
+	} else {
+		if validateListingCfg.ProductType == "" {
+			log.Error().Msg("product type is required to validate attributes, pass it with --type")
+			return
+		}
+		attributes, err := loadListingAttributes(validateListingCfg.Input, validateListingCfg.AutofillMarketplaceId, validateListingCfg.AutofillLanguageTag)
+		if err != nil {
+			log.Error().Err(err).Str("input", validateListingCfg.Input).Msg("failed to read attributes")
+			return
+		}
+		schema, err := loadProductTypeSchema(app, validateListingCfg.ProductType, validateListingCfg.Requirements)
+		if err != nil {
+			log.Error().Err(err).Str("product_type", validateListingCfg.ProductType).Msg("failed to load product type schema")
+			return
+		}
+		problems = schema.Validate(attributes)
+	}
+	if len(problems) == 0 {
+		color.Green("no problems found")
+		return
+	}
+	displayListingValidationErrors(problems)
+}
+
+// loadProductTypeSchema compiles the schema of the latest definition of a product type for the default marketplaces, served
+// from the local database while it is cached, see loadProductTypeDefinition.
+func loadProductTypeSchema(app AppCtx, productType string, requirements string) (*jsonschema.Schema, error) {
+	definition, err := loadProductTypeDefinition(app, productType, productTypeDefinitionParams(requirements, ""), "", false)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.Compile(definition.Schema)
+}
+
+// validateListingPatches validates the values of add, replace and merge patches of whole attributes against their schemas.
+// Required attributes are not checked, since patches only change some of the attributes of a listing.
+func validateListingPatches(schema *jsonschema.Schema, patches []listings.PatchOperation) ([]jsonschema.Error, error) {
+	var problems []jsonschema.Error
+	for i, patch := range patches {
+		if patch.Op == "delete" || patch.Value == nil {
+			continue
+		}
+		name, ok := strings.CutPrefix(patch.Path, "/attributes/")
+		if !ok || name == "" {
+			problems = append(problems, jsonschema.Error{Path: fmt.Sprintf("$.patches[%d].path", i), Keyword: "path", Message: fmt.Sprintf("%s is not an attribute path such as /attributes/item_name", patch.Path)})
+			continue
+		}
+		if strings.Contains(name, "/") {
+			// values of nested paths are fragments of an attribute, only whole attributes are validated
+			continue
+		}
+		encoded, err := json.Marshal(*patch.Value)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if err := json.Unmarshal(encoded, &value); err != nil {
+			return nil, err
+		}
+		problems = append(problems, schema.ValidateProperty(name, value)...)
+	}
+	return problems, nil
+}
+
+func displayListingValidationErrors(problems []jsonschema.Error) {
+	fmt.Printf("%-50s %-20s %s\n", "Path", "Keyword", "Message")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------"))
+	for _, problem := range problems {
+		fmt.Printf("%-50s %-20s %s\n", problem.Path, problem.Keyword, color.RedString("%s", problem.Message))
+	}
+	fmt.Printf("\n%s\n", color.RedString("%d problems", len(problems)))
+}
+
+// checkListingAttributes validates attributes before they are submitted, and reports whether the submission should continue.
+func checkListingAttributes(app AppCtx, productType string, requirements string, attributes map[string]interface{}) bool {
+	schema, err := loadProductTypeSchema(app, productType, requirements)
+	if err != nil {
+		log.Error().Err(err).Str("product_type", productType).Msg("failed to load product type schema, rerun with --skip-validation to submit without validating")
+		return false
+	}
+	problems := schema.Validate(attributes)
+	if len(problems) == 0 {
+		return true
+	}
+	displayListingValidationErrors(problems)
+	log.Error().Msg("attributes do not match the product type schema, rerun with --skip-validation to submit anyway")
+	return false
+}
+
+// checkListingPatches validates patches before they are submitted, and reports whether the submission should continue.
+func checkListingPatches(app AppCtx, productType string, patches []listings.PatchOperation) bool {
+	schema, err := loadProductTypeSchema(app, productType, "")
+	if err != nil {
+		log.Error().Err(err).Str("product_type", productType).Msg("failed to load product type schema, rerun with --skip-validation to submit without validating")
+		return false
+	}
+	problems, err := validateListingPatches(schema, patches)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to validate patches")
+		return false
+	}
+	if len(problems) == 0 {
+		return true
+	}
+	displayListingValidationErrors(problems)
+	log.Error().Msg("patches do not match the product type schema, rerun with --skip-validation to submit anyway")
+	return false
+}
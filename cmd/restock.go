@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type restockInventoryConfig struct {
+	Output          string
+	LeadTimeDays    int
+	TargetCoverDays int
+	LookbackDays    int
+	ShowAll         bool
+	Export          exportConfig
+}
+
+// RestockRecommendation is the computed restock state of a single SKU.
+type RestockRecommendation struct {
+	SKU   string
+	ASIN  string
+	Title string
+	// Fulfillable is the fulfillable quantity in the latest snapshot.
+	Fulfillable int
+	// Inbound is the working, shipped and receiving quantity in the latest snapshot.
+	Inbound int
+	// UnitsSold is the number of units that left the warehouse within the lookback window.
+	UnitsSold int
+	// HistoryDays is the time span between the first and the latest snapshot within the lookback window.
+	HistoryDays float64
+	// Velocity is the sell-through velocity in units per day.
+	Velocity float64
+	// DaysOfCover is how many days the fulfillable quantity lasts with the current velocity, +Inf if nothing is selling.
+	DaysOfCover float64
+	// ReorderPoint is the inventory position (fulfillable + inbound) at which a restock must be placed to not run out within lead time.
+	ReorderPoint int
+	// Quantity is the recommended number of units to send, zero if inventory position is at or above reorder point.
+	Quantity int
+}
+
+var (
+	restockInventoryCmd = &cobra.Command{
+		Use:   "restock",
+		Short: "computes reorder quantities from inventory snapshot history and outputs a shipment input csv",
+		Run:   WrapCommandWithResources(restockInventory, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+	restockInventoryCfg restockInventoryConfig
+)
+
+func getRestockInventoryCmd() *cobra.Command {
+	flags := restockInventoryCmd.PersistentFlags()
+	flags.StringVarP(&restockInventoryCfg.Output, "output", "o", "", "output csv in shipment create input format (default restock_<timestamp>.csv)")
+	flags.IntVar(&restockInventoryCfg.LeadTimeDays, "lead-time", 0, "days between ordering units and them becoming fulfillable (default from config, 30)")
+	flags.IntVar(&restockInventoryCfg.TargetCoverDays, "target-cover", 0, "days of sales a restock should cover once it arrives (default from config, 60)")
+	flags.IntVar(&restockInventoryCfg.LookbackDays, "lookback", 0, "days of snapshot history used for computing velocity (default from config, 30)")
+	flags.BoolVar(&restockInventoryCfg.ShowAll, "all", false, "also display SKUs that do not need restocking")
+	addExportFlags(flags, &restockInventoryCfg.Export, "restock_report")
+	return restockInventoryCmd
+}
+
+func restockInventory(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	leadTime := restockInventoryCfg.LeadTimeDays
+	if leadTime == 0 {
+		leadTime = cfg.Amazon.FBA.Restock.LeadTimeDays
+	}
+	targetCover := restockInventoryCfg.TargetCoverDays
+	if targetCover == 0 {
+		targetCover = cfg.Amazon.FBA.Restock.TargetCoverDays
+	}
+	lookback := restockInventoryCfg.LookbackDays
+	if lookback == 0 {
+		lookback = cfg.Amazon.FBA.Restock.LookbackDays
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -lookback)
+	snapshots, err := app.Query.GetFBAInventorySnapshotsSince(app.Ctx, since)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to query inventory snapshots")
+		return
+	}
+	if len(snapshots) == 0 {
+		log.Error().Int("lookback_days", lookback).Msg("no inventory snapshots found, run `inventory build --force-rebuild` periodically to record history")
+		return
+	}
+
+	recommendations := computeRestockRecommendations(snapshots, leadTime, targetCover)
+	displayRestockRecommendations(recommendations, restockInventoryCfg.ShowAll)
+	if restockInventoryCfg.Export.Enabled() {
+		if err := writeExport(restockInventoryCfg.Export, "restock_report", restockExportTable(recommendations)); err != nil {
+			log.Error().Err(err).Msg("failed to export restock report")
+			return
+		}
+	}
+
+	var restock []RestockRecommendation
+	for _, recommendation := range recommendations {
+		if recommendation.Quantity > 0 {
+			restock = append(restock, recommendation)
+		}
+	}
+	if len(restock) == 0 {
+		color.Green("no SKU needs restocking with lead time of %d days and target cover of %d days", leadTime, targetCover)
+		return
+	}
+
+	output := restockInventoryCfg.Output
+	if output == "" {
+		output = fmt.Sprintf("restock_%s.csv", time.Now().Format("2006-01-02_15-04-05"))
+	}
+	rows := make([]ShipmentInputRow, 0, len(restock))
+	for _, r := range restock {
+		rows = append(rows, ShipmentInputRow{ASIN: r.ASIN, SKU: r.SKU, Title: r.Title, Quantity: r.Quantity})
+	}
+	if err := writeShipmentInputCSV(output, rows); err != nil {
+		log.Error().Err(err).Str("file", output).Msg("failed to write restock csv")
+		return
+	}
+	log.Info().Str("file", output).Int("sku_count", len(restock)).Msg("saved csv")
+	fmt.Printf("%s %s (%d SKUs), use with: halycon shipment create -i %s\n", color.GreenString("Restock file:"), output, len(restock), output)
+}
+
+// computeRestockRecommendations groups snapshots by SKU and computes velocity, days of cover and reorder quantities.
+//
+// Units sold are the decreases of fulfillable + reserved quantity between consecutive snapshots.
+// Reserved quantity is included so that orders waiting to be shipped or units in between fulfillment
+// centers are not counted as sold twice, and increases (received shipments) are ignored. Units that
+// became unfulfillable (damaged, or waiting for removal) in the same period are not sold, so increases
+// of unfulfillable quantity are subtracted.
+//
+// Snapshots are expected to be ordered by SKU and snapshot time.
+func computeRestockRecommendations(snapshots []db.FbaInventorySnapshot, leadTimeDays int, targetCoverDays int) []RestockRecommendation {
+	var recommendations []RestockRecommendation
+	for start := 0; start < len(snapshots); {
+		end := start
+		for end < len(snapshots) && snapshots[end].Sku == snapshots[start].Sku {
+			end++
+		}
+		history := snapshots[start:end]
+		start = end
+
+		first, latest := history[0], history[len(history)-1]
+		recommendation := RestockRecommendation{
+			SKU:         latest.Sku,
+			ASIN:        latest.Asin.String,
+			Title:       latest.Title.String,
+			Fulfillable: int(latest.FulfillableQuantity.Int64),
+			Inbound:     int(latest.InboundWorkingQuantity.Int64 + latest.InboundShippedQuantity.Int64 + latest.InboundReceivingQuantity.Int64),
+			HistoryDays: latest.SnapshotAt.Sub(first.SnapshotAt).Hours() / 24,
+			DaysOfCover: math.Inf(1),
+		}
+		for i := 1; i < len(history); i++ {
+			previous := history[i-1].FulfillableQuantity.Int64 + history[i-1].ReservedQuantity.Int64
+			current := history[i].FulfillableQuantity.Int64 + history[i].ReservedQuantity.Int64
+			damaged := max(history[i].UnfulfillableQuantity.Int64-history[i-1].UnfulfillableQuantity.Int64, 0)
+			if sold := previous - current - damaged; sold > 0 {
+				recommendation.UnitsSold += int(sold)
+			}
+		}
+		if recommendation.HistoryDays > 0 {
+			recommendation.Velocity = float64(recommendation.UnitsSold) / recommendation.HistoryDays
+		}
+		if recommendation.Velocity > 0 {
+			recommendation.DaysOfCover = float64(recommendation.Fulfillable) / recommendation.Velocity
+			recommendation.ReorderPoint = int(math.Ceil(recommendation.Velocity * float64(leadTimeDays)))
+			position := recommendation.Fulfillable + recommendation.Inbound
+			if position < recommendation.ReorderPoint {
+				target := int(math.Ceil(recommendation.Velocity * float64(leadTimeDays+targetCoverDays)))
+				recommendation.Quantity = target - position
+			}
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].DaysOfCover < recommendations[j].DaysOfCover
+	})
+	return recommendations
+}
+
+func displayRestockRecommendations(recommendations []RestockRecommendation, showAll bool) {
+	fmt.Printf("%-30s %-12s %8s %8s %8s %10s %8s %8s\n", "SKU", "ASIN", "Fulfill", "Inbound", "Sold/d", "Cover (d)", "ROP", "Reorder")
+	fmt.Println(color.HiBlackString("%s", "-----------------------------------------------------------------------------------------------"))
+	for _, r := range recommendations {
+		if !showAll && r.Quantity == 0 {
+			continue
+		}
+		cover := "-"
+		if !math.IsInf(r.DaysOfCover, 1) {
+			cover = strconv.FormatFloat(r.DaysOfCover, 'f', 1, 64)
+		}
+		line := fmt.Sprintf("%-30s %-12s %8d %8d %8.2f %10s %8d %8d", truncateString(r.SKU, 30), r.ASIN, r.Fulfillable, r.Inbound, r.Velocity, cover, r.ReorderPoint, r.Quantity)
+		switch {
+		case r.Quantity > 0 && r.Fulfillable == 0:
+			fmt.Println(color.RedString(line))
+		case r.Quantity > 0:
+			fmt.Println(color.YellowString(line))
+		case r.HistoryDays == 0:
+			fmt.Println(color.HiBlackString("%s (not enough history)", line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+// restockExportTable contains every SKU, including the ones that do not need restocking.
+func restockExportTable(recommendations []RestockRecommendation) export.Table {
+	table := export.Table{
+		Name: "restock",
+		Columns: []export.Column{
+			{Name: "SKU", Key: "sku", Type: export.String},
+			{Name: "ASIN", Key: "asin", Type: export.String},
+			{Name: "Title", Key: "title", Type: export.String},
+			{Name: "Fulfillable", Key: "fulfillable", Type: export.Int},
+			{Name: "Inbound", Key: "inbound", Type: export.Int},
+			{Name: "Units Sold", Key: "units_sold", Type: export.Int},
+			{Name: "History Days", Key: "history_days", Type: export.Float},
+			{Name: "Velocity", Key: "velocity", Type: export.Float},
+			{Name: "Days Of Cover", Key: "days_of_cover", Type: export.Float},
+			{Name: "Reorder Point", Key: "reorder_point", Type: export.Int},
+			{Name: "Quantity", Key: "quantity", Type: export.Int},
+		},
+	}
+	for _, r := range recommendations {
+		var cover any
+		if !math.IsInf(r.DaysOfCover, 1) {
+			cover = r.DaysOfCover
+		}
+		table.Rows = append(table.Rows, []any{r.SKU, r.ASIN, r.Title, r.Fulfillable, r.Inbound, r.UnitsSold, r.HistoryDays, r.Velocity, cover, r.ReorderPoint, r.Quantity})
+	}
+	return table
+}
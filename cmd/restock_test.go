@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"cmp"
+	"database/sql"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal/db"
+)
+
+// quantities of a snapshot, taken day days after the first snapshot.
+type snapshotCounts struct {
+	day           int
+	fulfillable   int64
+	reserved      int64
+	unfulfillable int64
+	inbound       int64
+}
+
+func inventoryHistory(sku string, quantities ...snapshotCounts) []db.FbaInventorySnapshot {
+	start := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := make([]db.FbaInventorySnapshot, 0, len(quantities))
+	for _, q := range quantities {
+		snapshots = append(snapshots, db.FbaInventorySnapshot{
+			SnapshotAt:             start.AddDate(0, 0, q.day),
+			Sku:                    sku,
+			Asin:                   sql.NullString{String: "B0" + sku, Valid: true},
+			FulfillableQuantity:    sql.NullInt64{Int64: q.fulfillable, Valid: true},
+			ReservedQuantity:       sql.NullInt64{Int64: q.reserved, Valid: true},
+			UnfulfillableQuantity:  sql.NullInt64{Int64: q.unfulfillable, Valid: true},
+			InboundShippedQuantity: sql.NullInt64{Int64: q.inbound, Valid: true},
+		})
+	}
+	return snapshots
+}
+
+func TestComputeRestockRecommendations(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshots []db.FbaInventorySnapshot
+		// leadTime and targetCover default to 30 and 60 days
+		leadTime    int
+		targetCover int
+		want        RestockRecommendation
+	}{
+		{
+			name: "steady sales below reorder point",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 100},
+				snapshotCounts{day: 5, fulfillable: 80},
+				snapshotCounts{day: 10, fulfillable: 50},
+			),
+			// 50 units in 10 days, reorder point 5 * 30, target 5 * (30 + 60) - 50
+			want: RestockRecommendation{Fulfillable: 50, UnitsSold: 50, HistoryDays: 10, Velocity: 5, DaysOfCover: 10, ReorderPoint: 150, Quantity: 400},
+		},
+		{
+			name: "received shipments are ignored",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 20},
+				snapshotCounts{day: 5, fulfillable: 10},
+				snapshotCounts{day: 6, fulfillable: 110},
+				snapshotCounts{day: 10, fulfillable: 100},
+			),
+			want: RestockRecommendation{Fulfillable: 100, UnitsSold: 20, HistoryDays: 10, Velocity: 2, DaysOfCover: 50, ReorderPoint: 60, Quantity: 0},
+		},
+		{
+			name: "reserved transfers are not sales",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 100},
+				snapshotCounts{day: 5, fulfillable: 70, reserved: 30},
+				snapshotCounts{day: 10, fulfillable: 90, reserved: 0},
+			),
+			want: RestockRecommendation{Fulfillable: 90, UnitsSold: 10, HistoryDays: 10, Velocity: 1, DaysOfCover: 90, ReorderPoint: 30, Quantity: 0},
+		},
+		{
+			name: "damaged units are not sales",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 100},
+				snapshotCounts{day: 5, fulfillable: 85, unfulfillable: 10},
+				snapshotCounts{day: 10, fulfillable: 80, unfulfillable: 10},
+			),
+			// 15 units left fulfillable in the first period, 10 of them became unfulfillable
+			want: RestockRecommendation{Fulfillable: 80, UnitsSold: 10, HistoryDays: 10, Velocity: 1, DaysOfCover: 80, ReorderPoint: 30, Quantity: 0},
+		},
+		{
+			name: "removed unfulfillable units do not offset sales",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 100, unfulfillable: 10},
+				snapshotCounts{day: 10, fulfillable: 90, unfulfillable: 0},
+			),
+			want: RestockRecommendation{Fulfillable: 90, UnitsSold: 10, HistoryDays: 10, Velocity: 1, DaysOfCover: 90, ReorderPoint: 30, Quantity: 0},
+		},
+		{
+			name:      "single snapshot has no history",
+			snapshots: inventoryHistory("A", snapshotCounts{day: 0, fulfillable: 5}),
+			want:      RestockRecommendation{Fulfillable: 5, DaysOfCover: math.Inf(1)},
+		},
+		{
+			name: "no sales",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 0},
+				snapshotCounts{day: 10, fulfillable: 0},
+			),
+			want: RestockRecommendation{HistoryDays: 10, DaysOfCover: math.Inf(1)},
+		},
+		{
+			name: "position at reorder point",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 80},
+				snapshotCounts{day: 10, fulfillable: 40, inbound: 20},
+			),
+			leadTime:    15,
+			targetCover: 30,
+			// reorder point 4 * 15 is equal to 40 fulfillable + 20 inbound
+			want: RestockRecommendation{Fulfillable: 40, Inbound: 20, UnitsSold: 40, HistoryDays: 10, Velocity: 4, DaysOfCover: 10, ReorderPoint: 60, Quantity: 0},
+		},
+		{
+			name: "position below reorder point counts inbound",
+			snapshots: inventoryHistory("A",
+				snapshotCounts{day: 0, fulfillable: 80},
+				snapshotCounts{day: 10, fulfillable: 40, inbound: 19},
+			),
+			leadTime:    15,
+			targetCover: 30,
+			// target 4 * (15 + 30) - 59
+			want: RestockRecommendation{Fulfillable: 40, Inbound: 19, UnitsSold: 40, HistoryDays: 10, Velocity: 4, DaysOfCover: 10, ReorderPoint: 60, Quantity: 121},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommendations := computeRestockRecommendations(tt.snapshots, cmp.Or(tt.leadTime, 30), cmp.Or(tt.targetCover, 60))
+			if len(recommendations) != 1 {
+				t.Fatalf("got %d recommendations, want 1", len(recommendations))
+			}
+			got := recommendations[0]
+			want := tt.want
+			want.SKU, want.ASIN = "A", "B0A"
+			if got != want {
+				t.Errorf("computeRestockRecommendations() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestComputeRestockRecommendationsOrder(t *testing.T) {
+	var snapshots []db.FbaInventorySnapshot
+	snapshots = append(snapshots, inventoryHistory("IDLE", snapshotCounts{day: 0, fulfillable: 10}, snapshotCounts{day: 10, fulfillable: 10})...)
+	snapshots = append(snapshots, inventoryHistory("SLOW", snapshotCounts{day: 0, fulfillable: 100}, snapshotCounts{day: 10, fulfillable: 90})...)
+	snapshots = append(snapshots, inventoryHistory("FAST", snapshotCounts{day: 0, fulfillable: 100}, snapshotCounts{day: 10, fulfillable: 20})...)
+
+	recommendations := computeRestockRecommendations(snapshots, 30, 60)
+	var order []string
+	for _, r := range recommendations {
+		order = append(order, r.SKU)
+	}
+	if want := []string{"FAST", "SLOW", "IDLE"}; !slices.Equal(order, want) {
+		t.Errorf("recommendations are ordered %v, want %v by days of cover", order, want)
+	}
+}
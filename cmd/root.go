@@ -16,6 +16,10 @@ import (
 var cfg = &config.Config
 var cfgFile string
 
+// exitCode is set by commands that report their result through the exit status, the process exits with it after the
+// command returns, so resources opened for the command are released first.
+var exitCode int
+
 var rootCmd = &cobra.Command{
 	Use:   "halycon",
 	Short: "utility tools for amazon seller API",
@@ -31,6 +35,7 @@ func Execute() {
 	if err != nil {
 		os.Exit(1)
 	}
+	os.Exit(exitCode)
 }
 
 var (
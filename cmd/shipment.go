@@ -1,29 +1,32 @@
 package cmd
 
 import (
+	"cmp"
 	"encoding/csv"
-	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/caner-cetin/halycon/internal"
 	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/config"
+	"github.com/fatih/color"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
 type createShipmentPlanConfig struct {
-	Input string
+	Input          string
+	ShipFrom       string
+	SkipValidation bool
 }
 
 var (
 	createShipmentPlanCmd = &cobra.Command{
 		Use: "create",
-		Run: WrapCommandWithResources(createShipmentPlan, ResourceConfig{Resources: []ResourceType{ResourceAmazon}, Services: []ServiceType{ServiceFBAInbound}}),
+		Run: WrapCommandWithResources(createShipmentPlan, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound, ServiceFBAInboundV0, ServiceCatalog, ServiceListings, ServiceFBAEligibility}}),
 	}
 	createShipmentPlanCfg = createShipmentPlanConfig{}
 	shipmentCmd           = &cobra.Command{
@@ -40,77 +43,155 @@ var (
 	operationCmd = &cobra.Command{
 		Use: "operation",
 	}
-	operationId string
+	operationId        string
+	operationStatusCfg operationStatusConfig
 )
 
+type operationStatusConfig struct {
+	Wait bool
+	operationWaitConfig
+}
+
 func getShipmentCmd() *cobra.Command {
-	createShipmentPlanCmd.PersistentFlags().StringVarP(&createShipmentPlanCfg.Input, "input", "i", "", "comma delimited input consisting ASIN, SKU, product name, quantity in order (output of asin to sku command)")
+	createShipmentPlanCmd.PersistentFlags().StringVar(&createShipmentPlanCfg.ShipFrom, "ship-from", "", "name or company name of the ship from address in config, rows with a ship from column override it (default: the default ship from address)")
+	createShipmentPlanCmd.PersistentFlags().BoolVar(&createShipmentPlanCfg.SkipValidation, "skip-validation", false, "create plans without running shipment validate first")
+	createShipmentPlanCmd.PersistentFlags().StringVarP(&createShipmentPlanCfg.Input, "input", "i", "", "csv with a header row and a quantity column, identified by any of UPC, ASIN, SKU or FNSKU columns (output of asin to sku command works as is)")
 
 	operationStatusCmd.PersistentFlags().StringVarP(&operationId, "id", "i", "", "operation id")
+	operationStatusCmd.PersistentFlags().BoolVarP(&operationStatusCfg.Wait, "wait", "w", false, "poll until the operation succeeds or fails, exits with 0 on success, 1 on failure, 2 on timeout and 3 on errors")
+	operationStatusCmd.PersistentFlags().DurationVar(&operationStatusCfg.Timeout, "timeout", defaultOperationWait.Timeout, "maximum time to wait with --wait")
+	operationStatusCmd.PersistentFlags().DurationVar(&operationStatusCfg.Interval, "interval", defaultOperationWait.Interval, "initial polling interval with --wait, increases by 1.5x after every poll")
+	operationStatusCmd.PersistentFlags().DurationVar(&operationStatusCfg.MaxInterval, "max-interval", defaultOperationWait.MaxInterval, "maximum polling interval with --wait")
 	operationCmd.AddCommand(operationStatusCmd)
 	shipmentCmd.AddCommand(operationCmd)
 
-	shipmentCmd.AddCommand()
 	shipmentCmd.AddCommand(createShipmentPlanCmd)
+	shipmentCmd.AddCommand(getValidateShipmentCmd())
+	shipmentCmd.AddCommand(getShipmentWorkflowCmd())
+	shipmentCmd.AddCommand(getShipmentPlacementCmd())
+	shipmentCmd.AddCommand(getShipmentTransportCmd())
+	shipmentCmd.AddCommand(getShipmentRequirementsCmd())
+	shipmentCmd.AddCommand(getShipmentListCmd())
+	shipmentCmd.AddCommand(getShipmentShowCmd())
+	shipmentCmd.AddCommand(getShipmentPackSuggestCmd())
+	shipmentCmd.AddCommand(getShipmentLabelsCmd())
+	shipmentCmd.AddCommand(getCancelShipmentPlanCmd())
+	shipmentCmd.AddCommand(getRenameShipmentPlanCmd())
+	shipmentCmd.AddCommand(getUpdateShipmentQuantityCmd())
+	shipmentCmd.AddCommand(getUpdateShipmentSourceAddressCmd())
+	shipmentCmd.AddCommand(getReconcileShipmentCmd())
 	return shipmentCmd
 }
 func createShipmentPlan(cmd *cobra.Command, args []string) {
 	app := GetApp(cmd)
 
-	var params fba_inbound.CreateInboundPlanRequest
-	params.DestinationMarketplaces = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
-	params.SourceAddress = fba_inbound.AddressInput{
-		AddressLine1: cfg.Amazon.FBA.DefaultShipFrom.AddressLine1,
-		City:         cfg.Amazon.FBA.DefaultShipFrom.City,
-		Name:         cfg.Amazon.FBA.DefaultShipFrom.Name,
-		PhoneNumber:  cfg.Amazon.FBA.DefaultShipFrom.PhoneNumber,
-		PostalCode:   cfg.Amazon.FBA.DefaultShipFrom.PostalCode,
-		CountryCode:  cfg.Amazon.FBA.DefaultShipFrom.CountryCode,
-	}
-	if cfg.Amazon.FBA.DefaultShipFrom.AddressLine2 != "" {
-		params.SourceAddress.AddressLine2 = &cfg.Amazon.FBA.DefaultShipFrom.AddressLine2
-	}
-	if cfg.Amazon.FBA.DefaultShipFrom.CompanyName != "" {
-		params.SourceAddress.CompanyName = &cfg.Amazon.FBA.DefaultShipFrom.CompanyName
+	shipFrom := cfg.Amazon.FBA.DefaultShipFrom
+	if createShipmentPlanCfg.ShipFrom != "" {
+		var err error
+		if shipFrom, err = findShipFrom(createShipmentPlanCfg.ShipFrom); err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
 	}
-	if cfg.Amazon.FBA.DefaultShipFrom.StateOrProvince != "" {
-		params.SourceAddress.StateOrProvinceCode = &cfg.Amazon.FBA.DefaultShipFrom.StateOrProvince
+	groups, err := readShipmentInput(app, createShipmentPlanCfg.Input, shipFrom)
+	if err != nil {
+		log.Error().Err(err).Str("file", createShipmentPlanCfg.Input).Msg("failed to read shipment input")
+		return
 	}
-	if cfg.Amazon.FBA.DefaultShipFrom.Email != "" {
-		params.SourceAddress.Email = &cfg.Amazon.FBA.DefaultShipFrom.Email
+	if !createShipmentPlanCfg.SkipValidation {
+		problems, err := validateShipmentGroups(app, groups)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to validate shipment")
+			return
+		}
+		if errorCount := displayValidationProblems(problems); errorCount > 0 {
+			log.Error().Int("errors", errorCount).Msg("fix the problems above or pass --skip-validation to create the plan anyway")
+			return
+		}
 	}
 
-	input, err := internal.OpenFile(createShipmentPlanCfg.Input)
-	if err != nil {
-		log.Error().Err(err).Send()
+	if len(groups) == 1 {
+		created, err := createInboundPlan(app, groups[0])
+		if err != nil {
+			log.Error().Err(err).Str("inbound_plan_id", created.InboundPlanId).Msg("failed to create inbound plan")
+			return
+		}
+		inboundPlanId := created.InboundPlanId
+		shouldContinue, err := internal.PromptFor("Continue with packing, placement and transportation? [y/N]")
+		if err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
+		if strings.TrimSpace(strings.ToLower(shouldContinue)) != "y" {
+			fmt.Printf("Resume with: halycon shipment workflow %s\n", inboundPlanId)
+			return
+		}
+		runInboundWorkflow(app, inboundPlanId, 0)
 		return
 	}
-	defer input.Close()
-	reader := csv.NewReader(input)
-	products, err := reader.ReadAll()
-	if err != nil {
-		log.Error().
-			Err(err).
-			Str("file", createShipmentPlanCfg.Input).
-			Msg("error reading csv")
-		return
+
+	// plans are created one after another, a failed plan does not stop the rest
+	log.Info().Int("plans", len(groups)).Msg("input has more than one ship from address or marketplace, creating a plan for each")
+	results := make([]error, len(groups))
+	plans := make([]createdInboundPlan, len(groups))
+	for i, group := range groups {
+		plans[i], results[i] = createInboundPlan(app, group)
+		if results[i] != nil {
+			log.Error().Err(results[i]).Str("ship_from", shipFromLabel(group.ShipFrom)).Strs("marketplaces", group.MarketplaceIDs).Msg("failed to create inbound plan")
+		}
+	}
+	fmt.Printf("%-30s %-16s %6s %8s %-40s %-38s %s\n", "Ship From", "Marketplace", "SKUs", "Units", "Inbound Plan ID", "Operation ID", "Status")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------------------------------------------------------------"))
+	for i, group := range groups {
+		units := 0
+		for _, row := range group.Rows {
+			units += row.Quantity
+		}
+		status := color.GreenString("created")
+		if results[i] != nil {
+			status = color.RedString("%s", truncateString(results[i].Error(), 60))
+		}
+		fmt.Printf("%-30s %-16s %6d %8d %-40s %-38s %s\n",
+			truncateString(shipFromLabel(group.ShipFrom), 30),
+			truncateString(strings.Join(group.MarketplaceIDs, ","), 16),
+			len(group.Rows), units, valueOrDash(plans[i].InboundPlanId), valueOrDash(plans[i].OperationId), status)
 	}
+	fmt.Println("Continue every created plan with: halycon shipment workflow <inbound plan id>")
+}
+
+type createdInboundPlan struct {
+	InboundPlanId string
+	OperationId   string
+}
+
+// createInboundPlan creates an inbound plan for the rows of a group and starts tracking it once its creation operation succeeds.
+// The plan is returned with the error if it was created but its operation failed.
+func createInboundPlan(app AppCtx, group shipmentInputGroup) (createdInboundPlan, error) {
+	var created createdInboundPlan
+	var params fba_inbound.CreateInboundPlanRequest
+	params.DestinationMarketplaces = group.MarketplaceIDs
+	params.SourceAddress = sourceAddressInput(group.ShipFrom)
 
 	// todo: config key
 	defaultPrepOwner := fba_inbound.NONE
 	defaultLabelOwner := fba_inbound.LabelOwnerNONE
 
-	prepRequirements := loadPrepRequirements()
-
-	items := make([]fba_inbound.ItemInput, 0, len(products)-1)
-	for _, product := range products[1:] {
-		sku := product[1]
-		quantity, err := strconv.Atoi(product[3])
-		if err != nil {
-			log.Error().Err(err).Str("quantity", product[3]).Msg("error while converting quantity to integer")
-			return
-		}
+	marketplaceId := group.marketplaceId()
+	prepRequirements, err := loadPrepRequirements(app, marketplaceId)
+	if err != nil {
+		return created, err
+	}
+	mskus := make([]string, 0, len(group.Rows))
+	for _, product := range group.Rows {
+		mskus = append(mskus, product.SKU)
+	}
+	if err := prefillPrepRequirements(app, marketplaceId, mskus, prepRequirements); err != nil {
+		log.Warn().Err(err).Msg("could not prefill item requirements from prep details, continuing with stored requirements")
+	}
 
+	items := make([]fba_inbound.ItemInput, 0, len(group.Rows))
+	for _, product := range group.Rows {
+		sku := product.SKU
 		prepOwner := defaultPrepOwner
 		labelOwner := defaultLabelOwner
 
@@ -125,27 +206,20 @@ func createShipmentPlan(cmd *cobra.Command, args []string) {
 
 		items = append(items, fba_inbound.ItemInput{
 			Msku:       sku,
-			Quantity:   quantity,
+			Quantity:   product.Quantity,
 			PrepOwner:  prepOwner,
 			LabelOwner: fba_inbound.LabelOwner(labelOwner),
 		})
 	}
 	params.Items = items
 
-	status, err := app.Amazon.Client.CreateFBAInboundPlan(cmd.Context(), params)
+	status, err := app.Amazon.Client.CreateFBAInboundPlan(app.Ctx, params)
 	if err != nil {
 		if prepErrors := extractPrepOwnerErrors(err); len(prepErrors) > 0 {
 			log.Info().Msg("Found SKUs requiring prep, updating and retrying...")
 
 			for sku := range prepErrors {
-				requirements := ItemRequirements{
-					PrepOwner:  fba_inbound.NONE,
-					LabelOwner: fba_inbound.LabelOwnerNONE,
-				}
-
-				if existing, exists := prepRequirements[sku]; exists {
-					requirements = existing
-				}
+				requirements := prepRequirements.get(sku)
 
 				if strings.Contains(err.Error(), sku+" requires prepOwner") {
 					requirements.PrepOwner = fba_inbound.SELLER
@@ -153,11 +227,14 @@ func createShipmentPlan(cmd *cobra.Command, args []string) {
 				if strings.Contains(err.Error(), sku+" requires labelOwner") {
 					requirements.LabelOwner = fba_inbound.LabelOwnerSELLER
 				}
+				requirements.Source = itemRequirementSourceError
 
 				prepRequirements[sku] = requirements
+				if err := savePrepRequirement(app.Ctx, app.Query, marketplaceId, sku, requirements); err != nil {
+					log.Warn().Err(err).Str("msku", sku).Msg("could not store item requirements")
+				}
 			}
 
-			savePrepRequirements(prepRequirements)
 			for i, item := range items {
 				if _, exists := prepErrors[item.Msku]; exists {
 					if strings.Contains(err.Error(), item.Msku+" requires prepOwner") {
@@ -170,47 +247,64 @@ func createShipmentPlan(cmd *cobra.Command, args []string) {
 				}
 			}
 
-			status, err = app.Amazon.Client.CreateFBAInboundPlan(cmd.Context(), params)
+			status, err = app.Amazon.Client.CreateFBAInboundPlan(app.Ctx, params)
 			if err != nil {
-				log.Error().Err(err).Msg("error occurred while creating inbound shipment plan after prep update")
-				return
+				return created, fmt.Errorf("error occurred while creating inbound shipment plan after prep update: %w", err)
 			}
 		} else {
-			log.Error().Err(err).Msg("error occurred while creating inbound shipment plan")
-			return
+			return created, fmt.Errorf("error occurred while creating inbound shipment plan: %w", err)
 		}
 	}
 	result := status.JSON202
+	created = createdInboundPlan{InboundPlanId: result.InboundPlanId, OperationId: result.OperationId}
 	log.Info().Str("inbound_plan_id", result.InboundPlanId).Str("operation_id", result.OperationId).Msg("success!")
-	shouldOpenPlan, err := internal.PromptFor("Open plan with default browser? [y/N]")
+	operation, err := waitForOperation(app, result.OperationId)
 	if err != nil {
-		log.Error().Err(err).Send()
-		return
+		return created, fmt.Errorf("inbound plan creation failed: %w", err)
 	}
-	if strings.TrimSpace(strings.ToLower(strings.ToLower(shouldOpenPlan))) == "y" {
-		// todo: change .com, I dont know how other marketplaces works.
-		url := fmt.Sprintf("https://sellercentral.amazon.com/fba/sendtoamazon/confirm_content_step?wf=%s", result.InboundPlanId)
-		err = internal.OpenURL(url)
-		if err != nil {
-			log.Error().Str("url", url).Err(err).Send()
-			return
-		}
+	if err := trackInboundPlan(app, result.InboundPlanId, params.Name, marketplaceId, params.SourceAddress); err != nil {
+		return created, fmt.Errorf("failed to track inbound plan: %w", err)
+	}
+	if err := recordInboundPlanItems(app, result.InboundPlanId, params.Items); err != nil {
+		log.Warn().Err(err).Str("inbound_plan_id", result.InboundPlanId).Msg("failed to record inbound plan items")
 	}
+	if err := recordInboundPlanOperation(app, result.InboundPlanId, operation); err != nil {
+		log.Warn().Err(err).Str("inbound_plan_id", result.InboundPlanId).Msg("failed to record inbound plan operation")
+	}
+	return created, nil
 }
 
-// ItemRequirements defines the ownership requirements for item preparation and labeling.
-// It specifies which entities are responsible for preparing and labeling items in a shipment.
-type ItemRequirements struct {
-	// PrepOwner indicates the entity responsible for preparing the item for shipment.
-	PrepOwner fba_inbound.PrepOwner `json:"prep_owner"`
+// ShipmentInputRow is a single row of the `shipment create` input csv.
+type ShipmentInputRow struct {
+	ASIN     string
+	SKU      string
+	Title    string
+	Quantity int
 
-	// LabelOwner indicates the entity responsible for labeling the item for shipment.
-	LabelOwner fba_inbound.LabelOwner `json:"label_owner"`
+	// lines are the input rows merged into this row
+	lines []int
 }
 
-// PrepRequirements is a mapping from preparation ID to the items required for that preparation.
-// It defines the requirements needed for different preparation processes in a shipment.
-type PrepRequirements map[string]ItemRequirements
+// writeShipmentInputCSV writes rows in the format accepted by `shipment create --input`.
+func writeShipmentInputCSV(path string, rows []ShipmentInputRow) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"ASIN", "SKU", "Product Name", "Quantity"}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, row := range rows {
+		if err := writer.Write([]string{row.ASIN, row.SKU, row.Title, strconv.Itoa(row.Quantity)}); err != nil {
+			return fmt.Errorf("failed to write row for %s: %w", row.SKU, err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
 
 func extractPrepOwnerErrors(err error) map[string]string {
 	prepErrors := make(map[string]string)
@@ -239,66 +333,67 @@ func extractPrepOwnerErrors(err error) map[string]string {
 	return prepErrors
 }
 
-func loadPrepRequirements() PrepRequirements {
-	prepRequirements := make(PrepRequirements)
-	cacheFile := filepath.Join(os.TempDir(), "halycon_item_requirements.json")
-	if _, err := os.Stat(cacheFile); os.IsNotExist(err) {
-		return prepRequirements
-	}
-
-	data, err := os.ReadFile(cacheFile)
-	if err != nil {
-		log.Warn().Err(err).Msg("could not read item requirements cache")
-		return prepRequirements
+func getOperationStatusCmd(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	if operationStatusCfg.Wait {
+		if err := operationStatusCfg.validate(); err != nil {
+			log.Error().Err(err).Msg("invalid wait configuration")
+			exitCode = operationExitError
+			return
+		}
+		status, err := waitForOperationWith(app, operationId, operationStatusCfg.operationWaitConfig)
+		if err != nil {
+			log.Error().Err(err).Str("operation_id", operationId).Send()
+		} else {
+			color.Green("operation %s %s succeeded", status.Operation, status.OperationId)
+		}
+		exitCode = operationExitCode(err)
+		return
 	}
-	err = json.Unmarshal(data, &prepRequirements)
+	status, err := app.Amazon.Client.GetInboundOperationStatus(cmd.Context(), operationId)
 	if err != nil {
-		log.Warn().Err(err).Msg("could not parse item requirements cache")
-		return prepRequirements
+		log.Error().Err(err).Send()
+		return
 	}
-
-	return prepRequirements
+	logOperationStatus(status.JSON200)
 }
 
-func savePrepRequirements(prepRequirements PrepRequirements) {
-	cacheFile := filepath.Join(os.TempDir(), "halycon_item_requirements.json")
-	data, err := json.MarshalIndent(prepRequirements, "", "  ")
-	if err != nil {
-		log.Warn().Err(err).Msg("could not marshal item requirements")
-		return
+// sourceAddressInput converts a ship from address in config to the source address of inbound plans and shipments.
+func sourceAddressInput(shipFrom config.ShipFromConfig) fba_inbound.AddressInput {
+	address := fba_inbound.AddressInput{
+		AddressLine1: shipFrom.AddressLine1,
+		City:         shipFrom.City,
+		Name:         shipFrom.Name,
+		PhoneNumber:  shipFrom.PhoneNumber,
+		PostalCode:   shipFrom.PostalCode,
+		CountryCode:  shipFrom.CountryCode,
 	}
-	err = os.WriteFile(cacheFile, data, 0644)
-	if err != nil {
-		log.Warn().Err(err).Msg("could not write item requirements cache")
+	if shipFrom.AddressLine2 != "" {
+		address.AddressLine2 = &shipFrom.AddressLine2
 	}
+	if shipFrom.CompanyName != "" {
+		address.CompanyName = &shipFrom.CompanyName
+	}
+	if shipFrom.StateOrProvince != "" {
+		address.StateOrProvinceCode = &shipFrom.StateOrProvince
+	}
+	if shipFrom.Email != "" {
+		address.Email = &shipFrom.Email
+	}
+	return address
 }
 
-func getOperationStatusCmd(cmd *cobra.Command, args []string) {
-	ctx := cmd.Context()
-	app := ctx.Value(internal.APP_CONTEXT).(AppCtx)
-	status, err := app.Amazon.Client.GetInboundOperationStatus(cmd.Context(), operationId)
-	if err != nil {
-		log.Error().Err(err).Send()
-		return
-	}
-	response := status.JSON200
-	logger := log.With().
-		Str("id", response.OperationId).
-		Str("status", string(response.OperationStatus)).
-		Logger()
-	if response.OperationStatus == fba_inbound.FAILED {
-		logger.Warn().Send()
-	} else {
-		logger.Info().Send()
-	}
-	for i, problem := range response.OperationProblems {
-		ev := log.Warn().
-			Str("code", problem.Code).
-			Str("message", problem.Message).
-			Str("severity", problem.Severity)
-		if problem.Details != nil {
-			ev.Str("details", *problem.Details)
+// shipFromLabel returns a short description of a ship from address for display.
+func shipFromLabel(shipFrom config.ShipFromConfig) string {
+	return cmp.Or(shipFrom.CompanyName, shipFrom.Name, shipFrom.AddressLine1)
+}
+
+// findShipFrom returns the ship from address in config with given name or company name, case insensitive.
+func findShipFrom(name string) (config.ShipFromConfig, error) {
+	for _, address := range cfg.Amazon.FBA.ShipFrom {
+		if strings.EqualFold(address.Name, name) || strings.EqualFold(address.CompanyName, name) {
+			return address, nil
 		}
-		ev.Msgf("problem %d", i+1)
 	}
+	return config.ShipFromConfig{}, fmt.Errorf("no ship from address named %s in config", name)
 }
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/config"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type cancelShipmentPlanConfig struct {
+	Yes bool
+}
+
+type updateShipmentQuantityConfig struct {
+	InboundPlanId string
+	PackingInfo   string
+}
+
+type updateShipmentSourceAddressConfig struct {
+	InboundPlanId string
+	ShipFrom      string
+}
+
+var (
+	cancelShipmentPlanCmd = &cobra.Command{
+		Use:   "cancel [inbound plan id]",
+		Short: "cancels an inbound plan and all of its shipments",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(cancelShipmentPlan, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+	cancelShipmentPlanCfg cancelShipmentPlanConfig
+
+	renameShipmentPlanCmd = &cobra.Command{
+		Use:   "rename [inbound plan id] [name]",
+		Short: "renames an inbound plan",
+		Args:  cobra.ExactArgs(2),
+		Run:   WrapCommandWithResources(renameShipmentPlan, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+
+	updateShipmentQuantityCmd = &cobra.Command{
+		Use:   "update-quantity [shipment id or confirmation id] [MSKU=QUANTITY...]",
+		Short: "updates item quantities of a confirmed shipment through content update previews",
+		Args:  cobra.MinimumNArgs(2),
+		Run:   WrapCommandWithResources(updateShipmentQuantity, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+	updateShipmentQuantityCfg updateShipmentQuantityConfig
+
+	updateShipmentSourceAddressCmd = &cobra.Command{
+		Use:   "update-source-address [shipment id or confirmation id]",
+		Short: "changes the address a shipment is picked up from to one of the ship from addresses in config",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(updateShipmentSourceAddress, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+	updateShipmentSourceAddressCfg updateShipmentSourceAddressConfig
+)
+
+func getCancelShipmentPlanCmd() *cobra.Command {
+	cancelShipmentPlanCmd.PersistentFlags().BoolVarP(&cancelShipmentPlanCfg.Yes, "yes", "y", false, "cancel without asking for confirmation")
+	return cancelShipmentPlanCmd
+}
+
+func getRenameShipmentPlanCmd() *cobra.Command {
+	return renameShipmentPlanCmd
+}
+
+func getUpdateShipmentQuantityCmd() *cobra.Command {
+	flags := updateShipmentQuantityCmd.PersistentFlags()
+	flags.StringVar(&updateShipmentQuantityCfg.InboundPlanId, "plan", "", "inbound plan of the shipment, required if the shipment is not tracked locally")
+	flags.StringVar(&updateShipmentQuantityCfg.PackingInfo, "packing-info", "", "new box contents as csv or yaml, same format as shipment workflow, the current boxes are kept if not provided")
+	return updateShipmentQuantityCmd
+}
+
+func getUpdateShipmentSourceAddressCmd() *cobra.Command {
+	flags := updateShipmentSourceAddressCmd.PersistentFlags()
+	flags.StringVar(&updateShipmentSourceAddressCfg.InboundPlanId, "plan", "", "inbound plan of the shipment, required if the shipment is not tracked locally")
+	flags.StringVar(&updateShipmentSourceAddressCfg.ShipFrom, "ship-from", "", "name or company name of the ship from address in config, prompted if not provided")
+	return updateShipmentSourceAddressCmd
+}
+
+func cancelShipmentPlan(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	inboundPlanId := args[0]
+	if !cancelShipmentPlanCfg.Yes {
+		var proceed bool
+		if err := huh.NewConfirm().
+			Title(fmt.Sprintf("Cancel inbound plan %s?", inboundPlanId)).
+			Description("All shipments of the plan are cancelled. Plans with confirmed transportation may incur fees.").
+			Value(&proceed).
+			Run(); err != nil || !proceed {
+			return
+		}
+	}
+	if _, err := getOrTrackInboundPlan(app, inboundPlanId); err != nil {
+		log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to get inbound plan")
+		return
+	}
+	resp, err := app.Amazon.Client.CancelInboundPlan(app.Ctx, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to cancel inbound plan")
+		return
+	}
+	if _, err := waitForPlanOperation(app, inboundPlanId, resp.JSON200.OperationId); err != nil {
+		log.Error().Err(err).Str("operation_id", resp.JSON200.OperationId).Msg("operation did not succeed")
+		return
+	}
+	syncEditedInboundPlan(app, inboundPlanId)
+	color.Green("inbound plan %s is cancelled", inboundPlanId)
+}
+
+func renameShipmentPlan(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	inboundPlanId, name := args[0], strings.TrimSpace(args[1])
+	if name == "" {
+		log.Error().Msg("name cannot be empty")
+		return
+	}
+	if _, err := app.Amazon.Client.UpdateInboundPlanName(app.Ctx, inboundPlanId, fba_inbound.UpdateInboundPlanNameRequest{Name: name}); err != nil {
+		log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to rename inbound plan")
+		return
+	}
+	// renaming is applied immediately, there is no operation to wait for
+	syncEditedInboundPlan(app, inboundPlanId)
+	color.Green("inbound plan %s is renamed to %s", inboundPlanId, name)
+}
+
+func updateShipmentQuantity(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	quantities, err := parseQuantityUpdates(args[1:])
+	if err != nil {
+		log.Error().Err(err).Msg("invalid quantities")
+		return
+	}
+	shipment, err := resolveInboundShipment(app, args[0], updateShipmentQuantityCfg.InboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("shipment_id", args[0]).Msg("failed to get shipment")
+		return
+	}
+	inboundPlanId, shipmentId := shipment.InboundPlanID, shipment.ShipmentID
+
+	current, err := listShipmentItems(app, inboundPlanId, shipmentId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list shipment items")
+		return
+	}
+	var items []fba_inbound.ItemInput
+	fmt.Printf("%-40s %10s %10s\n", "MSKU", "Current", "New")
+	fmt.Println(color.HiBlackString("%s", "--------------------------------------------------------------"))
+	for _, item := range current {
+		input := itemInputFromItem(item)
+		if quantity, ok := quantities[item.Msku]; ok {
+			input.Quantity = quantity
+			delete(quantities, item.Msku)
+		}
+		fmt.Printf("%-40s %10d %10d\n", item.Msku, item.Quantity, input.Quantity)
+		if input.Quantity > 0 {
+			items = append(items, input)
+		}
+	}
+	if len(quantities) > 0 {
+		log.Error().Strs("mskus", slices.Sorted(maps.Keys(quantities))).Msg("skus are not in the shipment, only quantities of existing items can be updated")
+		return
+	}
+	if len(items) == 0 {
+		log.Error().Msg("shipment would have no items left, cancel the plan instead")
+		return
+	}
+
+	boxes, err := shipmentContentBoxes(app, inboundPlanId, shipmentId, items)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid box contents")
+		return
+	}
+	generated, err := app.Amazon.Client.GenerateShipmentContentUpdatePreviews(app.Ctx, inboundPlanId, shipmentId, fba_inbound.GenerateShipmentContentUpdatePreviewsRequest{Boxes: boxes, Items: items})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate content update previews")
+		return
+	}
+	if _, err := waitForPlanOperation(app, inboundPlanId, generated.JSON202.OperationId); err != nil {
+		log.Error().Err(err).Str("operation_id", generated.JSON202.OperationId).Msg("operation did not succeed")
+		return
+	}
+	previews, err := app.Amazon.Client.ListShipmentContentUpdatePreviews(app.Ctx, inboundPlanId, shipmentId, &fba_inbound.ListShipmentContentUpdatePreviewsParams{PageSize: internal.Ptr(inboundPageSize)})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list content update previews")
+		return
+	}
+	var options []huh.Option[string]
+	for _, preview := range previews.JSON200.ContentUpdatePreviews {
+		if preview.Expiration.Before(time.Now()) {
+			continue
+		}
+		label := fmt.Sprintf("%s, expires %s", formatTransportationOption(preview.TransportationOption), preview.Expiration.Local().Format(time.DateTime))
+		options = append(options, huh.NewOption(label, preview.ContentUpdatePreviewId))
+	}
+	previewId, err := selectInboundOption("Select content update", options)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to select content update preview")
+		return
+	}
+	confirmed, err := app.Amazon.Client.ConfirmShipmentContentUpdatePreview(app.Ctx, inboundPlanId, shipmentId, previewId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to confirm content update preview")
+		return
+	}
+	if _, err := waitForPlanOperation(app, inboundPlanId, confirmed.JSON202.OperationId); err != nil {
+		log.Error().Err(err).Str("operation_id", confirmed.JSON202.OperationId).Msg("operation did not succeed")
+		return
+	}
+	syncEditedInboundPlan(app, inboundPlanId)
+	color.Green("quantities of shipment %s are updated", shipmentId)
+}
+
+func updateShipmentSourceAddress(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	shipFrom, err := selectShipFrom(updateShipmentSourceAddressCfg.ShipFrom)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to select ship from address")
+		return
+	}
+	shipment, err := resolveInboundShipmentID(app, args[0], updateShipmentSourceAddressCfg.InboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("shipment_id", args[0]).Msg("failed to get shipment")
+		return
+	}
+	resp, err := app.Amazon.Client.UpdateShipmentSourceAddress(app.Ctx, shipment.InboundPlanID, shipment.ShipmentID, fba_inbound.UpdateShipmentSourceAddressRequest{Address: sourceAddressInput(shipFrom)})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to update source address")
+		return
+	}
+	if _, err := waitForPlanOperation(app, shipment.InboundPlanID, resp.JSON202.OperationId); err != nil {
+		log.Error().Err(err).Str("operation_id", resp.JSON202.OperationId).Msg("operation did not succeed")
+		return
+	}
+	syncEditedInboundPlan(app, shipment.InboundPlanID)
+	color.Green("shipment %s is picked up from %s", shipment.ShipmentID, strings.Join([]string{shipFrom.AddressLine1, shipFrom.City, shipFrom.CountryCode}, ", "))
+}
+
+// resolveInboundShipmentID is resolveInboundShipment for operations that do not need the shipment to be confirmed.
+func resolveInboundShipmentID(app AppCtx, id string, inboundPlanId string) (db.InboundPlanShipment, error) {
+	shipment, err := app.Query.FindInboundPlanShipment(app.Ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		if inboundPlanId == "" {
+			return shipment, errors.New("shipment is not tracked locally, pass its inbound plan with --plan")
+		}
+		return db.InboundPlanShipment{InboundPlanID: inboundPlanId, ShipmentID: id}, nil
+	}
+	return shipment, err
+}
+
+// syncEditedInboundPlan refreshes the local tracking of a plan after it is edited. The edit is already applied, so failures are only logged.
+func syncEditedInboundPlan(app AppCtx, inboundPlanId string) {
+	if err := syncInboundPlan(app, inboundPlanId); err != nil {
+		log.Warn().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to sync inbound plan, run shipment show to retry")
+	}
+}
+
+// parseQuantityUpdates parses MSKU=QUANTITY arguments, a quantity of 0 removes the item.
+func parseQuantityUpdates(args []string) (map[string]int, error) {
+	quantities := make(map[string]int, len(args))
+	var errs []error
+	for _, arg := range args {
+		msku, raw, ok := strings.Cut(arg, "=")
+		quantity, err := strconv.Atoi(raw)
+		if !ok || msku == "" || err != nil || quantity < 0 {
+			errs = append(errs, fmt.Errorf("invalid quantity update %s, must be MSKU=QUANTITY", arg))
+			continue
+		}
+		quantities[msku] = quantity
+	}
+	return quantities, errors.Join(errs...)
+}
+
+// shipmentContentBoxes returns the boxes of a content update. Boxes are read from --packing-info and validated against the updated items,
+// otherwise the current boxes of the shipment are kept, which is only possible if their contents are not listed per item.
+func shipmentContentBoxes(app AppCtx, inboundPlanId string, shipmentId string, items []fba_inbound.ItemInput) ([]fba_inbound.BoxUpdateInput, error) {
+	var boxes []fba_inbound.BoxUpdateInput
+	if updateShipmentQuantityCfg.PackingInfo != "" {
+		// the updated shipment is validated as a single packing group
+		groups := packingGroups{IDs: []string{shipmentId}, Items: map[string][]fba_inbound.Item{}}
+		for _, item := range items {
+			groups.Items[shipmentId] = append(groups.Items[shipmentId], fba_inbound.Item{Msku: item.Msku, Quantity: item.Quantity, LabelOwner: string(item.LabelOwner)})
+		}
+		request, err := readPackingInformation(updateShipmentQuantityCfg.PackingInfo, groups)
+		if err != nil {
+			return nil, err
+		}
+		if err := validatePackingInformation(request, groups); err != nil {
+			return nil, err
+		}
+		for _, grouping := range request.PackageGroupings {
+			for _, box := range grouping.Boxes {
+				boxes = append(boxes, fba_inbound.BoxUpdateInput{
+					ContentInformationSource: box.ContentInformationSource,
+					Dimensions:               box.Dimensions,
+					Items:                    box.Items,
+					Quantity:                 box.Quantity,
+					Weight:                   box.Weight,
+				})
+			}
+		}
+		return boxes, nil
+	}
+
+	current, err := listShipmentBoxes(app, inboundPlanId, shipmentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shipment boxes: %w", err)
+	}
+	for _, box := range current {
+		if box.Items != nil && len(*box.Items) > 0 {
+			return nil, errors.New("boxes of the shipment list their items, provide the updated box contents with --packing-info")
+		}
+		if box.Dimensions == nil || box.Weight == nil || box.ContentInformationSource == nil {
+			return nil, fmt.Errorf("box %s has no dimensions, weight or content information, provide the box contents with --packing-info", box.PackageId)
+		}
+		boxes = append(boxes, fba_inbound.BoxUpdateInput{
+			ContentInformationSource: *box.ContentInformationSource,
+			Dimensions:               *box.Dimensions,
+			PackageId:                internal.Ptr(box.PackageId),
+			Quantity:                 1,
+			Weight:                   *box.Weight,
+		})
+	}
+	return boxes, nil
+}
+
+// selectShipFrom returns the ship from address with given name, or prompts for one of the addresses in config.
+func selectShipFrom(name string) (config.ShipFromConfig, error) {
+	if name != "" {
+		return findShipFrom(name)
+	}
+	addresses := cfg.Amazon.FBA.ShipFrom
+	if len(addresses) == 0 {
+		return config.ShipFromConfig{}, errors.New("no ship from addresses in config")
+	}
+	options := make([]huh.Option[string], 0, len(addresses))
+	for i, address := range addresses {
+		label := strings.Join(slices.DeleteFunc([]string{address.CompanyName, address.Name, address.AddressLine1, address.City, address.CountryCode}, func(s string) bool { return s == "" }), ", ")
+		options = append(options, huh.NewOption(label, strconv.Itoa(i)))
+	}
+	selected, err := selectInboundOption("Select ship from address", options)
+	if err != nil {
+		return config.ShipFromConfig{}, err
+	}
+	index, _ := strconv.Atoi(selected)
+	return addresses[index], nil
+}
@@ -0,0 +1,431 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/catalog"
+	"github.com/caner-cetin/halycon/internal/config"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+)
+
+// Columns of the `shipment create` input. Any of the identifiers is enough to resolve a row, SKUs are used as they are.
+const (
+	shipmentInputUPC      = "upc"
+	shipmentInputASIN     = "asin"
+	shipmentInputSKU      = "sku"
+	shipmentInputFNSKU    = "fnsku"
+	shipmentInputTitle    = "title"
+	shipmentInputQuantity = "quantity"
+	// optional columns splitting the input into one plan per ship from address and destination marketplace
+	shipmentInputShipFrom    = "ship from"
+	shipmentInputMarketplace = "marketplace"
+)
+
+// shipmentInputHeaders maps headers, lowercased and stripped of everything except letters and digits, to input columns.
+var shipmentInputHeaders = map[string]string{
+	"upc":         shipmentInputUPC,
+	"barcode":     shipmentInputUPC,
+	"asin":        shipmentInputASIN,
+	"sku":         shipmentInputSKU,
+	"msku":        shipmentInputSKU,
+	"sellersku":   shipmentInputSKU,
+	"fnsku":       shipmentInputFNSKU,
+	"title":       shipmentInputTitle,
+	"productname": shipmentInputTitle,
+	"quantity":    shipmentInputQuantity,
+	"qty":         shipmentInputQuantity,
+
+	"shipfrom":               shipmentInputShipFrom,
+	"sourceaddress":          shipmentInputShipFrom,
+	"marketplace":            shipmentInputMarketplace,
+	"marketplaceid":          shipmentInputMarketplace,
+	"destinationmarketplace": shipmentInputMarketplace,
+}
+
+// shipmentInputLine is a row of the `shipment create` input before its SKU is resolved.
+type shipmentInputLine struct {
+	Line     int
+	UPC      string
+	ASIN     string
+	SKU      string
+	FNSKU    string
+	Title    string
+	Quantity int
+	// ShipFrom is the name or company name of a ship from address in config, the address given with --ship-from if empty.
+	ShipFrom    string
+	Marketplace string
+}
+
+// identifier returns the most specific identifier of the row for display.
+func (l shipmentInputLine) identifier() string {
+	for _, id := range []string{l.SKU, l.FNSKU, l.ASIN, l.UPC} {
+		if id != "" {
+			return id
+		}
+	}
+	return "-"
+}
+
+type shipmentInputProblem struct {
+	Line       int
+	Identifier string
+	Problem    string
+}
+
+// readShipmentInputLines reads a csv with a header row naming its columns, in any order and letter case.
+// Unknown columns are ignored, rows with an invalid quantity or without any identifier are returned as problems.
+func readShipmentInputLines(r io.Reader) ([]shipmentInputLine, []shipmentInputProblem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("input is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		key := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, header)
+		if column, ok := shipmentInputHeaders[key]; ok {
+			if _, exists := columns[column]; exists {
+				return nil, nil, fmt.Errorf("header has more than one %s column", column)
+			}
+			columns[column] = i
+		}
+	}
+	if _, ok := columns[shipmentInputQuantity]; !ok {
+		return nil, nil, errors.New("header has no quantity column, accepted headers are UPC, ASIN, SKU, FNSKU, Title and Quantity")
+	}
+	if !slices.ContainsFunc([]string{shipmentInputUPC, shipmentInputASIN, shipmentInputSKU, shipmentInputFNSKU}, func(column string) bool {
+		_, ok := columns[column]
+		return ok
+	}) {
+		return nil, nil, errors.New("header has no identifier column, one of UPC, ASIN, SKU or FNSKU is required")
+	}
+
+	var lines []shipmentInputLine
+	var problems []shipmentInputProblem
+	for i, record := range records[1:] {
+		field := func(column string) string {
+			if index, ok := columns[column]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+		if !slices.ContainsFunc(record, func(s string) bool { return strings.TrimSpace(s) != "" }) {
+			continue
+		}
+		line := shipmentInputLine{
+			Line:  i + 2,
+			UPC:   internal.RemoveAllNonDigit(field(shipmentInputUPC)),
+			ASIN:  strings.ToUpper(field(shipmentInputASIN)),
+			SKU:   field(shipmentInputSKU),
+			FNSKU: strings.ToUpper(field(shipmentInputFNSKU)),
+			Title: field(shipmentInputTitle),
+
+			ShipFrom:    field(shipmentInputShipFrom),
+			Marketplace: field(shipmentInputMarketplace),
+		}
+		quantity, err := strconv.Atoi(field(shipmentInputQuantity))
+		switch {
+		case line.identifier() == "-":
+			problems = append(problems, shipmentInputProblem{Line: line.Line, Identifier: "-", Problem: "row has no UPC, ASIN, SKU or FNSKU"})
+		case err != nil || quantity <= 0:
+			problems = append(problems, shipmentInputProblem{Line: line.Line, Identifier: line.identifier(), Problem: fmt.Sprintf("invalid quantity %q", field(shipmentInputQuantity))})
+		default:
+			line.Quantity = quantity
+			lines = append(lines, line)
+		}
+	}
+	return lines, problems, nil
+}
+
+// inventoryIdentifiers indexes SKUs of the local fba_inventory cache by their other identifiers.
+type inventoryIdentifiers struct {
+	products map[string]shipmentInputLine
+	byASIN   map[string][]string
+	byUPC    map[string][]string
+	byFNSKU  map[string][]string
+}
+
+func loadInventoryIdentifiers(app AppCtx) (inventoryIdentifiers, error) {
+	index := inventoryIdentifiers{
+		products: map[string]shipmentInputLine{},
+		byASIN:   map[string][]string{},
+		byUPC:    map[string][]string{},
+		byFNSKU:  map[string][]string{},
+	}
+	rows, err := app.Query.ListInventoryIdentifiers(app.Ctx)
+	if err != nil {
+		return index, fmt.Errorf("failed to list inventory identifiers: %w", err)
+	}
+	for _, row := range rows {
+		product := shipmentInputLine{
+			SKU:   row.Sku.String,
+			ASIN:  strings.ToUpper(row.Asin.String),
+			UPC:   row.Upc.String,
+			FNSKU: strings.ToUpper(row.Fnsku.String),
+			Title: row.Title.String,
+		}
+		index.products[product.SKU] = product
+		addInventoryIdentifier(index.byASIN, product.ASIN, product.SKU)
+		addInventoryIdentifier(index.byUPC, normalizeUPC(product.UPC), product.SKU)
+		addInventoryIdentifier(index.byFNSKU, product.FNSKU, product.SKU)
+	}
+	return index, nil
+}
+
+func addInventoryIdentifier(index map[string][]string, id string, sku string) {
+	if id != "" && !slices.Contains(index[id], sku) {
+		index[id] = append(index[id], sku)
+	}
+}
+
+// normalizeUPC strips leading zeros, so UPC-A, EAN-13 and GTIN-14 forms of the same code match.
+func normalizeUPC(upc string) string {
+	return strings.TrimLeft(upc, "0")
+}
+
+// resolveShipmentInput resolves the SKU of every line through the inventory cache index. Lines with a SKU are used as they are,
+// otherwise the SKU is looked up by FNSKU, ASIN or UPC in that order. UPCs that are not cached are searched in the catalog
+// and resolved by the ASIN found. Lines resolving to no SKU, to more than one SKU, or to a SKU whose cached identifiers
+// contradict the line are returned as problems. Lines with the same SKU are merged.
+func resolveShipmentInput(app AppCtx, index inventoryIdentifiers, marketplaceId string, lines []shipmentInputLine) ([]ShipmentInputRow, []shipmentInputProblem, error) {
+	var uncachedUPCs []string
+	for _, line := range lines {
+		if line.SKU == "" && line.FNSKU == "" && line.ASIN == "" && len(index.byUPC[normalizeUPC(line.UPC)]) == 0 && !slices.Contains(uncachedUPCs, line.UPC) {
+			uncachedUPCs = append(uncachedUPCs, line.UPC)
+		}
+	}
+	catalogASINs, err := searchCatalogASINsByUPC(app, marketplaceId, uncachedUPCs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []ShipmentInputRow
+	var problems []shipmentInputProblem
+	rowIndex := map[string]int{}
+	for _, line := range lines {
+		sku, problem := index.resolve(line, catalogASINs)
+		if problem != "" {
+			problems = append(problems, shipmentInputProblem{Line: line.Line, Identifier: line.identifier(), Problem: problem})
+			continue
+		}
+		if i, ok := rowIndex[sku]; ok {
+			log.Info().Str("sku", sku).Int("line", line.Line).Msg("sku is listed more than once, quantities are merged")
+			rows[i].Quantity += line.Quantity
+			rows[i].lines = append(rows[i].lines, line.Line)
+			continue
+		}
+		row := ShipmentInputRow{ASIN: line.ASIN, SKU: sku, Title: line.Title, Quantity: line.Quantity, lines: []int{line.Line}}
+		if product, ok := index.products[sku]; ok {
+			row.ASIN, row.Title = cmp.Or(row.ASIN, product.ASIN), cmp.Or(row.Title, product.Title)
+		}
+		rowIndex[sku] = len(rows)
+		rows = append(rows, row)
+	}
+	return rows, problems, nil
+}
+
+func (index inventoryIdentifiers) resolve(line shipmentInputLine, catalogASINs map[string][]string) (string, string) {
+	if line.SKU != "" {
+		if product, ok := index.products[line.SKU]; ok {
+			return line.SKU, index.conflict(line, product)
+		}
+		return line.SKU, ""
+	}
+
+	var candidates []string
+	var by string
+	switch {
+	case line.FNSKU != "":
+		candidates, by = index.byFNSKU[line.FNSKU], "FNSKU"
+	case line.ASIN != "":
+		candidates, by = index.byASIN[line.ASIN], "ASIN"
+	default:
+		by = "UPC"
+		candidates = index.byUPC[normalizeUPC(line.UPC)]
+		if len(candidates) == 0 {
+			asins, found := catalogASINs[normalizeUPC(line.UPC)]
+			if !found {
+				return "", "UPC not found in the inventory cache or the catalog"
+			}
+			if len(asins) > 1 {
+				return "", fmt.Sprintf("UPC matches more than one ASIN in the catalog: %s", strings.Join(asins, ", "))
+			}
+			candidates, by = index.byASIN[asins[0]], "ASIN "+asins[0]+" of UPC"
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Sprintf("no SKU with this %s in the inventory cache, add a SKU column or run `halycon inventory build`", by)
+	case 1:
+		return candidates[0], index.conflict(line, index.products[candidates[0]])
+	default:
+		return "", fmt.Sprintf("%s matches more than one SKU: %s, add a SKU column", by, strings.Join(candidates, ", "))
+	}
+}
+
+// conflict reports identifiers of the line that differ from the cached product of its SKU.
+func (index inventoryIdentifiers) conflict(line shipmentInputLine, product shipmentInputLine) string {
+	var conflicts []string
+	if line.ASIN != "" && product.ASIN != "" && line.ASIN != product.ASIN {
+		conflicts = append(conflicts, fmt.Sprintf("ASIN %s", product.ASIN))
+	}
+	if line.FNSKU != "" && product.FNSKU != "" && line.FNSKU != product.FNSKU {
+		conflicts = append(conflicts, fmt.Sprintf("FNSKU %s", product.FNSKU))
+	}
+	if line.UPC != "" && product.UPC != "" && normalizeUPC(line.UPC) != normalizeUPC(product.UPC) {
+		conflicts = append(conflicts, fmt.Sprintf("UPC %s", product.UPC))
+	}
+	if len(conflicts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("SKU %s has %s in the inventory cache", product.SKU, strings.Join(conflicts, ", "))
+}
+
+// searchCatalogASINsByUPC returns the ASINs of given UPCs in the marketplace, keyed by normalized UPC. UPCs without any item are not included.
+func searchCatalogASINsByUPC(app AppCtx, marketplaceId string, upcs []string) (map[string][]string, error) {
+	asins := map[string][]string{}
+	for batch := range slices.Chunk(upcs, searchCatalogItemsMaxIdentifiers) {
+		params := catalog.SearchCatalogItemsParams{
+			MarketplaceIds:  []string{marketplaceId},
+			IdentifiersType: internal.Ptr(catalog.UPC),
+			Identifiers:     &batch,
+			IncludedData:    &[]catalog.SearchCatalogItemsParamsIncludedData{"identifiers"},
+		}
+		resp, err := app.Amazon.Client.SearchCatalogItems(app.Ctx, &params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search catalog items by UPC: %w", err)
+		}
+		if resp.JSON200 == nil {
+			continue
+		}
+		for _, item := range resp.JSON200.Items {
+			if item.Identifiers == nil {
+				continue
+			}
+			for _, marketplace := range *item.Identifiers {
+				for _, identifier := range marketplace.Identifiers {
+					if identifier.IdentifierType != "UPC" && identifier.IdentifierType != "EAN" && identifier.IdentifierType != "GTIN" {
+						continue
+					}
+					upc := normalizeUPC(identifier.Identifier)
+					if !slices.Contains(asins[upc], string(item.Asin)) {
+						asins[upc] = append(asins[upc], string(item.Asin))
+					}
+				}
+			}
+		}
+	}
+	return asins, nil
+}
+
+// shipmentInputGroup is the part of the `shipment create` input that is created as a single inbound plan.
+type shipmentInputGroup struct {
+	ShipFrom       config.ShipFromConfig
+	MarketplaceIDs []string
+	Rows           []ShipmentInputRow
+
+	lines []shipmentInputLine
+}
+
+func (g shipmentInputGroup) marketplaceId() string {
+	if len(g.MarketplaceIDs) > 0 {
+		return g.MarketplaceIDs[0]
+	}
+	return ""
+}
+
+// readShipmentInput reads and resolves the `shipment create` input, displaying every row that could not be resolved.
+// Rows are grouped by their ship from address and destination marketplace, defaulting to shipFrom and the marketplaces
+// of the default merchant, and every group is resolved in its own marketplace.
+func readShipmentInput(app AppCtx, path string, shipFrom config.ShipFromConfig) ([]shipmentInputGroup, error) {
+	input, err := internal.OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	lines, problems, err := readShipmentInputLines(input)
+	if err != nil {
+		return nil, err
+	}
+	total := len(lines) + len(problems)
+
+	type groupKey struct {
+		shipFrom     config.ShipFromConfig
+		marketplaces string
+	}
+	var groups []shipmentInputGroup
+	groupIndex := map[groupKey]int{}
+	for _, line := range lines {
+		address := shipFrom
+		if line.ShipFrom != "" {
+			if address, err = findShipFrom(line.ShipFrom); err != nil {
+				problems = append(problems, shipmentInputProblem{Line: line.Line, Identifier: line.identifier(), Problem: err.Error()})
+				continue
+			}
+		}
+		// the default address may be marked as default while its entry in the config list is not
+		address.Default = false
+		marketplaces := cfg.Amazon.Auth.DefaultMerchant.MarketplaceID
+		if line.Marketplace != "" {
+			marketplaces = []string{line.Marketplace}
+		}
+		key := groupKey{shipFrom: address, marketplaces: strings.Join(marketplaces, ",")}
+		i, ok := groupIndex[key]
+		if !ok {
+			i = len(groups)
+			groupIndex[key] = i
+			groups = append(groups, shipmentInputGroup{ShipFrom: address, MarketplaceIDs: marketplaces})
+		}
+		groups[i].lines = append(groups[i].lines, line)
+	}
+	index, err := loadInventoryIdentifiers(app)
+	if err != nil {
+		return nil, err
+	}
+	if len(index.products) == 0 {
+		log.Warn().Msg("inventory cache is empty, run `halycon inventory build` to resolve rows without a SKU")
+	}
+	for i, group := range groups {
+		rows, unresolved, err := resolveShipmentInput(app, index, group.marketplaceId(), group.lines)
+		if err != nil {
+			return nil, err
+		}
+		groups[i].Rows = rows
+		problems = append(problems, unresolved...)
+	}
+
+	if len(problems) > 0 {
+		slices.SortFunc(problems, func(a, b shipmentInputProblem) int { return a.Line - b.Line })
+		fmt.Printf("%-6s %-20s %s\n", "Row", "Identifier", "Problem")
+		fmt.Println(color.HiBlackString("%s", "--------------------------------------------------------------------------------"))
+		for _, problem := range problems {
+			fmt.Printf("%-6d %-20s %s\n", problem.Line, truncateString(problem.Identifier, 20), problem.Problem)
+		}
+		return nil, fmt.Errorf("%d of %d rows could not be resolved", len(problems), total)
+	}
+	if len(groups) == 0 {
+		return nil, errors.New("input has no items")
+	}
+	return groups, nil
+}
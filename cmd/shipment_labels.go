@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound_v0"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type shipmentLabelsConfig struct {
+	InboundPlanId string
+	PageType      string
+	LabelType     string
+	Pallets       int
+	Challan       bool
+	Directory     string
+	Print         bool
+	PrintCommand  string
+}
+
+var (
+	shipmentLabelsCmd = &cobra.Command{
+		Use:   "labels [shipment id or confirmation id]",
+		Short: "downloads box, pallet and delivery challan labels of a confirmed shipment, and optionally prints them",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(shipmentLabels, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound, ServiceFBAInboundV0}}),
+	}
+	shipmentLabelsCfg shipmentLabelsConfig
+)
+
+var labelPageTypes = []fba_inbound_v0.GetLabelsParamsPageType{
+	fba_inbound_v0.PackageLabelPlainPaper,
+	fba_inbound_v0.PackageLabelPlainPaperCarrierBottom,
+	fba_inbound_v0.PackageLabelLetter2,
+	fba_inbound_v0.PackageLabelLetter4,
+	fba_inbound_v0.PackageLabelLetter6,
+	fba_inbound_v0.PackageLabelLetter6CarrierLeft,
+	fba_inbound_v0.PackageLabelA42,
+	fba_inbound_v0.PackageLabelA44,
+	fba_inbound_v0.PackageLabelThermal,
+	fba_inbound_v0.PackageLabelThermalUnified,
+	fba_inbound_v0.PackageLabelThermalNonPCP,
+	fba_inbound_v0.PackageLabelThermalNoCarrierRotation,
+}
+
+var labelTypes = []fba_inbound_v0.GetLabelsParamsLabelType{fba_inbound_v0.UNIQUE, fba_inbound_v0.BARCODE2D, fba_inbound_v0.PALLET}
+
+func getShipmentLabelsCmd() *cobra.Command {
+	pageTypes := make([]string, 0, len(labelPageTypes))
+	for _, pageType := range labelPageTypes {
+		pageTypes = append(pageTypes, string(pageType))
+	}
+	flags := shipmentLabelsCmd.PersistentFlags()
+	flags.StringVar(&shipmentLabelsCfg.InboundPlanId, "plan", "", "inbound plan of the shipment, required if the shipment is not tracked locally")
+	flags.StringVar(&shipmentLabelsCfg.PageType, "page-type", "", fmt.Sprintf("page type of labels (%s), defaults to fba.labels.page_type in config or %s", strings.Join(pageTypes, ", "), fba_inbound_v0.PackageLabelPlainPaper))
+	flags.StringVar(&shipmentLabelsCfg.LabelType, "label-type", string(fba_inbound_v0.UNIQUE), "UNIQUE for box labels of every box, BARCODE_2D for box labels with 2D barcodes of box contents, PALLET for pallet labels")
+	flags.IntVar(&shipmentLabelsCfg.Pallets, "pallets", 0, "number of pallets, required for PALLET labels")
+	flags.BoolVar(&shipmentLabelsCfg.Challan, "challan", false, "also download the delivery challan document (India marketplace only)")
+	flags.StringVarP(&shipmentLabelsCfg.Directory, "output-dir", "o", "", "directory labels are saved under, defaults to fba.labels.directory in config or labels")
+	flags.BoolVar(&shipmentLabelsCfg.Print, "print", false, "pipe downloaded documents to the print command")
+	flags.StringVar(&shipmentLabelsCfg.PrintCommand, "print-command", "", "print command documents are piped to, overrides fba.labels.print_command and fba.labels.thermal_print_command in config")
+	return shipmentLabelsCmd
+}
+
+// shipmentLabelOptions are the labels downloaded for a shipment. PageType, LabelType and Directory must be set.
+type shipmentLabelOptions struct {
+	PageType  fba_inbound_v0.GetLabelsParamsPageType
+	LabelType fba_inbound_v0.GetLabelsParamsLabelType
+	Pallets   int
+	Challan   bool
+	Directory string
+}
+
+func shipmentLabels(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	options, err := shipmentLabelOptionsFromFlags()
+	if err != nil {
+		log.Error().Err(err).Msg("invalid label options")
+		return
+	}
+	shipment, err := resolveInboundShipment(app, args[0], shipmentLabelsCfg.InboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("shipment_id", args[0]).Msg("failed to get shipment")
+		return
+	}
+	paths, err := downloadShipmentLabels(app, shipment, options)
+	for _, path := range paths {
+		fmt.Printf("%s %s\n", color.GreenString("saved"), path)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("shipment_id", shipment.ShipmentID).Msg("failed to download labels")
+		return
+	}
+	if !shipmentLabelsCfg.Print {
+		return
+	}
+	for _, path := range paths {
+		if err := printLabel(path, options.PageType); err != nil {
+			log.Error().Err(err).Str("path", path).Msg("failed to print document")
+			return
+		}
+		fmt.Printf("%s %s\n", color.GreenString("printed"), path)
+	}
+}
+
+func shipmentLabelOptionsFromFlags() (shipmentLabelOptions, error) {
+	options := shipmentLabelOptions{
+		PageType:  fba_inbound_v0.GetLabelsParamsPageType(cmp.Or(shipmentLabelsCfg.PageType, cfg.Amazon.FBA.Labels.PageType, string(fba_inbound_v0.PackageLabelPlainPaper))),
+		LabelType: fba_inbound_v0.GetLabelsParamsLabelType(strings.ToUpper(shipmentLabelsCfg.LabelType)),
+		Pallets:   shipmentLabelsCfg.Pallets,
+		Challan:   shipmentLabelsCfg.Challan,
+		Directory: cmp.Or(shipmentLabelsCfg.Directory, cfg.Amazon.FBA.Labels.Directory, "labels"),
+	}
+	if !slices.Contains(labelPageTypes, options.PageType) {
+		return options, fmt.Errorf("unknown page type %s", options.PageType)
+	}
+	if !slices.Contains(labelTypes, options.LabelType) {
+		return options, fmt.Errorf("unknown label type %s", options.LabelType)
+	}
+	if options.LabelType == fba_inbound_v0.PALLET && options.Pallets <= 0 {
+		return options, errors.New("--pallets is required for PALLET labels")
+	}
+	return options, nil
+}
+
+// resolveInboundShipment finds a shipment by its ID or confirmation ID in the local database, or fetches it from given inbound plan.
+// The confirmation ID is fetched and recorded if it is not known yet, as labels are requested with it.
+func resolveInboundShipment(app AppCtx, id string, inboundPlanId string) (db.InboundPlanShipment, error) {
+	shipment, err := app.Query.FindInboundPlanShipment(app.Ctx, id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if inboundPlanId == "" {
+			return shipment, errors.New("shipment is not tracked locally, pass its inbound plan with --plan")
+		}
+		shipment = db.InboundPlanShipment{InboundPlanID: inboundPlanId, ShipmentID: id}
+	case err != nil:
+		return shipment, err
+	case shipment.ConfirmationID.Valid:
+		return shipment, nil
+	}
+
+	resp, err := app.Amazon.Client.GetInboundShipment(app.Ctx, shipment.InboundPlanID, shipment.ShipmentID)
+	if err != nil {
+		return shipment, err
+	}
+	if resp.JSON200.ShipmentConfirmationId == nil {
+		return shipment, errors.New("shipment is not confirmed yet, confirm its transportation options first")
+	}
+	shipment.ConfirmationID = sql.NullString{String: *resp.JSON200.ShipmentConfirmationId, Valid: true}
+	if err := app.Query.UpdateInboundPlanShipmentConfirmationID(app.Ctx, db.UpdateInboundPlanShipmentConfirmationIDParams{
+		ConfirmationID: shipment.ConfirmationID,
+		InboundPlanID:  shipment.InboundPlanID,
+		ShipmentID:     shipment.ShipmentID,
+	}); err != nil {
+		return shipment, fmt.Errorf("failed to record confirmation id: %w", err)
+	}
+	return shipment, nil
+}
+
+// downloadShipmentLabels downloads the labels of a confirmed shipment into <directory>/<inbound plan>/<confirmation id>/, and returns the
+// paths of the saved documents. Box labels are requested for every box of the shipment.
+func downloadShipmentLabels(app AppCtx, shipment db.InboundPlanShipment, options shipmentLabelOptions) ([]string, error) {
+	var paths []string
+	directory := filepath.Join(options.Directory, shipment.InboundPlanID, shipment.ConfirmationID.String)
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return paths, fmt.Errorf("failed to create label directory: %w", err)
+	}
+
+	params := fba_inbound_v0.GetLabelsParams{PageType: options.PageType, LabelType: options.LabelType}
+	if options.LabelType == fba_inbound_v0.PALLET {
+		params.NumberOfPallets = &options.Pallets
+	} else {
+		boxes, err := listShipmentBoxes(app, shipment.InboundPlanID, shipment.ShipmentID)
+		if err != nil {
+			return paths, fmt.Errorf("failed to list boxes: %w", err)
+		}
+		if len(boxes) == 0 {
+			return paths, errors.New("shipment has no boxes")
+		}
+		packageIds := make([]string, 0, len(boxes))
+		for _, box := range boxes {
+			packageIds = append(packageIds, box.PackageId)
+		}
+		params.NumberOfPackages = internal.Ptr(len(packageIds))
+		if options.LabelType == fba_inbound_v0.UNIQUE {
+			params.PackageLabelsToPrint = &packageIds
+		}
+	}
+	resp, err := app.Amazon.Client.GetLabels(app.Ctx, shipment.ConfirmationID.String, &params)
+	if err != nil {
+		return paths, err
+	}
+	if resp.JSON200.Payload == nil || resp.JSON200.Payload.DownloadURL == nil {
+		return paths, errors.New("labels have no download url")
+	}
+	path, err := downloadDocument(*resp.JSON200.Payload.DownloadURL, filepath.Join(directory, fmt.Sprintf("%s_%s", options.LabelType, options.PageType)))
+	if err != nil {
+		return paths, fmt.Errorf("failed to download labels: %w", err)
+	}
+	paths = append(paths, path)
+
+	if options.Challan {
+		challan, err := app.Amazon.Client.GetDeliveryChallanDocument(app.Ctx, shipment.InboundPlanID, shipment.ShipmentID)
+		if err != nil {
+			return paths, fmt.Errorf("failed to get delivery challan document: %w", err)
+		}
+		path, err := downloadDocument(challan.JSON200.DocumentDownload.Uri, filepath.Join(directory, "delivery_challan"))
+		if err != nil {
+			return paths, fmt.Errorf("failed to download delivery challan document: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func listShipmentBoxes(app AppCtx, inboundPlanId string, shipmentId string) ([]fba_inbound.Box, error) {
+	var boxes []fba_inbound.Box
+	params := fba_inbound.ListShipmentBoxesParams{PageSize: internal.Ptr(1000)}
+	for {
+		resp, err := app.Amazon.Client.ListShipmentBoxes(app.Ctx, inboundPlanId, shipmentId, &params)
+		if err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, resp.JSON200.Boxes...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return boxes, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+// downloadDocument saves the document at uri to path, with the extension of its content type, pdf if unknown.
+func downloadDocument(uri string, path string) (string, error) {
+	client := &http.Client{
+		Timeout: time.Minute * 2,
+	}
+	resp, err := client.Get(uri) //nolint:bodyclose
+	if err != nil {
+		return "", err
+	}
+	defer internal.CloseReader(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed with status %s", resp.Status)
+	}
+	extension := ".pdf"
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		switch mediaType {
+		case "application/zip":
+			extension = ".zip"
+		case "application/zpl", "text/plain":
+			extension = ".zpl"
+		case "image/png":
+			extension = ".png"
+		}
+	}
+	path += extension
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		// a partial document would be printed or merged as if it was complete
+		file.Close()    //nolint:errcheck
+		os.Remove(path) //nolint:errcheck
+		return "", err
+	}
+	return path, file.Close()
+}
+
+// printLabel pipes the document to the print command. Thermal page types use the thermal print command if configured.
+func printLabel(path string, pageType fba_inbound_v0.GetLabelsParamsPageType) error {
+	command := cfg.Amazon.FBA.Labels.PrintCommand
+	if strings.Contains(string(pageType), "Thermal") && cfg.Amazon.FBA.Labels.ThermalPrintCommand != "" {
+		command = cfg.Amazon.FBA.Labels.ThermalPrintCommand
+	}
+	command = cmp.Or(shipmentLabelsCfg.PrintCommand, command)
+	if command == "" {
+		command = "lpr"
+		if _, err := exec.LookPath("lp"); err == nil {
+			command = "lp"
+		}
+	}
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return fmt.Errorf("print command is empty")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	printer := exec.Command(fields[0], fields[1:]...)
+	printer.Stdin = file
+	printer.Stdout = os.Stdout
+	printer.Stderr = os.Stderr
+	if err := printer.Run(); err != nil {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/rs/zerolog/log"
+)
+
+// operationWaitConfig controls how long and how often an inbound operation is polled.
+type operationWaitConfig struct {
+	Timeout time.Duration
+	// Interval is the delay before the second poll, multiplied by 1.5 after every poll up to MaxInterval.
+	Interval    time.Duration
+	MaxInterval time.Duration
+}
+
+var defaultOperationWait = operationWaitConfig{
+	Timeout:     10 * time.Minute,
+	Interval:    time.Second,
+	MaxInterval: 15 * time.Second,
+}
+
+// validate rejects durations that would poll in a busy loop or never poll at all.
+func (c operationWaitConfig) validate() error {
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, got %s", c.Timeout)
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", c.Interval)
+	}
+	if c.MaxInterval <= 0 {
+		return fmt.Errorf("max interval must be positive, got %s", c.MaxInterval)
+	}
+	return nil
+}
+
+// Exit codes of `shipment operation status --wait`.
+const (
+	operationExitSuccess = 0
+	operationExitFailed  = 1
+	operationExitTimeout = 2
+	operationExitError   = 3
+)
+
+var errOperationTimeout = errors.New("timed out waiting for operation")
+
+// OperationFailedError is returned by waitForOperation when the operation completes with FAILED status.
+type OperationFailedError struct {
+	Status *fba_inbound.InboundOperationStatus
+}
+
+func (e *OperationFailedError) Error() string {
+	problems := make([]string, 0, len(e.Status.OperationProblems))
+	for _, problem := range e.Status.OperationProblems {
+		text := fmt.Sprintf("%s: %s", problem.Code, problem.Message)
+		if problem.Details != nil {
+			text += fmt.Sprintf(" (%s)", *problem.Details)
+		}
+		problems = append(problems, text)
+	}
+	return fmt.Sprintf("operation %s %s failed: %s", e.Status.Operation, e.Status.OperationId, strings.Join(problems, "; "))
+}
+
+// waitForOperation polls an inbound operation with the default wait config until it succeeds or fails.
+func waitForOperation(app AppCtx, operationId string) (*fba_inbound.InboundOperationStatus, error) {
+	return waitForOperationWith(app, operationId, defaultOperationWait)
+}
+
+// waitForOperationWith polls an inbound operation with exponential backoff until it succeeds, fails or times out,
+// displaying a spinner on terminals. Problems of failed operations are displayed and returned as *OperationFailedError.
+func waitForOperationWith(app AppCtx, operationId string, wait operationWaitConfig) (*fba_inbound.InboundOperationStatus, error) {
+	progress := newOperationProgress(operationId)
+	defer progress.Stop()
+
+	deadline := time.Now().Add(wait.Timeout)
+	interval := wait.Interval
+	for {
+		resp, err := app.Amazon.Client.GetInboundOperationStatus(app.Ctx, operationId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get operation status: %w", err)
+		}
+		status := resp.JSON200
+		progress.Update(status)
+		switch status.OperationStatus {
+		case fba_inbound.SUCCESS:
+			progress.Stop()
+			log.Info().Str("operation_id", operationId).Str("operation", status.Operation).Msg("operation succeeded")
+			return status, nil
+		case fba_inbound.FAILED:
+			progress.Stop()
+			logOperationStatus(status)
+			return status, &OperationFailedError{Status: status}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return status, fmt.Errorf("%w %s after %s", errOperationTimeout, operationId, wait.Timeout)
+		}
+		select {
+		case <-app.Ctx.Done():
+			return status, app.Ctx.Err()
+		case <-time.After(min(interval, remaining)):
+		}
+		interval = min(interval*3/2, wait.MaxInterval)
+	}
+}
+
+// logOperationStatus logs the status and the problems of an operation.
+func logOperationStatus(status *fba_inbound.InboundOperationStatus) {
+	logger := log.With().
+		Str("id", status.OperationId).
+		Str("operation", status.Operation).
+		Str("status", string(status.OperationStatus)).
+		Logger()
+	if status.OperationStatus == fba_inbound.FAILED {
+		logger.Warn().Send()
+	} else {
+		logger.Info().Send()
+	}
+	for i, problem := range status.OperationProblems {
+		ev := log.Warn().
+			Str("code", problem.Code).
+			Str("message", problem.Message).
+			Str("severity", problem.Severity)
+		if problem.Details != nil {
+			ev.Str("details", *problem.Details)
+		}
+		ev.Msgf("problem %d", i+1)
+	}
+}
+
+// operationExitCode maps the result of waitForOperationWith to the exit codes of `shipment operation status --wait`.
+func operationExitCode(err error) int {
+	var failed *OperationFailedError
+	switch {
+	case err == nil:
+		return operationExitSuccess
+	case errors.As(err, &failed):
+		return operationExitFailed
+	case errors.Is(err, errOperationTimeout):
+		return operationExitTimeout
+	default:
+		return operationExitError
+	}
+}
+
+type operationStatusMsg struct {
+	Operation string
+	Status    fba_inbound.OperationStatus
+}
+
+type operationDoneMsg struct{}
+
+type operationProgressModel struct {
+	spinner     spinner.Model
+	operationId string
+	operation   string
+	status      fba_inbound.OperationStatus
+	started     time.Time
+	done        bool
+}
+
+func (m operationProgressModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m operationProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case operationStatusMsg:
+		m.operation = msg.Operation
+		m.status = msg.Status
+		return m, nil
+	case operationDoneMsg:
+		m.done = true
+		return m, tea.Quit
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m operationProgressModel) View() string {
+	if m.done {
+		return ""
+	}
+	operation := m.operation
+	if operation == "" {
+		operation = m.operationId
+	}
+	status := string(m.status)
+	if status == "" {
+		status = "PENDING"
+	}
+	elapsed := time.Since(m.started).Truncate(time.Second)
+	return fmt.Sprintf("%s waiting for %s %s %s\n", m.spinner.View(), operation, lipgloss.NewStyle().Bold(true).Render(status), lipgloss.NewStyle().Faint(true).Render(elapsed.String()))
+}
+
+// operationProgress displays a spinner on stderr while an operation is polled, and logs status changes at debug level otherwise.
+type operationProgress struct {
+	operationId string
+	program     *tea.Program
+	done        chan struct{}
+	stopped     bool
+}
+
+func newOperationProgress(operationId string) *operationProgress {
+	progress := &operationProgress{operationId: operationId}
+	if !isatty.IsTerminal(os.Stderr.Fd()) {
+		return progress
+	}
+	model := operationProgressModel{
+		spinner:     spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED")))),
+		operationId: operationId,
+		started:     time.Now(),
+	}
+	progress.program = tea.NewProgram(model, tea.WithOutput(os.Stderr), tea.WithInput(nil))
+	progress.done = make(chan struct{})
+	go func() {
+		if _, err := progress.program.Run(); err != nil {
+			log.Debug().Err(err).Msg("operation progress display failed")
+		}
+		close(progress.done)
+	}()
+	return progress
+}
+
+func (p *operationProgress) Update(status *fba_inbound.InboundOperationStatus) {
+	if p.program == nil {
+		log.Debug().Str("operation_id", p.operationId).Str("operation", status.Operation).Str("status", string(status.OperationStatus)).Msg("polled operation")
+		return
+	}
+	p.program.Send(operationStatusMsg{Operation: status.Operation, Status: status.OperationStatus})
+}
+
+// Stop clears the spinner, it is safe to call multiple times.
+func (p *operationProgress) Stop() {
+	if p.program == nil || p.stopped {
+		return
+	}
+	p.stopped = true
+	p.program.Send(operationDoneMsg{})
+	<-p.done
+}
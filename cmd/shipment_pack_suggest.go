@@ -0,0 +1,510 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/config"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type shipmentPackSuggestConfig struct {
+	Cartons       []string
+	Dimensions    []string
+	DimensionUnit string
+	WeightUnit    string
+	MaxWeight     float64
+	Fill          float64
+	SingleSku     bool
+	Refresh       bool
+	Output        string
+}
+
+var (
+	shipmentPackSuggestCmd = &cobra.Command{
+		Use:   "pack-suggest [inbound plan id]",
+		Short: "suggests box contents of an inbound plan by packing units into cartons from catalog dimensions and weights",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(shipmentPackSuggest, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound, ServiceCatalog}}),
+	}
+	shipmentPackSuggestCfg shipmentPackSuggestConfig
+)
+
+func getShipmentPackSuggestCmd() *cobra.Command {
+	flags := shipmentPackSuggestCmd.PersistentFlags()
+	flags.StringArrayVar(&shipmentPackSuggestCfg.Cartons, "carton", nil, "carton size as [name=]LxWxH, can be repeated, overrides the cartons in config")
+	flags.StringArrayVar(&shipmentPackSuggestCfg.Dimensions, "dimensions", nil, "dimensions and weight of a single unit as MSKU=LxWxH:weight, for items missing from the catalog, stored for later runs")
+	flags.StringVar(&shipmentPackSuggestCfg.DimensionUnit, "dimension-unit", string(fba_inbound.IN), "unit of --carton and --dimensions, IN or CM")
+	flags.StringVar(&shipmentPackSuggestCfg.WeightUnit, "weight-unit", string(fba_inbound.LB), "unit of --max-weight and --dimensions, LB or KG")
+	flags.Float64Var(&shipmentPackSuggestCfg.MaxWeight, "max-weight", 0, "maximum gross weight of a --carton (default 50 lb)")
+	flags.Float64Var(&shipmentPackSuggestCfg.Fill, "fill", 0.85, "fraction of the carton volume that can be filled, leaves room for imperfect stacking and padding")
+	flags.BoolVar(&shipmentPackSuggestCfg.SingleSku, "single-sku", false, "do not mix SKUs in a box")
+	flags.BoolVar(&shipmentPackSuggestCfg.Refresh, "refresh", false, "search the catalog for dimensions again instead of using the cached ones")
+	flags.StringVarP(&shipmentPackSuggestCfg.Output, "output", "o", "", "box content file, yaml or csv (default box_contents_<plan>.yaml)")
+	return shipmentPackSuggestCmd
+}
+
+// packingCarton is a carton size with sides sorted from longest to shortest in inches and weights in pounds.
+// Dimensions and units as configured are kept for the suggested box content.
+type packingCarton struct {
+	Name      string
+	Sides     [3]float64
+	Weight    float64
+	MaxWeight float64
+	config    config.CartonConfig
+}
+
+func (c packingCarton) volume() float64 {
+	return c.Sides[0] * c.Sides[1] * c.Sides[2]
+}
+
+// packingUnit is a single unit of a SKU with sides sorted from longest to shortest in inches and weight in pounds.
+type packingUnit struct {
+	Msku   string
+	Sides  [3]float64
+	Weight float64
+}
+
+func (u packingUnit) volume() float64 {
+	return u.Sides[0] * u.Sides[1] * u.Sides[2]
+}
+
+func (u packingUnit) fits(carton packingCarton) bool {
+	return u.Sides[0] <= carton.Sides[0] && u.Sides[1] <= carton.Sides[1] && u.Sides[2] <= carton.Sides[2]
+}
+
+// packedBox is a suggested box. Boxes without a carton hold a single unit that does not fit into any carton, shipped in its own packaging.
+type packedBox struct {
+	Carton *packingCarton
+	Units  []packingUnit
+	Volume float64
+	Weight float64
+}
+
+func (b packedBox) grossWeight() float64 {
+	if b.Carton == nil {
+		return b.Weight
+	}
+	return b.Weight + b.Carton.Weight
+}
+
+// accepts reports whether unit can be added to the box without exceeding the carton size, the fillable volume or the weight limit.
+// Any unit that fits into an empty carton is accepted regardless of its volume.
+func (b packedBox) accepts(unit packingUnit, carton packingCarton, fill float64) bool {
+	if !unit.fits(carton) || b.Weight+unit.Weight+carton.Weight > carton.MaxWeight {
+		return false
+	}
+	return len(b.Units) == 0 || b.Volume+unit.volume() <= carton.volume()*fill
+}
+
+func shipmentPackSuggest(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	inboundPlanId := args[0]
+	cartons, err := packingCartons()
+	if err != nil {
+		log.Error().Err(err).Msg("invalid cartons")
+		return
+	}
+	if shipmentPackSuggestCfg.Fill <= 0 || shipmentPackSuggestCfg.Fill > 1 {
+		log.Error().Float64("fill", shipmentPackSuggestCfg.Fill).Msg("fill must be between 0 and 1")
+		return
+	}
+	plan, err := getOrTrackInboundPlan(app, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to get inbound plan")
+		return
+	}
+	if !plan.PackingOptionID.Valid {
+		log.Error().Str("inbound_plan_id", inboundPlanId).Msg("packing option is not confirmed yet, run shipment workflow until it asks for packing information")
+		return
+	}
+	groups, err := listConfirmedPackingGroups(app, plan)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list packing groups")
+		return
+	}
+	marketplaceId := plan.MarketplaceID.String
+	if marketplaceId == "" {
+		marketplaceId = cfg.Amazon.Auth.DefaultMerchant.MarketplaceID[0]
+	}
+
+	asins := map[string]string{}
+	for _, packingGroupId := range groups.IDs {
+		for _, item := range groups.Items[packingGroupId] {
+			asins[item.Msku] = item.Asin
+		}
+	}
+	for _, raw := range shipmentPackSuggestCfg.Dimensions {
+		msku, dimensions, err := parseUnitDimensions(raw)
+		if err != nil {
+			log.Error().Err(err).Str("dimensions", raw).Msg("invalid unit dimensions")
+			return
+		}
+		asin, ok := asins[msku]
+		if !ok {
+			log.Error().Str("msku", msku).Msg("sku is not in the inbound plan")
+			return
+		}
+		if err := saveItemDimensions(app.Ctx, app.Query, marketplaceId, asin, dimensions, dimensionSourceManual); err != nil {
+			log.Error().Err(err).Str("msku", msku).Msg("failed to save unit dimensions")
+			return
+		}
+	}
+	dimensions, err := loadItemDimensions(app, marketplaceId, slices.Compact(slices.Sorted(maps.Values(asins))), shipmentPackSuggestCfg.Refresh)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load catalog dimensions")
+		return
+	}
+	var missing []string
+	for msku, asin := range asins {
+		if _, ok := dimensions[asin]; !ok {
+			missing = append(missing, fmt.Sprintf("%s (%s)", msku, asin))
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		log.Error().Strs("items", missing).Msg("catalog has no dimensions for some items, provide them with --dimensions MSKU=LxWxH:weight")
+		return
+	}
+
+	var content boxContent
+	boxes, boxGroups, err := packPackingGroups(groups, dimensions, cartons, shipmentPackSuggestCfg.Fill, shipmentPackSuggestCfg.SingleSku)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to pack units")
+		return
+	}
+	content.Boxes = boxContentFromPackedBoxes(boxes, boxGroups)
+
+	request, err := buildPackingInformation(content, groups)
+	if err == nil {
+		err = validatePackingInformation(request, groups)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("suggested box content is invalid")
+		return
+	}
+	output := shipmentPackSuggestCfg.Output
+	if output == "" {
+		output = fmt.Sprintf("box_contents_%s.yaml", inboundPlanId)
+	}
+	if err := writeBoxContent(output, content); err != nil {
+		log.Error().Err(err).Msg("failed to write box content")
+		return
+	}
+
+	fmt.Printf("%-8s %-40s %-24s %6s %6s %12s %s\n", "Box", "Packing Group", "Carton", "Count", "Units", "Weight", "SKUs")
+	fmt.Println(color.HiBlackString("%s", strings.Repeat("-", 120)))
+	total := 0
+	for _, box := range content.Boxes {
+		units := 0
+		mskus := make([]string, 0, len(box.Items))
+		for _, item := range box.Items {
+			units += item.Quantity
+			mskus = append(mskus, fmt.Sprintf("%s x%d", item.Msku, item.Quantity))
+		}
+		carton := fmt.Sprintf("%gx%gx%g %s", box.Length, box.Width, box.Height, box.DimensionUnit)
+		fmt.Printf("%-8s %-40s %-24s %6d %6d %12s %s\n", box.ID, box.PackingGroup, truncateString(carton, 24), box.Count, units, fmt.Sprintf("%g %s", box.Weight, box.WeightUnit), strings.Join(mskus, ", "))
+		total += box.Count
+	}
+	fmt.Println(color.HiBlackString("%s", strings.Repeat("-", 120)))
+	color.Green("%d boxes suggested, box content is written to %s", total, output)
+	fmt.Printf("Submit with: halycon shipment workflow %s --packing-info %s\n", inboundPlanId, output)
+}
+
+// packingCartons returns the cartons given with --carton, or the cartons in config, sorted from smallest to largest.
+func packingCartons() ([]packingCarton, error) {
+	configured := cfg.Amazon.FBA.Cartons
+	if len(shipmentPackSuggestCfg.Cartons) > 0 {
+		configured = nil
+		for _, raw := range shipmentPackSuggestCfg.Cartons {
+			name, size, _ := strings.Cut(raw, "=")
+			if size == "" {
+				name, size = "", name
+			}
+			sides, err := parseSides(size)
+			if err != nil {
+				return nil, fmt.Errorf("carton %s: %w", raw, err)
+			}
+			configured = append(configured, config.CartonConfig{
+				Name:          name,
+				Length:        sides[0],
+				Width:         sides[1],
+				Height:        sides[2],
+				DimensionUnit: shipmentPackSuggestCfg.DimensionUnit,
+				MaxWeight:     shipmentPackSuggestCfg.MaxWeight,
+				WeightUnit:    shipmentPackSuggestCfg.WeightUnit,
+			})
+		}
+	}
+	if len(configured) == 0 {
+		return nil, errors.New("no cartons, pass them with --carton or add them to fba.cartons in config")
+	}
+
+	cartons := make([]packingCarton, 0, len(configured))
+	var errs []error
+	for _, c := range configured {
+		c.DimensionUnit = strings.ToUpper(cmp.Or(c.DimensionUnit, string(fba_inbound.IN)))
+		c.WeightUnit = strings.ToUpper(cmp.Or(c.WeightUnit, string(fba_inbound.LB)))
+		name := cmp.Or(c.Name, fmt.Sprintf("%gx%gx%g %s", c.Length, c.Width, c.Height, c.DimensionUnit))
+		inches, err := unitFactor(c.DimensionUnit, string(fba_inbound.IN), string(fba_inbound.CM), inchesPerCentimeter)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("carton %s: %w", name, err))
+			continue
+		}
+		pounds, err := unitFactor(c.WeightUnit, string(fba_inbound.LB), string(fba_inbound.KG), poundsPerKilogram)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("carton %s: %w", name, err))
+			continue
+		}
+		carton := packingCarton{
+			Name:      name,
+			Sides:     sortedSides(c.Length*inches, c.Width*inches, c.Height*inches),
+			Weight:    c.Weight * pounds,
+			MaxWeight: maxBoxWeightPounds,
+			config:    c,
+		}
+		if c.MaxWeight > 0 {
+			carton.MaxWeight = c.MaxWeight * pounds
+		}
+		switch {
+		case carton.Sides[2] <= 0:
+			errs = append(errs, fmt.Errorf("carton %s has no dimensions", name))
+		case carton.Sides[0] > maxBoxSideInches:
+			errs = append(errs, fmt.Errorf("carton %s has a side of %.1f in, boxes with multiple units cannot exceed %g in", name, carton.Sides[0], maxBoxSideInches))
+		case carton.MaxWeight > maxBoxWeightPounds:
+			errs = append(errs, fmt.Errorf("carton %s has a max weight of %.1f lb, boxes with multiple units cannot exceed %g lb", name, carton.MaxWeight, maxBoxWeightPounds))
+		case carton.Weight >= carton.MaxWeight:
+			errs = append(errs, fmt.Errorf("carton %s weighs more than its max weight", name))
+		default:
+			cartons = append(cartons, carton)
+		}
+	}
+	slices.SortStableFunc(cartons, func(a, b packingCarton) int { return cmp.Compare(a.volume(), b.volume()) })
+	return cartons, errors.Join(errs...)
+}
+
+// packPackingGroups packs the units of every packing group separately, and every SKU separately if singleSku is set.
+// dimensions are keyed by ASIN. Returns the boxes and the packing group of each box.
+func packPackingGroups(groups packingGroups, dimensions map[string]itemDimensions, cartons []packingCarton, fill float64, singleSku bool) ([]packedBox, []string, error) {
+	var boxes []packedBox
+	var boxGroups []string
+	var errs []error
+	for _, packingGroupId := range groups.IDs {
+		var batches [][]packingUnit
+		var units []packingUnit
+		for _, item := range groups.Items[packingGroupId] {
+			d := dimensions[item.Asin]
+			unit := packingUnit{Msku: item.Msku, Sides: sortedSides(d.Length, d.Width, d.Height), Weight: d.Weight}
+			if singleSku {
+				batches = append(batches, slices.Repeat([]packingUnit{unit}, item.Quantity))
+				continue
+			}
+			units = append(units, slices.Repeat([]packingUnit{unit}, item.Quantity)...)
+		}
+		if !singleSku {
+			batches = append(batches, units)
+		}
+		for _, batch := range batches {
+			packed, err := packUnits(batch, cartons, fill)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("packing group %s: %w", packingGroupId, err))
+				continue
+			}
+			for _, box := range packed {
+				boxes = append(boxes, box)
+				boxGroups = append(boxGroups, packingGroupId)
+			}
+		}
+	}
+	return boxes, boxGroups, errors.Join(errs...)
+}
+
+// packUnits packs units into as few boxes as possible with first fit decreasing. Every carton is tried as the preferred size of new
+// boxes, falling back to the largest carton a unit fits, and the suggestion with the fewest boxes and then the least carton volume wins.
+// Every box is shrunk to the smallest carton that holds its units at the end. Units that do not fit into any carton are packed alone.
+func packUnits(units []packingUnit, cartons []packingCarton, fill float64) ([]packedBox, error) {
+	units = slices.Clone(units)
+	slices.SortStableFunc(units, func(a, b packingUnit) int {
+		return cmp.Or(cmp.Compare(b.volume(), a.volume()), cmp.Compare(b.Weight, a.Weight), cmp.Compare(a.Msku, b.Msku))
+	})
+
+	var oversized []packedBox
+	var packable []packingUnit
+	var errs []error
+	for _, unit := range units {
+		if slices.ContainsFunc(cartons, func(c packingCarton) bool { return packedBox{}.accepts(unit, c, fill) }) {
+			packable = append(packable, unit)
+			continue
+		}
+		if unit.Weight > maxSingleUnitBoxWeightPounds {
+			errs = append(errs, fmt.Errorf("a unit of %s weighs %.1f lb, boxes cannot exceed %g lb", unit.Msku, unit.Weight, maxSingleUnitBoxWeightPounds))
+			continue
+		}
+		oversized = append(oversized, packedBox{Units: []packingUnit{unit}, Volume: unit.volume(), Weight: unit.Weight})
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var best []packedBox
+	bestVolume := 0.0
+	for preferred := range cartons {
+		var boxes []packedBox
+		for _, unit := range packable {
+			index := slices.IndexFunc(boxes, func(box packedBox) bool { return box.accepts(unit, *box.Carton, fill) })
+			if index == -1 {
+				carton := &cartons[preferred]
+				if !(packedBox{}).accepts(unit, *carton, fill) {
+					for i := len(cartons) - 1; i >= 0; i-- {
+						if (packedBox{}).accepts(unit, cartons[i], fill) {
+							carton = &cartons[i]
+							break
+						}
+					}
+				}
+				boxes = append(boxes, packedBox{Carton: carton})
+				index = len(boxes) - 1
+			}
+			boxes[index].Units = append(boxes[index].Units, unit)
+			boxes[index].Volume += unit.volume()
+			boxes[index].Weight += unit.Weight
+		}
+		volume := 0.0
+		for i := range boxes {
+			boxes[i].Carton = smallestCarton(boxes[i], cartons, fill)
+			volume += boxes[i].Carton.volume()
+		}
+		if best == nil || len(boxes) < len(best) || (len(boxes) == len(best) && volume < bestVolume) {
+			best, bestVolume = boxes, volume
+		}
+	}
+	return append(best, oversized...), nil
+}
+
+// smallestCarton returns the smallest carton that holds every unit of the box within the fillable volume and the weight limit.
+func smallestCarton(box packedBox, cartons []packingCarton, fill float64) *packingCarton {
+	for i, carton := range cartons {
+		if box.Weight+carton.Weight > carton.MaxWeight || (len(box.Units) > 1 && box.Volume > carton.volume()*fill) {
+			continue
+		}
+		if !slices.ContainsFunc(box.Units, func(unit packingUnit) bool { return !unit.fits(carton) }) {
+			return &cartons[i]
+		}
+	}
+	return box.Carton
+}
+
+// boxContentFromPackedBoxes converts packed boxes to box content, merging boxes with the same carton and contents into a single box with a count.
+// Carton dimensions and weights are in the configured units of the carton, boxes packed alone use the dimensions of the unit in inches and pounds.
+func boxContentFromPackedBoxes(boxes []packedBox, packingGroupIds []string) []boxContentBox {
+	var content []boxContentBox
+	indexes := map[string]int{}
+	for i, box := range boxes {
+		quantities := map[string]int{}
+		for _, unit := range box.Units {
+			quantities[unit.Msku]++
+		}
+		var items []boxContentItem
+		for _, msku := range slices.Sorted(maps.Keys(quantities)) {
+			items = append(items, boxContentItem{Msku: msku, Quantity: quantities[msku]})
+		}
+		b := boxContentBox{PackingGroup: packingGroupIds[i], Count: 1, Items: items}
+		if box.Carton != nil {
+			pounds, _ := unitFactor(box.Carton.config.WeightUnit, string(fba_inbound.LB), string(fba_inbound.KG), poundsPerKilogram)
+			b.Length, b.Width, b.Height = box.Carton.config.Length, box.Carton.config.Width, box.Carton.config.Height
+			b.DimensionUnit, b.WeightUnit = box.Carton.config.DimensionUnit, box.Carton.config.WeightUnit
+			b.Weight = roundUp(box.grossWeight() / pounds)
+		} else {
+			unit := box.Units[0]
+			b.Length, b.Width, b.Height = roundUp(unit.Sides[0]), roundUp(unit.Sides[1]), roundUp(unit.Sides[2])
+			b.DimensionUnit, b.WeightUnit = string(fba_inbound.IN), string(fba_inbound.LB)
+			b.Weight = roundUp(box.grossWeight())
+		}
+		key := fmt.Sprintf("%s|%g|%g|%g|%s|%g|%v", b.PackingGroup, b.Length, b.Width, b.Height, b.DimensionUnit, b.Weight, b.Items)
+		if index, ok := indexes[key]; ok {
+			content[index].Count++
+			continue
+		}
+		indexes[key] = len(content)
+		b.ID = fmt.Sprintf("BOX-%d", len(content)+1)
+		content = append(content, b)
+	}
+	return content
+}
+
+// parseUnitDimensions parses MSKU=LxWxH:weight in --dimension-unit and --weight-unit into inches and pounds.
+func parseUnitDimensions(raw string) (string, itemDimensions, error) {
+	var dimensions itemDimensions
+	msku, value, ok := strings.Cut(raw, "=")
+	size, weight, hasWeight := strings.Cut(value, ":")
+	if !ok || msku == "" || !hasWeight {
+		return "", dimensions, errors.New("must be MSKU=LxWxH:weight")
+	}
+	sides, err := parseSides(size)
+	if err != nil {
+		return "", dimensions, err
+	}
+	dimensions.Weight, err = strconv.ParseFloat(weight, 64)
+	if err != nil || dimensions.Weight <= 0 {
+		return "", dimensions, fmt.Errorf("invalid weight %s", weight)
+	}
+	inches, err := unitFactor(strings.ToUpper(shipmentPackSuggestCfg.DimensionUnit), string(fba_inbound.IN), string(fba_inbound.CM), inchesPerCentimeter)
+	if err != nil {
+		return "", dimensions, err
+	}
+	pounds, err := unitFactor(strings.ToUpper(shipmentPackSuggestCfg.WeightUnit), string(fba_inbound.LB), string(fba_inbound.KG), poundsPerKilogram)
+	if err != nil {
+		return "", dimensions, err
+	}
+	dimensions.Length, dimensions.Width, dimensions.Height = sides[0]*inches, sides[1]*inches, sides[2]*inches
+	dimensions.Weight *= pounds
+	return msku, dimensions, nil
+}
+
+// parseSides parses LxWxH.
+func parseSides(raw string) ([3]float64, error) {
+	var sides [3]float64
+	parts := strings.Split(strings.ToLower(raw), "x")
+	if len(parts) != 3 {
+		return sides, fmt.Errorf("invalid size %s, must be LxWxH", raw)
+	}
+	for i, part := range parts {
+		side, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || side <= 0 {
+			return sides, fmt.Errorf("invalid size %s, must be LxWxH", raw)
+		}
+		sides[i] = side
+	}
+	return sides, nil
+}
+
+// unitFactor returns the factor converting unit to base, which is 1 for base itself and factor for other.
+func unitFactor(unit string, base string, other string, factor float64) (float64, error) {
+	switch unit {
+	case base:
+		return 1, nil
+	case other:
+		return factor, nil
+	}
+	return 0, fmt.Errorf("unknown unit %s, must be %s or %s", unit, base, other)
+}
+
+func sortedSides(length float64, width float64, height float64) [3]float64 {
+	sides := []float64{length, width, height}
+	slices.SortFunc(sides, func(a, b float64) int { return cmp.Compare(b, a) })
+	return [3]float64(sides)
+}
+
+// roundUp rounds up to one decimal, so declared weights and dimensions are never below the actual ones.
+func roundUp(value float64) float64 {
+	return math.Ceil(value*10-1e-9) / 10
+}
@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/config"
+)
+
+// testCarton returns a cube carton in inches and pounds.
+func testCarton(name string, side float64, weight float64) packingCarton {
+	return packingCarton{
+		Name:      name,
+		Sides:     [3]float64{side, side, side},
+		Weight:    weight,
+		MaxWeight: maxBoxWeightPounds,
+		config:    config.CartonConfig{Name: name, Length: side, Width: side, Height: side, DimensionUnit: "IN", Weight: weight, WeightUnit: "LB"},
+	}
+}
+
+func testUnits(msku string, quantity int, length float64, width float64, height float64, weight float64) []packingUnit {
+	return slices.Repeat([]packingUnit{{Msku: msku, Sides: sortedSides(length, width, height), Weight: weight}}, quantity)
+}
+
+// describeBoxes renders boxes as `carton:MSKU=quantity,...`, with `-` as the carton of units packed alone.
+func describeBoxes(boxes []packedBox) []string {
+	described := make([]string, 0, len(boxes))
+	for _, box := range boxes {
+		quantities := map[string]int{}
+		for _, unit := range box.Units {
+			quantities[unit.Msku]++
+		}
+		var items []string
+		for _, msku := range slices.Sorted(maps.Keys(quantities)) {
+			items = append(items, fmt.Sprintf("%s=%d", msku, quantities[msku]))
+		}
+		carton := "-"
+		if box.Carton != nil {
+			carton = box.Carton.Name
+		}
+		described = append(described, carton+":"+strings.Join(items, ","))
+	}
+	return described
+}
+
+func TestPackUnits(t *testing.T) {
+	cartons := []packingCarton{testCarton("small", 10, 1), testCarton("large", 20, 2)}
+	tests := []struct {
+		name    string
+		units   []packingUnit
+		fill    float64
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "units share the smallest carton",
+			units: testUnits("A", 10, 4, 4, 4, 1),
+			fill:  0.85,
+			want:  []string{"small:A=10"},
+		},
+		{
+			name:  "fill ratio prefers one larger carton over two smaller ones",
+			units: testUnits("A", 10, 4, 4, 4, 1),
+			fill:  0.5,
+			want:  []string{"large:A=10"},
+		},
+		{
+			name:  "weight limit splits boxes",
+			units: testUnits("A", 10, 2, 2, 2, 12),
+			fill:  0.85,
+			want:  []string{"small:A=4", "small:A=4", "small:A=2"},
+		},
+		{
+			name:  "units that do not fit the preferred carton use the largest one",
+			units: append(testUnits("A", 3, 4, 4, 4, 1), testUnits("B", 1, 15, 15, 15, 5)...),
+			fill:  0.85,
+			want:  []string{"large:A=3,B=1"},
+		},
+		{
+			name:  "mixed skus share a box",
+			units: append(testUnits("A", 12, 4, 4, 4, 1), testUnits("B", 2, 9, 9, 4, 2)...),
+			fill:  0.85,
+			// 1416 cubic inches would need two small cartons
+			want: []string{"large:A=12,B=2"},
+		},
+		{
+			name:  "oversized and overweight units are packed alone",
+			units: slices.Concat(testUnits("A", 2, 4, 4, 4, 1), testUnits("X", 1, 30, 5, 5, 20), testUnits("H", 1, 5, 5, 5, 60)),
+			fill:  0.85,
+			want:  []string{"small:A=2", "-:X=1", "-:H=1"},
+		},
+		{
+			name:    "unit over single unit box limit",
+			units:   testUnits("X", 1, 30, 5, 5, 120),
+			fill:    0.85,
+			wantErr: "a unit of X weighs 120.0 lb, boxes cannot exceed 100 lb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes, err := packUnits(tt.units, cartons, tt.fill)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("packUnits() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describeBoxes(boxes); !slices.Equal(got, tt.want) {
+				t.Errorf("packUnits() = %v, want %v", got, tt.want)
+			}
+			for _, box := range boxes {
+				if box.Carton != nil && (box.grossWeight() > box.Carton.MaxWeight || (len(box.Units) > 1 && box.Volume > box.Carton.volume()*tt.fill)) {
+					t.Errorf("box %v exceeds carton %s", describeBoxes([]packedBox{box}), box.Carton.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestSmallestCarton(t *testing.T) {
+	// the large carton is lighter, so a box can be too heavy for the small one only
+	cartons := []packingCarton{testCarton("small", 10, 1), testCarton("large", 20, 0.5)}
+	box := func(weight float64, units ...packingUnit) packedBox {
+		b := packedBox{Carton: &cartons[1], Units: units, Weight: weight}
+		for _, unit := range units {
+			b.Volume += unit.volume()
+		}
+		return b
+	}
+	unit := packingUnit{Msku: "A", Sides: [3]float64{9, 9, 9}}
+	half := packingUnit{Msku: "A", Sides: [3]float64{9, 9, 4.5}}
+	tests := []struct {
+		name string
+		box  packedBox
+		want string
+	}{
+		{name: "single unit is not limited by fill", box: box(1, unit), want: "small"},
+		{name: "units are limited by fill", box: box(1, half, half), want: "large"},
+		{name: "weight of carton counts", box: box(49.5, half), want: "large"},
+		{name: "keeps carton if no carton holds the box", box: packedBox{Carton: &cartons[0], Units: []packingUnit{half}, Volume: half.volume(), Weight: 49.8}, want: "small"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := smallestCarton(tt.box, cartons, 0.5); got.Name != tt.want {
+				t.Errorf("smallestCarton() = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoxContentFromPackedBoxes(t *testing.T) {
+	metric := packingCarton{
+		Name:      "metric",
+		Sides:     sortedSides(40*inchesPerCentimeter, 30*inchesPerCentimeter, 20*inchesPerCentimeter),
+		Weight:    0.5 * poundsPerKilogram,
+		MaxWeight: maxBoxWeightPounds,
+		config:    config.CartonConfig{Name: "metric", Length: 40, Width: 30, Height: 20, DimensionUnit: "CM", Weight: 0.5, WeightUnit: "KG"},
+	}
+	imperial := testCarton("imperial", 12, 1)
+	a := packingUnit{Msku: "A", Sides: [3]float64{4, 4, 4}, Weight: 2}
+	b := packingUnit{Msku: "B", Sides: [3]float64{4, 4, 4}, Weight: 1.5}
+	oversized := packingUnit{Msku: "X", Sides: [3]float64{30.02, 5, 5}, Weight: 20.01}
+
+	boxes := []packedBox{
+		{Carton: &metric, Units: []packingUnit{a, a}, Weight: 4},
+		{Carton: &metric, Units: []packingUnit{a, a}, Weight: 4},
+		{Carton: &metric, Units: []packingUnit{a, a}, Weight: 4},
+		{Carton: &metric, Units: []packingUnit{b, a}, Weight: 3.5},
+		{Carton: &imperial, Units: []packingUnit{a, a}, Weight: 4},
+		{Units: []packingUnit{oversized}, Weight: 20.01},
+		{Units: []packingUnit{oversized}, Weight: 20.01},
+	}
+	groups := []string{"G1", "G1", "G2", "G1", "G1", "G1", "G1"}
+	want := []boxContentBox{
+		// (4 lb + 0.5 kg) / 2.20462 rounded up
+		{ID: "BOX-1", PackingGroup: "G1", Count: 2, Length: 40, Width: 30, Height: 20, DimensionUnit: "CM", Weight: 2.4, WeightUnit: "KG", Items: []boxContentItem{{Msku: "A", Quantity: 2}}},
+		{ID: "BOX-2", PackingGroup: "G2", Count: 1, Length: 40, Width: 30, Height: 20, DimensionUnit: "CM", Weight: 2.4, WeightUnit: "KG", Items: []boxContentItem{{Msku: "A", Quantity: 2}}},
+		{ID: "BOX-3", PackingGroup: "G1", Count: 1, Length: 40, Width: 30, Height: 20, DimensionUnit: "CM", Weight: 2.1, WeightUnit: "KG", Items: []boxContentItem{{Msku: "A", Quantity: 1}, {Msku: "B", Quantity: 1}}},
+		{ID: "BOX-4", PackingGroup: "G1", Count: 1, Length: 12, Width: 12, Height: 12, DimensionUnit: "IN", Weight: 5, WeightUnit: "LB", Items: []boxContentItem{{Msku: "A", Quantity: 2}}},
+		{ID: "BOX-5", PackingGroup: "G1", Count: 2, Length: 30.1, Width: 5, Height: 5, DimensionUnit: "IN", Weight: 20.1, WeightUnit: "LB", Items: []boxContentItem{{Msku: "X", Quantity: 1}}},
+	}
+	if got := boxContentFromPackedBoxes(boxes, groups); !reflect.DeepEqual(got, want) {
+		t.Errorf("boxContentFromPackedBoxes() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestPackPackingGroups(t *testing.T) {
+	cartons := []packingCarton{testCarton("small", 10, 1), testCarton("large", 20, 2)}
+	groups := packingGroups{
+		IDs: []string{"G1", "G2"},
+		Items: map[string][]fba_inbound.Item{
+			"G1": {{Msku: "A", Asin: "B0A", Quantity: 2}, {Msku: "B", Asin: "B0B", Quantity: 3}},
+			"G2": {{Msku: "C", Asin: "B0C", Quantity: 1}},
+		},
+	}
+	dimensions := map[string]itemDimensions{
+		"B0A": {Length: 4, Width: 4, Height: 4, Weight: 1},
+		"B0B": {Length: 2, Width: 3, Height: 4, Weight: 1},
+		"B0C": {Length: 4, Width: 4, Height: 4, Weight: 1},
+	}
+	tests := []struct {
+		name       string
+		singleSku  bool
+		want       []string
+		wantGroups []string
+	}{
+		{
+			name:       "skus of a packing group share boxes",
+			want:       []string{"small:A=2,B=3", "small:C=1"},
+			wantGroups: []string{"G1", "G2"},
+		},
+		{
+			name:       "single sku packs every sku separately",
+			singleSku:  true,
+			want:       []string{"small:A=2", "small:B=3", "small:C=1"},
+			wantGroups: []string{"G1", "G1", "G2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes, boxGroups, err := packPackingGroups(groups, dimensions, cartons, 0.85, tt.singleSku)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describeBoxes(boxes); !slices.Equal(got, tt.want) {
+				t.Errorf("packPackingGroups() = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(boxGroups, tt.wantGroups) {
+				t.Errorf("packPackingGroups() groups = %v, want %v", boxGroups, tt.wantGroups)
+			}
+		})
+	}
+
+	t.Run("errors name the packing group", func(t *testing.T) {
+		heavy := map[string]itemDimensions{"B0A": {Length: 30, Width: 5, Height: 5, Weight: 120}, "B0B": dimensions["B0B"], "B0C": dimensions["B0C"]}
+		_, _, err := packPackingGroups(groups, heavy, cartons, 0.85, false)
+		if err == nil || !strings.HasPrefix(err.Error(), "packing group G1: a unit of A weighs 120.0 lb") {
+			t.Errorf("packPackingGroups() error = %v", err)
+		}
+	})
+}
+
+// setPackSuggestConfig replaces the flags and configured cartons for the duration of the test.
+func setPackSuggestConfig(t *testing.T, flags shipmentPackSuggestConfig, cartons []config.CartonConfig) {
+	t.Helper()
+	previousFlags, previousCartons := shipmentPackSuggestCfg, cfg.Amazon.FBA.Cartons
+	t.Cleanup(func() {
+		shipmentPackSuggestCfg, cfg.Amazon.FBA.Cartons = previousFlags, previousCartons
+	})
+	shipmentPackSuggestCfg, cfg.Amazon.FBA.Cartons = flags, cartons
+}
+
+func TestPackingCartons(t *testing.T) {
+	type carton struct {
+		Name      string
+		Sides     [3]float64
+		Weight    float64
+		MaxWeight float64
+	}
+	tests := []struct {
+		name    string
+		flags   shipmentPackSuggestConfig
+		cartons []config.CartonConfig
+		want    []carton
+		wantErr string
+	}{
+		{
+			name:  "flags in centimeters and kilograms",
+			flags: shipmentPackSuggestConfig{Cartons: []string{"60x40x40", "small=30X20x10"}, DimensionUnit: "cm", WeightUnit: "kg", MaxWeight: 20},
+			// flags override the configured cartons
+			cartons: []config.CartonConfig{{Name: "ignored", Length: 10, Width: 10, Height: 10}},
+			want: []carton{
+				{Name: "small", Sides: [3]float64{30 * inchesPerCentimeter, 20 * inchesPerCentimeter, 10 * inchesPerCentimeter}, MaxWeight: 20 * poundsPerKilogram},
+				{Name: "60x40x40 CM", Sides: [3]float64{60 * inchesPerCentimeter, 40 * inchesPerCentimeter, 40 * inchesPerCentimeter}, MaxWeight: 20 * poundsPerKilogram},
+			},
+		},
+		{
+			name: "configured cartons default to inches, pounds and 50 lb",
+			cartons: []config.CartonConfig{
+				{Name: "big", Length: 18, Width: 24, Height: 18, Weight: 2},
+				{Length: 12, Width: 10, Height: 8, DimensionUnit: "in", Weight: 0.5, MaxWeight: 20, WeightUnit: "kg"},
+			},
+			want: []carton{
+				{Name: "12x10x8 IN", Sides: [3]float64{12, 10, 8}, Weight: 0.5 * poundsPerKilogram, MaxWeight: 20 * poundsPerKilogram},
+				{Name: "big", Sides: [3]float64{24, 18, 18}, Weight: 2, MaxWeight: 50},
+			},
+		},
+		{
+			name:    "no cartons",
+			wantErr: "no cartons, pass them with --carton or add them to fba.cartons in config",
+		},
+		{
+			name:    "invalid size",
+			flags:   shipmentPackSuggestConfig{Cartons: []string{"bad=10x10"}, DimensionUnit: "IN", WeightUnit: "LB"},
+			wantErr: "carton bad=10x10: invalid size 10x10, must be LxWxH",
+		},
+		{
+			name:    "unknown unit",
+			cartons: []config.CartonConfig{{Name: "box", Length: 10, Width: 10, Height: 10, DimensionUnit: "MM"}},
+			wantErr: "carton box: unknown unit MM, must be IN or CM",
+		},
+		{
+			name:    "side over limit",
+			cartons: []config.CartonConfig{{Name: "box", Length: 70, Width: 10, Height: 10, DimensionUnit: "CM"}},
+			wantErr: "carton box has a side of 27.6 in, boxes with multiple units cannot exceed 25 in",
+		},
+		{
+			name:    "max weight over limit",
+			cartons: []config.CartonConfig{{Name: "box", Length: 10, Width: 10, Height: 10, MaxWeight: 30, WeightUnit: "KG"}},
+			wantErr: "carton box has a max weight of 66.1 lb, boxes with multiple units cannot exceed 50 lb",
+		},
+		{
+			name:    "carton heavier than max weight",
+			cartons: []config.CartonConfig{{Name: "box", Length: 10, Width: 10, Height: 10, Weight: 10, MaxWeight: 10}},
+			wantErr: "carton box weighs more than its max weight",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setPackSuggestConfig(t, tt.flags, tt.cartons)
+			cartons, err := packingCartons()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("packingCartons() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]carton, 0, len(cartons))
+			for _, c := range cartons {
+				got = append(got, carton{Name: c.Name, Sides: c.Sides, Weight: c.Weight, MaxWeight: c.MaxWeight})
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("packingCartons() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnitDimensions(t *testing.T) {
+	tests := []struct {
+		raw           string
+		dimensionUnit string
+		weightUnit    string
+		wantMsku      string
+		want          itemDimensions
+		wantErr       string
+	}{
+		{raw: "SKU-1=10x20x5:2", dimensionUnit: "IN", weightUnit: "LB", wantMsku: "SKU-1", want: itemDimensions{Length: 10, Width: 20, Height: 5, Weight: 2}},
+		{raw: "SKU-1=25.4x2.54x5.08:2", dimensionUnit: "cm", weightUnit: "kg", wantMsku: "SKU-1", want: itemDimensions{Length: 25.4 * inchesPerCentimeter, Width: 2.54 * inchesPerCentimeter, Height: 5.08 * inchesPerCentimeter, Weight: 2 * poundsPerKilogram}},
+		{raw: "SKU-1=10x20x5", dimensionUnit: "IN", weightUnit: "LB", wantErr: "must be MSKU=LxWxH:weight"},
+		{raw: "=10x20x5:2", dimensionUnit: "IN", weightUnit: "LB", wantErr: "must be MSKU=LxWxH:weight"},
+		{raw: "SKU-1=10x0x5:2", dimensionUnit: "IN", weightUnit: "LB", wantErr: "invalid size 10x0x5, must be LxWxH"},
+		{raw: "SKU-1=10x20x5:0", dimensionUnit: "IN", weightUnit: "LB", wantErr: "invalid weight 0"},
+		{raw: "SKU-1=10x20x5:2", dimensionUnit: "MM", weightUnit: "LB", wantErr: "unknown unit MM, must be IN or CM"},
+		{raw: "SKU-1=10x20x5:2", dimensionUnit: "IN", weightUnit: "G", wantErr: "unknown unit G, must be LB or KG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw+" "+tt.dimensionUnit+" "+tt.weightUnit, func(t *testing.T) {
+			setPackSuggestConfig(t, shipmentPackSuggestConfig{DimensionUnit: tt.dimensionUnit, WeightUnit: tt.weightUnit}, nil)
+			msku, dimensions, err := parseUnitDimensions(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseUnitDimensions() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msku != tt.wantMsku || dimensions != tt.want {
+				t.Errorf("parseUnitDimensions() = %s %+v, want %s %+v", msku, dimensions, tt.wantMsku, tt.want)
+			}
+		})
+	}
+}
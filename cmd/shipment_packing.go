@@ -0,0 +1,604 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/db"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Amazon's standard box limits, boxes containing a single oversized unit are exempt from the side and standard weight limits.
+const (
+	maxBoxSideInches             = 25.0
+	maxBoxWeightPounds           = 50.0
+	maxSingleUnitBoxWeightPounds = 100.0
+	inchesPerCentimeter          = 1 / 2.54
+	poundsPerKilogram            = 2.20462
+)
+
+// boxContent is the box content input of `shipment workflow --packing-info`, read from yaml or csv files.
+// Boxes list the quantity of every SKU in each box, cases describe case-packed SKUs with the units per case and the number of cases.
+type boxContent struct {
+	// DimensionUnit and WeightUnit are the defaults of boxes and cases that do not set their own units, IN and LB if empty.
+	DimensionUnit string           `yaml:"dimension_unit,omitempty"`
+	WeightUnit    string           `yaml:"weight_unit,omitempty"`
+	Boxes         []boxContentBox  `yaml:"boxes,omitempty"`
+	Cases         []boxContentCase `yaml:"cases,omitempty"`
+}
+
+type boxContentBox struct {
+	ID string `yaml:"id,omitempty"`
+	// PackingGroup is resolved from the SKUs of the box if empty.
+	PackingGroup string `yaml:"packing_group,omitempty"`
+	// Count is the number of identical boxes, 1 if empty.
+	Count         int              `yaml:"count,omitempty"`
+	Length        float64          `yaml:"length,omitempty"`
+	Width         float64          `yaml:"width,omitempty"`
+	Height        float64          `yaml:"height,omitempty"`
+	DimensionUnit string           `yaml:"dimension_unit,omitempty"`
+	Weight        float64          `yaml:"weight,omitempty"`
+	WeightUnit    string           `yaml:"weight_unit,omitempty"`
+	Items         []boxContentItem `yaml:"items,omitempty"`
+}
+
+type boxContentItem struct {
+	Msku     string `yaml:"msku,omitempty"`
+	Quantity int    `yaml:"quantity,omitempty"`
+}
+
+type boxContentCase struct {
+	Msku          string  `yaml:"msku,omitempty"`
+	PackingGroup  string  `yaml:"packing_group,omitempty"`
+	UnitsPerCase  int     `yaml:"units_per_case,omitempty"`
+	Cases         int     `yaml:"cases,omitempty"`
+	Length        float64 `yaml:"length,omitempty"`
+	Width         float64 `yaml:"width,omitempty"`
+	Height        float64 `yaml:"height,omitempty"`
+	DimensionUnit string  `yaml:"dimension_unit,omitempty"`
+	Weight        float64 `yaml:"weight,omitempty"`
+	WeightUnit    string  `yaml:"weight_unit,omitempty"`
+}
+
+// packingGroups are the packing groups of the confirmed packing option with their items, in the order Amazon lists them.
+type packingGroups struct {
+	IDs   []string
+	Items map[string][]fba_inbound.Item
+}
+
+func listConfirmedPackingGroups(app AppCtx, plan db.InboundPlan) (packingGroups, error) {
+	groups := packingGroups{Items: map[string][]fba_inbound.Item{}}
+	options, err := listPackingOptions(app, plan.InboundPlanID)
+	if err != nil {
+		return groups, fmt.Errorf("failed to list packing options: %w", err)
+	}
+	index := slices.IndexFunc(options, func(option fba_inbound.PackingOption) bool {
+		return option.PackingOptionId == plan.PackingOptionID.String
+	})
+	if index == -1 {
+		return groups, fmt.Errorf("confirmed packing option %s not found", plan.PackingOptionID.String)
+	}
+	for _, packingGroupId := range options[index].PackingGroups {
+		items, err := listPackingGroupItems(app, plan.InboundPlanID, packingGroupId)
+		if err != nil {
+			return groups, fmt.Errorf("failed to list items of packing group %s: %w", packingGroupId, err)
+		}
+		groups.IDs = append(groups.IDs, packingGroupId)
+		groups.Items[packingGroupId] = items
+	}
+	return groups, nil
+}
+
+// itemInputFromItem converts a planned item back to an input, keeping prep and label owners assigned while creating the plan.
+func itemInputFromItem(item fba_inbound.Item) fba_inbound.ItemInput {
+	prepOwner := fba_inbound.NONE
+	for _, instruction := range item.PrepInstructions {
+		if instruction.PrepOwner != nil && *instruction.PrepOwner != string(fba_inbound.NONE) {
+			prepOwner = fba_inbound.PrepOwner(*instruction.PrepOwner)
+		}
+	}
+	return fba_inbound.ItemInput{
+		Msku:                 item.Msku,
+		Quantity:             item.Quantity,
+		PrepOwner:            prepOwner,
+		LabelOwner:           fba_inbound.LabelOwner(item.LabelOwner),
+		Expiration:           item.Expiration,
+		ManufacturingLotCode: item.ManufacturingLotCode,
+	}
+}
+
+// writeBoxContentTemplate writes a box content csv that puts every item of a packing group into a single box with empty dimensions and weight.
+func writeBoxContentTemplate(path string, groups packingGroups) error {
+	content := boxContent{DimensionUnit: string(fba_inbound.IN), WeightUnit: string(fba_inbound.LB)}
+	for i, packingGroupId := range groups.IDs {
+		box := boxContentBox{ID: fmt.Sprintf("BOX-%d", i+1), PackingGroup: packingGroupId, Count: 1}
+		for _, item := range groups.Items[packingGroupId] {
+			box.Items = append(box.Items, boxContentItem{Msku: item.Msku, Quantity: item.Quantity})
+		}
+		content.Boxes = append(content.Boxes, box)
+	}
+	return writeBoxContent(path, content)
+}
+
+// writeBoxContent writes box content as yaml, or as csv with one row per SKU of every box.
+func writeBoxContent(path string, content boxContent) error {
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var err error
+		if data, err = yaml.Marshal(content); err != nil {
+			return fmt.Errorf("failed to marshal box content: %w", err)
+		}
+	case ".csv":
+		var buffer bytes.Buffer
+		if err := writeBoxContentCSV(&buffer, content); err != nil {
+			return fmt.Errorf("failed to write box content: %w", err)
+		}
+		data = buffer.Bytes()
+	default:
+		return fmt.Errorf("unknown box content format %s, must be yaml or csv", filepath.Ext(path))
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write box content: %w", err)
+	}
+	return nil
+}
+
+func writeBoxContentCSV(w io.Writer, content boxContent) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"box", "packing_group", "msku", "quantity", "count", "length", "width", "height", "dimension_unit", "weight", "weight_unit"}); err != nil {
+		return err
+	}
+	number := func(value float64) string {
+		if value == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	for _, box := range content.Boxes {
+		for _, item := range box.Items {
+			record := []string{
+				box.ID, box.PackingGroup, item.Msku, strconv.Itoa(item.Quantity), strconv.Itoa(max(box.Count, 1)),
+				number(box.Length), number(box.Width), number(box.Height), cmp.Or(box.DimensionUnit, content.DimensionUnit),
+				number(box.Weight), cmp.Or(box.WeightUnit, content.WeightUnit),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// readPackingInformation reads a setPackingInformation request from json, or builds it from a yaml or csv box content file.
+func readPackingInformation(path string, groups packingGroups) (fba_inbound.SetPackingInformationRequest, error) {
+	var request fba_inbound.SetPackingInformationRequest
+	data, err := internal.ReadFile(path)
+	if err != nil {
+		return request, err
+	}
+	var content boxContent
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.Unmarshal(data, &request); err != nil {
+			return request, fmt.Errorf("failed to parse packing information: %w", err)
+		}
+		return request, nil
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &content); err != nil {
+			return request, fmt.Errorf("failed to parse box content: %w", err)
+		}
+	case ".csv":
+		content, err = readBoxContentCSV(data)
+		if err != nil {
+			return request, fmt.Errorf("failed to parse box content: %w", err)
+		}
+	default:
+		return request, fmt.Errorf("unknown packing information format %s, must be json, yaml or csv", filepath.Ext(path))
+	}
+	return buildPackingInformation(content, groups)
+}
+
+// readBoxContentCSV reads a box content csv. Files with units_per_case or cases columns are case-packed, one row per SKU.
+// Otherwise every row is a SKU in a box, box dimensions and weight can be given in any row of the box.
+func readBoxContentCSV(data []byte) (boxContent, error) {
+	var content boxContent
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return content, err
+	}
+	if len(records) < 2 {
+		return content, errors.New("csv has no rows")
+	}
+	columns := map[string]int{}
+	aliases := map[string]string{
+		"sku":              "msku",
+		"seller_sku":       "msku",
+		"qty":              "quantity",
+		"units":            "quantity",
+		"box_id":           "box",
+		"box_name":         "box",
+		"packing_group_id": "packing_group",
+		"boxes":            "count",
+		"box_count":        "count",
+		"case_quantity":    "units_per_case",
+		"case_qty":         "units_per_case",
+		"units_per_box":    "units_per_case",
+		"case_count":       "cases",
+		"number_of_cases":  "cases",
+		"unit":             "dimension_unit",
+		"dimensions_unit":  "dimension_unit",
+	}
+	for i, name := range records[0] {
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		columns[name] = i
+	}
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	number := func(record []string, column string, line int) (float64, error) {
+		raw := value(record, column)
+		if raw == "" {
+			return 0, nil
+		}
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: invalid %s %s", line, column, raw)
+		}
+		return parsed, nil
+	}
+	integer := func(record []string, column string, line int) (int, error) {
+		raw := value(record, column)
+		if raw == "" {
+			return 0, nil
+		}
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: invalid %s %s", line, column, raw)
+		}
+		return parsed, nil
+	}
+	if _, ok := columns["msku"]; !ok {
+		return content, errors.New("csv header must have a msku column")
+	}
+
+	_, hasUnitsPerCase := columns["units_per_case"]
+	_, hasCases := columns["cases"]
+	var errs []error
+	if hasUnitsPerCase || hasCases {
+		for i, record := range records[1:] {
+			line := i + 2
+			c := boxContentCase{Msku: value(record, "msku"), PackingGroup: value(record, "packing_group"), DimensionUnit: value(record, "dimension_unit"), WeightUnit: value(record, "weight_unit")}
+			if c.Msku == "" {
+				continue
+			}
+			var err error
+			if c.UnitsPerCase, err = integer(record, "units_per_case", line); err != nil {
+				errs = append(errs, err)
+			}
+			if c.Cases, err = integer(record, "cases", line); err != nil {
+				errs = append(errs, err)
+			}
+			for column, target := range map[string]*float64{"length": &c.Length, "width": &c.Width, "height": &c.Height, "weight": &c.Weight} {
+				if *target, err = number(record, column, line); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			content.Cases = append(content.Cases, c)
+		}
+		return content, errors.Join(errs...)
+	}
+
+	if _, ok := columns["box"]; !ok {
+		return content, errors.New("csv header must have a box column, or units_per_case and cases columns for case-packed input")
+	}
+	boxes := map[string]int{}
+	for i, record := range records[1:] {
+		line := i + 2
+		id := value(record, "box")
+		msku := value(record, "msku")
+		if id == "" && msku == "" {
+			continue
+		}
+		if id == "" {
+			errs = append(errs, fmt.Errorf("line %d: box is empty", line))
+			continue
+		}
+		index, exists := boxes[id]
+		if !exists {
+			index = len(content.Boxes)
+			boxes[id] = index
+			content.Boxes = append(content.Boxes, boxContentBox{ID: id})
+		}
+		box := &content.Boxes[index]
+		for column, target := range map[string]*string{"packing_group": &box.PackingGroup, "dimension_unit": &box.DimensionUnit, "weight_unit": &box.WeightUnit} {
+			if raw := value(record, column); raw != "" {
+				if *target != "" && *target != raw {
+					errs = append(errs, fmt.Errorf("line %d: box %s has conflicting %s %s and %s", line, id, column, *target, raw))
+				}
+				*target = raw
+			}
+		}
+		for column, target := range map[string]*float64{"length": &box.Length, "width": &box.Width, "height": &box.Height, "weight": &box.Weight} {
+			parsed, err := number(record, column, line)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if parsed != 0 {
+				if *target != 0 && *target != parsed {
+					errs = append(errs, fmt.Errorf("line %d: box %s has conflicting %s %g and %g", line, id, column, *target, parsed))
+				}
+				*target = parsed
+			}
+		}
+		count, err := integer(record, "count", line)
+		if err != nil {
+			errs = append(errs, err)
+		} else if count != 0 {
+			if box.Count != 0 && box.Count != count {
+				errs = append(errs, fmt.Errorf("line %d: box %s has conflicting count %d and %d", line, id, box.Count, count))
+			}
+			box.Count = count
+		}
+		if msku != "" {
+			quantity, err := integer(record, "quantity", line)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			box.Items = append(box.Items, boxContentItem{Msku: msku, Quantity: quantity})
+		}
+	}
+	return content, errors.Join(errs...)
+}
+
+// buildPackingInformation converts box content into a setPackingInformation request, resolving packing groups of boxes from their SKUs.
+func buildPackingInformation(content boxContent, groups packingGroups) (fba_inbound.SetPackingInformationRequest, error) {
+	var request fba_inbound.SetPackingInformationRequest
+	var errs []error
+
+	planned := map[string]map[string]fba_inbound.Item{}
+	mskuGroups := map[string][]string{}
+	for _, packingGroupId := range groups.IDs {
+		planned[packingGroupId] = map[string]fba_inbound.Item{}
+		for _, item := range groups.Items[packingGroupId] {
+			planned[packingGroupId][item.Msku] = item
+			mskuGroups[item.Msku] = append(mskuGroups[item.Msku], packingGroupId)
+		}
+	}
+	resolveGroup := func(name string, explicit string, mskus []string) (string, error) {
+		if explicit != "" {
+			if _, ok := planned[explicit]; !ok {
+				return "", fmt.Errorf("%s: packing group %s is not in the confirmed packing option", name, explicit)
+			}
+			return explicit, nil
+		}
+		var resolved string
+		for _, msku := range mskus {
+			candidates := mskuGroups[msku]
+			switch {
+			case len(candidates) == 0:
+				return "", fmt.Errorf("%s: %s is not in the inbound plan", name, msku)
+			case len(candidates) > 1:
+				return "", fmt.Errorf("%s: %s is in multiple packing groups, set packing_group", name, msku)
+			case resolved != "" && resolved != candidates[0]:
+				return "", fmt.Errorf("%s: SKUs belong to different packing groups, boxes can only contain SKUs of a single packing group", name)
+			}
+			resolved = candidates[0]
+		}
+		if resolved == "" {
+			return "", fmt.Errorf("%s: box has no items", name)
+		}
+		return resolved, nil
+	}
+	units := func(name string, dimensionUnit string, weightUnit string) (fba_inbound.UnitOfMeasurement, fba_inbound.UnitOfWeight, error) {
+		dimensionUnit = strings.ToUpper(cmp.Or(dimensionUnit, content.DimensionUnit, string(fba_inbound.IN)))
+		weightUnit = strings.ToUpper(cmp.Or(weightUnit, content.WeightUnit, string(fba_inbound.LB)))
+		if dimensionUnit != string(fba_inbound.IN) && dimensionUnit != string(fba_inbound.CM) {
+			return "", "", fmt.Errorf("%s: invalid dimension unit %s, must be IN or CM", name, dimensionUnit)
+		}
+		if weightUnit != string(fba_inbound.LB) && weightUnit != string(fba_inbound.KG) {
+			return "", "", fmt.Errorf("%s: invalid weight unit %s, must be LB or KG", name, weightUnit)
+		}
+		return fba_inbound.UnitOfMeasurement(dimensionUnit), fba_inbound.UnitOfWeight(weightUnit), nil
+	}
+
+	boxesByGroup := map[string][]fba_inbound.BoxInput{}
+	for i, box := range content.Boxes {
+		name := fmt.Sprintf("box %d", i+1)
+		if box.ID != "" {
+			name = "box " + box.ID
+		}
+		mskus := make([]string, 0, len(box.Items))
+		for _, item := range box.Items {
+			mskus = append(mskus, item.Msku)
+		}
+		packingGroupId, err := resolveGroup(name, box.PackingGroup, mskus)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		dimensionUnit, weightUnit, err := units(name, box.DimensionUnit, box.WeightUnit)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		items := make([]fba_inbound.ItemInput, 0, len(box.Items))
+		for _, item := range box.Items {
+			plannedItem, ok := planned[packingGroupId][item.Msku]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: %s is not in packing group %s", name, item.Msku, packingGroupId))
+				continue
+			}
+			input := itemInputFromItem(plannedItem)
+			input.Quantity = item.Quantity
+			items = append(items, input)
+		}
+		count := box.Count
+		if count == 0 {
+			count = 1
+		}
+		boxesByGroup[packingGroupId] = append(boxesByGroup[packingGroupId], fba_inbound.BoxInput{
+			ContentInformationSource: fba_inbound.BOXCONTENTPROVIDED,
+			Dimensions:               fba_inbound.Dimensions{Length: float32(box.Length), Width: float32(box.Width), Height: float32(box.Height), UnitOfMeasurement: dimensionUnit},
+			Weight:                   fba_inbound.Weight{Value: float32(box.Weight), Unit: weightUnit},
+			Quantity:                 count,
+			Items:                    &items,
+		})
+	}
+	for i, c := range content.Cases {
+		name := fmt.Sprintf("case %d (%s)", i+1, c.Msku)
+		packingGroupId, err := resolveGroup(name, c.PackingGroup, []string{c.Msku})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		dimensionUnit, weightUnit, err := units(name, c.DimensionUnit, c.WeightUnit)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		plannedItem, ok := planned[packingGroupId][c.Msku]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s is not in packing group %s", name, c.Msku, packingGroupId))
+			continue
+		}
+		input := itemInputFromItem(plannedItem)
+		input.Quantity = c.UnitsPerCase
+		boxesByGroup[packingGroupId] = append(boxesByGroup[packingGroupId], fba_inbound.BoxInput{
+			ContentInformationSource: fba_inbound.BOXCONTENTPROVIDED,
+			Dimensions:               fba_inbound.Dimensions{Length: float32(c.Length), Width: float32(c.Width), Height: float32(c.Height), UnitOfMeasurement: dimensionUnit},
+			Weight:                   fba_inbound.Weight{Value: float32(c.Weight), Unit: weightUnit},
+			Quantity:                 c.Cases,
+			Items:                    &[]fba_inbound.ItemInput{input},
+		})
+	}
+	for _, packingGroupId := range groups.IDs {
+		if boxes, ok := boxesByGroup[packingGroupId]; ok {
+			request.PackageGroupings = append(request.PackageGroupings, fba_inbound.PackageGroupingInput{
+				PackingGroupId: internal.Ptr(packingGroupId),
+				Boxes:          boxes,
+			})
+		}
+	}
+	return request, errors.Join(errs...)
+}
+
+// validatePackingInformation checks box dimensions, weights and quantities against Amazon's limits, and packed quantities
+// of every SKU against the planned quantities of its packing group. Every problem is reported at once.
+func validatePackingInformation(request fba_inbound.SetPackingInformationRequest, groups packingGroups) error {
+	if len(request.PackageGroupings) == 0 {
+		return errors.New("no package groupings")
+	}
+	var errs []error
+	packed := map[string]map[string]int{}
+	// units of groupings by shipment can not be matched to packing groups
+	byShipment := false
+	for i, grouping := range request.PackageGroupings {
+		if grouping.PackingGroupId == nil && grouping.ShipmentId == nil {
+			errs = append(errs, fmt.Errorf("package grouping %d has neither packingGroupId nor shipmentId", i+1))
+		}
+		if grouping.PackingGroupId == nil && grouping.ShipmentId != nil {
+			byShipment = true
+		}
+		if grouping.PackingGroupId != nil {
+			if _, ok := groups.Items[*grouping.PackingGroupId]; !ok {
+				errs = append(errs, fmt.Errorf("package grouping %d: packing group %s is not in the confirmed packing option", i+1, *grouping.PackingGroupId))
+			} else if packed[*grouping.PackingGroupId] == nil {
+				packed[*grouping.PackingGroupId] = map[string]int{}
+			}
+		}
+		for j, box := range grouping.Boxes {
+			name := fmt.Sprintf("box %d of package grouping %d", j+1, i+1)
+			if box.Dimensions.Length <= 0 || box.Dimensions.Width <= 0 || box.Dimensions.Height <= 0 {
+				errs = append(errs, fmt.Errorf("%s has no dimensions", name))
+			}
+			if box.Weight.Value <= 0 {
+				errs = append(errs, fmt.Errorf("%s has no weight", name))
+			}
+			if box.Quantity < 1 {
+				errs = append(errs, fmt.Errorf("%s has quantity below 1", name))
+			}
+			var units int
+			if box.Items != nil {
+				for _, item := range *box.Items {
+					if item.Quantity < 1 {
+						errs = append(errs, fmt.Errorf("%s has %s with quantity below 1", name, item.Msku))
+					}
+					units += item.Quantity
+					if grouping.PackingGroupId != nil && packed[*grouping.PackingGroupId] != nil {
+						packed[*grouping.PackingGroupId][item.Msku] += item.Quantity * box.Quantity
+					}
+				}
+			}
+			if err := validateBoxLimits(name, box, units); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for _, packingGroupId := range groups.IDs {
+		quantities, ok := packed[packingGroupId]
+		if !ok {
+			if byShipment {
+				continue
+			}
+			// every planned unit of a packing group left out of the file is missing
+			quantities = map[string]int{}
+		}
+		for _, item := range groups.Items[packingGroupId] {
+			if quantities[item.Msku] != item.Quantity {
+				errs = append(errs, fmt.Errorf("packing group %s: %s has %d units in boxes, %d planned", packingGroupId, item.Msku, quantities[item.Msku], item.Quantity))
+			}
+			delete(quantities, item.Msku)
+		}
+		for msku, quantity := range quantities {
+			errs = append(errs, fmt.Errorf("packing group %s: %s has %d units in boxes, but is not planned", packingGroupId, msku, quantity))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateBoxLimits checks a box against Amazon's standard size and weight limits, boxes with a single unit may exceed the standard limits.
+func validateBoxLimits(name string, box fba_inbound.BoxInput, units int) error {
+	sideFactor := 1.0
+	if box.Dimensions.UnitOfMeasurement == fba_inbound.CM {
+		sideFactor = inchesPerCentimeter
+	}
+	weightFactor := 1.0
+	if box.Weight.Unit == fba_inbound.KG {
+		weightFactor = poundsPerKilogram
+	}
+	var errs []error
+	longest := float64(max(box.Dimensions.Length, box.Dimensions.Width, box.Dimensions.Height)) * sideFactor
+	if longest > maxBoxSideInches && units > 1 {
+		errs = append(errs, fmt.Errorf("%s has a side of %.1f in, boxes with multiple units cannot exceed %g in", name, longest, maxBoxSideInches))
+	}
+	weight := float64(box.Weight.Value) * weightFactor
+	switch {
+	case weight > maxSingleUnitBoxWeightPounds:
+		errs = append(errs, fmt.Errorf("%s weighs %.1f lb, boxes cannot exceed %g lb", name, weight, maxSingleUnitBoxWeightPounds))
+	case weight > maxBoxWeightPounds && units > 1:
+		errs = append(errs, fmt.Errorf("%s weighs %.1f lb, boxes with multiple units cannot exceed %g lb", name, weight, maxBoxWeightPounds))
+	}
+	return errors.Join(errs...)
+}
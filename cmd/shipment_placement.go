@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type shipmentPlacementConfig struct {
+	Policy string
+}
+
+var (
+	shipmentPlacementCmd = &cobra.Command{
+		Use:   "placement [inbound plan id]",
+		Short: "compares placement options of an inbound plan with their fees and shipments, and confirms one",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(shipmentPlacement, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+	shipmentPlacementCfg shipmentPlacementConfig
+)
+
+func getShipmentPlacementCmd() *cobra.Command {
+	flags := shipmentPlacementCmd.PersistentFlags()
+	flags.StringVar(&shipmentPlacementCfg.Policy, "policy", "", fmt.Sprintf("select the option without prompting (%s)", strings.Join(placementPolicies, ", ")))
+	return shipmentPlacementCmd
+}
+
+const (
+	placementPolicyLowestFee       = "lowest-fee"
+	placementPolicyFewestShipments = "fewest-shipments"
+)
+
+var placementPolicies = []string{placementPolicyLowestFee, placementPolicyFewestShipments}
+
+// placementSummary is a placement option with its fees and discounts totalled, and the destination warehouses of its shipments.
+type placementSummary struct {
+	Option     fba_inbound.PlacementOption
+	Fees       float64
+	Discounts  float64
+	Currency   string
+	Warehouses []string
+}
+
+func newPlacementSummary(option fba_inbound.PlacementOption) placementSummary {
+	summary := placementSummary{Option: option}
+	for _, fee := range option.Fees {
+		summary.Fees += float64(fee.Value.Amount)
+		summary.Currency = cmp.Or(summary.Currency, fee.Value.Code)
+	}
+	for _, discount := range option.Discounts {
+		summary.Discounts += float64(discount.Value.Amount)
+		summary.Currency = cmp.Or(summary.Currency, discount.Value.Code)
+	}
+	return summary
+}
+
+// Net is the placement fee after discounts.
+func (s placementSummary) Net() float64 {
+	return s.Fees - s.Discounts
+}
+
+func shipmentPlacement(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	inboundPlanId := args[0]
+	if shipmentPlacementCfg.Policy != "" && !slices.Contains(placementPolicies, shipmentPlacementCfg.Policy) {
+		log.Error().Str("policy", shipmentPlacementCfg.Policy).Msgf("policy must be one of %s", strings.Join(placementPolicies, ", "))
+		return
+	}
+	plan, err := getOrTrackInboundPlan(app, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to get inbound plan")
+		return
+	}
+	step := inboundWorkflowStepIndex(plan.Step)
+	if plan.Step == inboundStepCancelled || step < inboundWorkflowStepIndex(inboundStepPackingInformationSet) {
+		log.Error().Str("step", plan.Step).Msg("packing information of the inbound plan is not set yet, continue it with shipment workflow first")
+		return
+	}
+	if step >= inboundWorkflowStepIndex(inboundStepPlacementConfirmed) {
+		log.Error().Str("step", plan.Step).Str("placement_option_id", plan.PlacementOptionID.String).Msg("placement of the inbound plan is already confirmed")
+		return
+	}
+
+	fmt.Println(color.CyanString("generating placement options"))
+	options, err := generatePlacementOptions(app, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate placement options")
+		return
+	}
+	if len(options) == 0 {
+		log.Error().Str("inbound_plan_id", inboundPlanId).Msg("no placement options offered")
+		return
+	}
+	placements := make([]placementSummary, 0, len(options))
+	for _, option := range options {
+		placements = append(placements, newPlacementSummary(option))
+	}
+	for i := range placements {
+		placements[i].Warehouses = placementWarehouses(app, inboundPlanId, placements[i].Option.ShipmentIds)
+	}
+	slices.SortStableFunc(placements, comparePlacementFee)
+	displayPlacementSummaries(placements)
+
+	placement, err := selectPlacement(placements, shipmentPlacementCfg.Policy)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to select placement option")
+		return
+	}
+	if err := confirmPlacementOption(app, inboundPlanId, placement, shipmentPlacementCfg.Policy); err != nil {
+		log.Error().Err(err).Str("placement_option_id", placement.Option.PlacementOptionId).Msg("failed to confirm placement option")
+		return
+	}
+	if !recordInboundPlanStep(app, inboundPlanId, inboundStepPlacementConfirmed) {
+		return
+	}
+	color.Green("placement option %s is confirmed with %d shipments", placement.Option.PlacementOptionId, len(placement.Option.ShipmentIds))
+	fmt.Printf("Continue with: halycon shipment transport %s\n", inboundPlanId)
+}
+
+// placementWarehouses returns the destination warehouse of every shipment, shipments that cannot be fetched are listed as unknown.
+func placementWarehouses(app AppCtx, inboundPlanId string, shipmentIds []string) []string {
+	warehouses := make([]string, 0, len(shipmentIds))
+	for _, shipmentId := range shipmentIds {
+		resp, err := app.Amazon.Client.GetInboundShipment(app.Ctx, inboundPlanId, shipmentId)
+		if err != nil {
+			log.Warn().Err(err).Str("shipment_id", shipmentId).Msg("failed to get shipment destination")
+			warehouses = append(warehouses, "?")
+			continue
+		}
+		warehouses = append(warehouses, cmp.Or(internal.NullString(resp.JSON200.Destination.WarehouseId).String, "?"))
+	}
+	return warehouses
+}
+
+// comparePlacementFee orders placements by their net fee, and by the number of shipments for equal fees.
+func comparePlacementFee(a, b placementSummary) int {
+	return cmp.Or(cmp.Compare(a.Net(), b.Net()), cmp.Compare(len(a.Option.ShipmentIds), len(b.Option.ShipmentIds)))
+}
+
+func displayPlacementSummaries(placements []placementSummary) {
+	fmt.Printf("%-40s %9s %-30s %12s %12s %12s %s\n", "Placement Option ID", "Shipments", "Warehouses", "Fees", "Discounts", "Net", "Expires")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------------------------------------------"))
+	for _, placement := range placements {
+		expires := "-"
+		if placement.Option.Expiration != nil {
+			expires = placement.Option.Expiration.Local().Format(time.DateTime)
+		}
+		fmt.Printf("%-40s %9d %-30s %12s %12s %12s %s\n",
+			placement.Option.PlacementOptionId, len(placement.Option.ShipmentIds), truncateString(strings.Join(placement.Warehouses, ", "), 30),
+			formatPlacementAmount(placement.Fees, placement.Currency), formatPlacementAmount(placement.Discounts, placement.Currency),
+			formatPlacementAmount(placement.Net(), placement.Currency), expires)
+	}
+}
+
+func formatPlacementAmount(amount float64, currency string) string {
+	return strings.TrimSpace(fmt.Sprintf("%.2f %s", amount, currency))
+}
+
+// selectPlacement selects the placement by policy, or prompts for one.
+func selectPlacement(placements []placementSummary, policy string) (placementSummary, error) {
+	switch policy {
+	case placementPolicyLowestFee:
+		placement := slices.MinFunc(placements, comparePlacementFee)
+		fmt.Printf("  %s %s\n", color.HiBlackString("lowest fee:"), placement.Option.PlacementOptionId)
+		return placement, nil
+	case placementPolicyFewestShipments:
+		placement := slices.MinFunc(placements, func(a, b placementSummary) int {
+			return cmp.Or(cmp.Compare(len(a.Option.ShipmentIds), len(b.Option.ShipmentIds)), cmp.Compare(a.Net(), b.Net()))
+		})
+		fmt.Printf("  %s %s\n", color.HiBlackString("fewest shipments:"), placement.Option.PlacementOptionId)
+		return placement, nil
+	}
+	choices := make([]huh.Option[string], 0, len(placements))
+	for _, placement := range placements {
+		key := fmt.Sprintf("%d shipments (%s), net %s, %s", len(placement.Option.ShipmentIds), strings.Join(placement.Warehouses, ", "),
+			formatPlacementAmount(placement.Net(), placement.Currency), formatIncentives(placement.Option.Fees, placement.Option.Discounts))
+		choices = append(choices, huh.NewOption(key, placement.Option.PlacementOptionId))
+	}
+	placementOptionId, err := selectInboundOption("Placement Option", choices)
+	if err != nil {
+		return placementSummary{}, err
+	}
+	index := slices.IndexFunc(placements, func(p placementSummary) bool { return p.Option.PlacementOptionId == placementOptionId })
+	return placements[index], nil
+}
@@ -0,0 +1,687 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type listShipmentPlansConfig struct {
+	Local  bool
+	Status string
+	Export exportConfig
+}
+
+type showShipmentPlanConfig struct {
+	Local bool
+}
+
+// inboundPlanStepUntracked is displayed for remote plans that are not tracked locally yet.
+const inboundPlanStepUntracked = "untracked"
+
+// inboundPlanStatusVoided is the status of cancelled plans.
+const inboundPlanStatusVoided = "VOIDED"
+
+var (
+	listShipmentPlansCmd = &cobra.Command{
+		Use:   "list",
+		Short: "lists inbound plans, synced with Amazon",
+		Run:   WrapCommandWithResources(listShipmentPlans, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+	listShipmentPlansCfg listShipmentPlansConfig
+
+	showShipmentPlanCmd = &cobra.Command{
+		Use:   "show [inbound plan id]",
+		Short: "displays an inbound plan with its items, shipments, boxes and operations, synced with Amazon",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(showShipmentPlan, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+	showShipmentPlanCfg showShipmentPlanConfig
+)
+
+func getShipmentListCmd() *cobra.Command {
+	flags := listShipmentPlansCmd.Flags()
+	flags.BoolVar(&listShipmentPlansCfg.Local, "local", false, "only list locally tracked plans without syncing")
+	flags.StringVar(&listShipmentPlansCfg.Status, "status", "", "only list plans with status ACTIVE, VOIDED or SHIPPED")
+	addExportFlags(flags, &listShipmentPlansCfg.Export, "inbound_plans")
+	return listShipmentPlansCmd
+}
+
+func getShipmentShowCmd() *cobra.Command {
+	showShipmentPlanCmd.Flags().BoolVar(&showShipmentPlanCfg.Local, "local", false, "display the locally tracked plan without syncing")
+	return showShipmentPlanCmd
+}
+
+// inboundPlanRow is a single row of `shipment list`, either a tracked plan or an untracked remote plan.
+type inboundPlanRow struct {
+	ID            string
+	Name          string
+	Status        string
+	Step          string
+	MarketplaceID string
+	Shipments     int
+	CreatedAt     time.Time
+	SyncedAt      *time.Time
+}
+
+func listShipmentPlans(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	status := strings.ToUpper(listShipmentPlansCfg.Status)
+
+	var untracked []fba_inbound.InboundPlanSummary
+	if !listShipmentPlansCfg.Local {
+		var err error
+		untracked, err = syncInboundPlanSummaries(app, status)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to sync inbound plans")
+			return
+		}
+	}
+
+	plans, err := app.Query.ListInboundPlans(app.Ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list inbound plans")
+		return
+	}
+	rows := make([]inboundPlanRow, 0, len(plans)+len(untracked))
+	for _, plan := range plans {
+		if status != "" && plan.Status.String != status {
+			continue
+		}
+		shipments, err := app.Query.GetInboundPlanShipments(app.Ctx, plan.InboundPlanID)
+		if err != nil {
+			log.Error().Err(err).Str("inbound_plan_id", plan.InboundPlanID).Msg("failed to get shipments")
+			return
+		}
+		row := inboundPlanRow{
+			ID:            plan.InboundPlanID,
+			Name:          plan.Name.String,
+			Status:        plan.Status.String,
+			Step:          plan.Step,
+			MarketplaceID: plan.MarketplaceID.String,
+			Shipments:     len(shipments),
+			CreatedAt:     plan.CreatedAt,
+		}
+		if plan.SyncedAt.Valid {
+			row.SyncedAt = &plan.SyncedAt.Time
+		}
+		rows = append(rows, row)
+	}
+	for _, plan := range untracked {
+		var marketplaceId string
+		if len(plan.MarketplaceIds) > 0 {
+			marketplaceId = plan.MarketplaceIds[0]
+		}
+		rows = append(rows, inboundPlanRow{
+			ID:            plan.InboundPlanId,
+			Name:          plan.Name,
+			Status:        plan.Status,
+			Step:          inboundPlanStepUntracked,
+			MarketplaceID: marketplaceId,
+			CreatedAt:     plan.CreatedAt,
+		})
+	}
+	if len(rows) == 0 {
+		log.Warn().Msg("no inbound plans found")
+		return
+	}
+
+	fmt.Printf("%-40s %-30s %-8s %-27s %-9s %-20s\n", "Inbound Plan ID", "Name", "Status", "Step", "Shipments", "Created")
+	fmt.Println(color.HiBlackString("%s", "--------------------------------------------------------------------------------------------------------------------------------------"))
+	for _, row := range rows {
+		fmt.Printf("%-40s %-30s %-8s %-27s %-9d %-20s\n", row.ID, truncateString(row.Name, 30), valueOrDash(row.Status), row.Step, row.Shipments, row.CreatedAt.Local().Format(snapshotTimeLayout))
+	}
+
+	if listShipmentPlansCfg.Export.Enabled() {
+		table := export.Table{
+			Name: "inbound_plans",
+			Columns: []export.Column{
+				{Name: "Inbound Plan ID", Key: "inbound_plan_id", Type: export.String},
+				{Name: "Name", Key: "name", Type: export.String},
+				{Name: "Status", Key: "status", Type: export.String},
+				{Name: "Step", Key: "step", Type: export.String},
+				{Name: "Marketplace ID", Key: "marketplace_id", Type: export.String},
+				{Name: "Shipments", Key: "shipments", Type: export.Int},
+				{Name: "Created At", Key: "created_at", Type: export.Time},
+				{Name: "Synced At", Key: "synced_at", Type: export.Time},
+			},
+		}
+		for _, row := range rows {
+			var syncedAt any
+			if row.SyncedAt != nil {
+				syncedAt = *row.SyncedAt
+			}
+			table.Rows = append(table.Rows, []any{row.ID, row.Name, row.Status, row.Step, row.MarketplaceID, row.Shipments, row.CreatedAt, syncedAt})
+		}
+		if err := writeExport(listShipmentPlansCfg.Export, "inbound_plans", table); err != nil {
+			log.Error().Err(err).Msg("failed to export inbound plans")
+		}
+	}
+}
+
+// syncInboundPlanSummaries updates name, status and source address of tracked plans from listInboundPlans,
+// and returns the remote plans that are not tracked locally.
+func syncInboundPlanSummaries(app AppCtx, status string) ([]fba_inbound.InboundPlanSummary, error) {
+	params := &fba_inbound.ListInboundPlansParams{PageSize: internal.Ptr(30)}
+	if status != "" {
+		params.Status = internal.Ptr(fba_inbound.ListInboundPlansParamsStatus(status))
+	}
+	var untracked []fba_inbound.InboundPlanSummary
+	now := time.Now().UTC()
+	for {
+		resp, err := app.Amazon.Client.ListInboundPlans(app.Ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200.InboundPlans != nil {
+			for _, plan := range *resp.JSON200.InboundPlans {
+				_, err := app.Query.GetInboundPlan(app.Ctx, plan.InboundPlanId)
+				if errors.Is(err, sql.ErrNoRows) {
+					untracked = append(untracked, plan)
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				if err := updateInboundPlanDetails(app.Ctx, app.Query, plan.InboundPlanId, plan.Name, plan.Status, plan.SourceAddress, now); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return untracked, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+func updateInboundPlanDetails(ctx context.Context, query *db.Queries, inboundPlanId string, name string, status string, sourceAddress fba_inbound.Address, syncedAt time.Time) error {
+	address, err := json.Marshal(sourceAddress)
+	if err != nil {
+		return fmt.Errorf("failed to marshal source address: %w", err)
+	}
+	return query.UpdateInboundPlanDetails(ctx, db.UpdateInboundPlanDetailsParams{
+		Name:          sql.NullString{String: name, Valid: name != ""},
+		Status:        sql.NullString{String: status, Valid: status != ""},
+		SourceAddress: sql.NullString{String: string(address), Valid: true},
+		SyncedAt:      sql.NullTime{Time: syncedAt, Valid: true},
+		InboundPlanID: inboundPlanId,
+	})
+}
+
+// syncInboundPlan refreshes a plan, its items, boxes and shipments from Amazon, and starts tracking it if it is not tracked yet.
+// Shipments that are no longer in the plan are removed.
+func syncInboundPlan(app AppCtx, inboundPlanId string) error {
+	resp, err := app.Amazon.Client.GetInboundPlan(app.Ctx, inboundPlanId)
+	if err != nil {
+		return fmt.Errorf("failed to get inbound plan: %w", err)
+	}
+	remote := resp.JSON200
+	if _, err := app.Query.GetInboundPlan(app.Ctx, inboundPlanId); errors.Is(err, sql.ErrNoRows) {
+		if remote.Status == "ACTIVE" {
+			if _, err := getOrTrackInboundPlan(app, inboundPlanId); err != nil {
+				return err
+			}
+		} else {
+			// voided and shipped plans have no workflow steps left
+			var marketplaceId string
+			if len(remote.MarketplaceIds) > 0 {
+				marketplaceId = remote.MarketplaceIds[0]
+			}
+			if err := trackInboundPlan(app, inboundPlanId, &remote.Name, marketplaceId, remote.SourceAddress); err != nil {
+				return err
+			}
+			step := inboundStepCompleted
+			if remote.Status == inboundPlanStatusVoided {
+				step = inboundStepCancelled
+			}
+			if err := app.Query.UpdateInboundPlanStep(app.Ctx, db.UpdateInboundPlanStepParams{Step: step, UpdatedAt: time.Now().UTC(), InboundPlanID: inboundPlanId}); err != nil {
+				return err
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+
+	items, err := listInboundPlanItems(app, inboundPlanId)
+	if err != nil {
+		return fmt.Errorf("failed to list inbound plan items: %w", err)
+	}
+	boxes, err := listInboundPlanBoxes(app, inboundPlanId)
+	if err != nil {
+		return fmt.Errorf("failed to list inbound plan boxes: %w", err)
+	}
+	var shipments []fba_inbound.Shipment
+	shipmentItems := map[string][]fba_inbound.Item{}
+	if remote.Shipments != nil {
+		for _, summary := range *remote.Shipments {
+			shipment, err := app.Amazon.Client.GetInboundShipment(app.Ctx, inboundPlanId, summary.ShipmentId)
+			if err != nil {
+				return fmt.Errorf("failed to get shipment %s: %w", summary.ShipmentId, err)
+			}
+			shipments = append(shipments, *shipment.JSON200)
+			if shipmentItems[summary.ShipmentId], err = listShipmentItems(app, inboundPlanId, summary.ShipmentId); err != nil {
+				return fmt.Errorf("failed to list items of shipment %s: %w", summary.ShipmentId, err)
+			}
+		}
+	}
+
+	tx, err := app.DB.BeginTx(app.Ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer internal.Rollback(tx)
+	query := app.Query.WithTx(tx)
+	if err := updateInboundPlanDetails(app.Ctx, query, inboundPlanId, remote.Name, remote.Status, remote.SourceAddress, time.Now().UTC()); err != nil {
+		return err
+	}
+	if remote.Status == inboundPlanStatusVoided {
+		if err := query.UpdateInboundPlanStep(app.Ctx, db.UpdateInboundPlanStepParams{Step: inboundStepCancelled, UpdatedAt: time.Now().UTC(), InboundPlanID: inboundPlanId}); err != nil {
+			return err
+		}
+	}
+	if err := query.DeleteInboundPlanItems(app.Ctx, inboundPlanId); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := query.InsertInboundPlanItem(app.Ctx, db.InsertInboundPlanItemParams{
+			InboundPlanID: inboundPlanId,
+			Msku:          item.Msku,
+			Asin:          sql.NullString{String: item.Asin, Valid: item.Asin != ""},
+			Fnsku:         sql.NullString{String: item.Fnsku, Valid: item.Fnsku != ""},
+			Quantity:      int64(item.Quantity),
+			PrepOwner:     sql.NullString{String: itemPrepOwner(item), Valid: true},
+			LabelOwner:    sql.NullString{String: item.LabelOwner, Valid: item.LabelOwner != ""},
+		}); err != nil {
+			return err
+		}
+	}
+	if err := query.DeleteInboundPlanBoxes(app.Ctx, inboundPlanId); err != nil {
+		return err
+	}
+	for _, box := range boxes {
+		if err := query.InsertInboundPlanBox(app.Ctx, inboundPlanBoxParams(inboundPlanId, box)); err != nil {
+			return err
+		}
+	}
+	if err := query.DeleteInboundPlanShipmentItems(app.Ctx, inboundPlanId); err != nil {
+		return err
+	}
+	for shipmentId, items := range shipmentItems {
+		for _, item := range items {
+			if err := query.InsertInboundPlanShipmentItem(app.Ctx, db.InsertInboundPlanShipmentItemParams{
+				InboundPlanID: inboundPlanId,
+				ShipmentID:    shipmentId,
+				Msku:          item.Msku,
+				Asin:          sql.NullString{String: item.Asin, Valid: item.Asin != ""},
+				Fnsku:         sql.NullString{String: item.Fnsku, Valid: item.Fnsku != ""},
+				Quantity:      int64(item.Quantity),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	// shipments of placement options that were not confirmed are removed from the plan by Amazon
+	local, err := query.GetInboundPlanShipments(app.Ctx, inboundPlanId)
+	if err != nil {
+		return err
+	}
+	for _, shipment := range local {
+		if slices.ContainsFunc(shipments, func(s fba_inbound.Shipment) bool { return s.ShipmentId == shipment.ShipmentID }) {
+			continue
+		}
+		if err := query.DeleteInboundPlanShipment(app.Ctx, db.DeleteInboundPlanShipmentParams{InboundPlanID: inboundPlanId, ShipmentID: shipment.ShipmentID}); err != nil {
+			return err
+		}
+	}
+	for _, shipment := range shipments {
+		if err := query.InsertInboundPlanShipment(app.Ctx, db.InsertInboundPlanShipmentParams{InboundPlanID: inboundPlanId, ShipmentID: shipment.ShipmentId}); err != nil {
+			return err
+		}
+		// shipments can be moved to another address than the plan with shipment update-source-address
+		var sourceAddress sql.NullString
+		if shipment.Source.Address != nil {
+			address, err := json.Marshal(shipment.Source.Address)
+			if err != nil {
+				return fmt.Errorf("failed to marshal source address of shipment %s: %w", shipment.ShipmentId, err)
+			}
+			sourceAddress = sql.NullString{String: string(address), Valid: true}
+		}
+		if err := query.UpdateInboundPlanShipmentDetails(app.Ctx, db.UpdateInboundPlanShipmentDetailsParams{
+			Name:                 internal.NullString(shipment.Name),
+			Status:               internal.NullString(shipment.Status),
+			DestinationWarehouse: internal.NullString(shipment.Destination.WarehouseId),
+			AmazonReferenceID:    internal.NullString(shipment.AmazonReferenceId),
+			SourceAddress:        sourceAddress,
+			InboundPlanID:        inboundPlanId,
+			ShipmentID:           shipment.ShipmentId,
+		}); err != nil {
+			return err
+		}
+		if shipment.SelectedTransportationOptionId != nil {
+			if err := query.UpdateInboundPlanShipmentTransportationOption(app.Ctx, db.UpdateInboundPlanShipmentTransportationOptionParams{
+				TransportationOptionID: internal.NullString(shipment.SelectedTransportationOptionId),
+				InboundPlanID:          inboundPlanId,
+				ShipmentID:             shipment.ShipmentId,
+			}); err != nil {
+				return err
+			}
+		}
+		if shipment.SelectedDeliveryWindow != nil {
+			if err := query.UpdateInboundPlanShipmentDeliveryWindowOption(app.Ctx, db.UpdateInboundPlanShipmentDeliveryWindowOptionParams{
+				DeliveryWindowOptionID: sql.NullString{String: shipment.SelectedDeliveryWindow.DeliveryWindowOptionId, Valid: true},
+				InboundPlanID:          inboundPlanId,
+				ShipmentID:             shipment.ShipmentId,
+			}); err != nil {
+				return err
+			}
+		}
+		if shipment.ShipmentConfirmationId != nil {
+			if err := query.UpdateInboundPlanShipmentConfirmationID(app.Ctx, db.UpdateInboundPlanShipmentConfirmationIDParams{
+				ConfirmationID: internal.NullString(shipment.ShipmentConfirmationId),
+				InboundPlanID:  inboundPlanId,
+				ShipmentID:     shipment.ShipmentId,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// itemPrepOwner returns the owner of the first prep instruction, items without instructions need no prep.
+func itemPrepOwner(item fba_inbound.Item) string {
+	for _, instruction := range item.PrepInstructions {
+		if instruction.PrepOwner != nil {
+			return *instruction.PrepOwner
+		}
+	}
+	return string(fba_inbound.NONE)
+}
+
+func inboundPlanBoxParams(inboundPlanId string, box fba_inbound.Box) db.InsertInboundPlanBoxParams {
+	params := db.InsertInboundPlanBoxParams{
+		InboundPlanID: inboundPlanId,
+		PackageID:     box.PackageId,
+		BoxID:         internal.NullString(box.BoxId),
+	}
+	if box.Quantity != nil {
+		params.Quantity = sql.NullInt64{Int64: int64(*box.Quantity), Valid: true}
+	}
+	if box.Dimensions != nil {
+		params.Length = sql.NullFloat64{Float64: float64(box.Dimensions.Length), Valid: true}
+		params.Width = sql.NullFloat64{Float64: float64(box.Dimensions.Width), Valid: true}
+		params.Height = sql.NullFloat64{Float64: float64(box.Dimensions.Height), Valid: true}
+		params.DimensionUnit = sql.NullString{String: string(box.Dimensions.UnitOfMeasurement), Valid: true}
+	}
+	if box.Weight != nil {
+		params.Weight = sql.NullFloat64{Float64: float64(box.Weight.Value), Valid: true}
+		params.WeightUnit = sql.NullString{String: string(box.Weight.Unit), Valid: true}
+	}
+	if box.Items != nil {
+		var count int
+		for _, item := range *box.Items {
+			count += item.Quantity
+		}
+		params.ItemCount = sql.NullInt64{Int64: int64(count), Valid: true}
+	}
+	if box.Destination != nil {
+		params.DestinationWarehouse = internal.NullString(box.Destination.WarehouseId)
+	}
+	return params
+}
+
+func listInboundPlanItems(app AppCtx, inboundPlanId string) ([]fba_inbound.Item, error) {
+	var items []fba_inbound.Item
+	params := &fba_inbound.ListInboundPlanItemsParams{PageSize: internal.Ptr(100)}
+	for {
+		resp, err := app.Amazon.Client.ListInboundPlanItems(app.Ctx, inboundPlanId, params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.JSON200.Items...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return items, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+func listInboundPlanBoxes(app AppCtx, inboundPlanId string) ([]fba_inbound.Box, error) {
+	var boxes []fba_inbound.Box
+	params := &fba_inbound.ListInboundPlanBoxesParams{PageSize: internal.Ptr(100)}
+	for {
+		resp, err := app.Amazon.Client.ListInboundPlanBoxes(app.Ctx, inboundPlanId, params)
+		if err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, resp.JSON200.Boxes...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return boxes, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+func listShipmentItems(app AppCtx, inboundPlanId string, shipmentId string) ([]fba_inbound.Item, error) {
+	var items []fba_inbound.Item
+	params := &fba_inbound.ListShipmentItemsParams{PageSize: internal.Ptr(100)}
+	for {
+		resp, err := app.Amazon.Client.ListShipmentItems(app.Ctx, inboundPlanId, shipmentId, params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.JSON200.Items...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return items, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+// recordInboundPlanItems stores the items a plan is created with, they are replaced with the items reported by Amazon on sync.
+func recordInboundPlanItems(app AppCtx, inboundPlanId string, items []fba_inbound.ItemInput) error {
+	tx, err := app.DB.BeginTx(app.Ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer internal.Rollback(tx)
+	query := app.Query.WithTx(tx)
+	for _, item := range items {
+		if err := query.InsertInboundPlanItem(app.Ctx, db.InsertInboundPlanItemParams{
+			InboundPlanID: inboundPlanId,
+			Msku:          item.Msku,
+			Quantity:      int64(item.Quantity),
+			PrepOwner:     sql.NullString{String: string(item.PrepOwner), Valid: item.PrepOwner != ""},
+			LabelOwner:    sql.NullString{String: string(item.LabelOwner), Valid: item.LabelOwner != ""},
+		}); err != nil {
+			return fmt.Errorf("failed to record item %s: %w", item.Msku, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// recordInboundPlanOperation stores the last known status of an operation of a plan.
+func recordInboundPlanOperation(app AppCtx, inboundPlanId string, status *fba_inbound.InboundOperationStatus) error {
+	now := time.Now().UTC()
+	return app.Query.UpsertInboundPlanOperation(app.Ctx, db.UpsertInboundPlanOperationParams{
+		OperationID:   status.OperationId,
+		InboundPlanID: inboundPlanId,
+		Operation:     status.Operation,
+		Status:        string(status.OperationStatus),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+// waitForPlanOperation waits for an operation of a tracked plan and records its final status.
+func waitForPlanOperation(app AppCtx, inboundPlanId string, operationId string) (*fba_inbound.InboundOperationStatus, error) {
+	status, err := waitForOperation(app, operationId)
+	if status != nil {
+		if recordErr := recordInboundPlanOperation(app, inboundPlanId, status); recordErr != nil {
+			log.Warn().Err(recordErr).Str("operation_id", operationId).Msg("failed to record operation")
+		}
+	}
+	return status, err
+}
+
+func showShipmentPlan(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	inboundPlanId := args[0]
+	if !showShipmentPlanCfg.Local {
+		if err := syncInboundPlan(app, inboundPlanId); err != nil {
+			log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to sync inbound plan, displaying local data")
+		}
+	}
+	plan, err := app.Query.GetInboundPlan(app.Ctx, inboundPlanId)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Error().Str("inbound_plan_id", inboundPlanId).Msg("inbound plan is not tracked, run without --local to sync it")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get inbound plan")
+		return
+	}
+	items, err := app.Query.GetInboundPlanItems(app.Ctx, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get inbound plan items")
+		return
+	}
+	shipments, err := app.Query.GetInboundPlanShipments(app.Ctx, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get inbound plan shipments")
+		return
+	}
+	boxes, err := app.Query.GetInboundPlanBoxes(app.Ctx, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get inbound plan boxes")
+		return
+	}
+	operations, err := app.Query.GetInboundPlanOperations(app.Ctx, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get inbound plan operations")
+		return
+	}
+	placements, err := app.Query.GetInboundPlanPlacements(app.Ctx, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get inbound plan placements")
+		return
+	}
+
+	bold := color.New(color.Bold).SprintFunc()
+	fmt.Printf("%s %s\n", bold("Inbound Plan:"), plan.InboundPlanID)
+	fmt.Printf("%s %s\n", bold("Name:"), valueOrDash(plan.Name.String))
+	fmt.Printf("%s %s\n", bold("Status:"), valueOrDash(plan.Status.String))
+	fmt.Printf("%s %s\n", bold("Workflow Step:"), plan.Step)
+	fmt.Printf("%s %s\n", bold("Marketplace:"), valueOrDash(plan.MarketplaceID.String))
+	fmt.Printf("%s %s\n", bold("Source Address:"), formatSourceAddress(plan.SourceAddress))
+	fmt.Printf("%s %s\n", bold("Packing Option:"), valueOrDash(plan.PackingOptionID.String))
+	fmt.Printf("%s %s\n", bold("Placement Option:"), valueOrDash(plan.PlacementOptionID.String))
+	fmt.Printf("%s %s\n", bold("Created:"), plan.CreatedAt.Local().Format(snapshotTimeLayout))
+	if plan.SyncedAt.Valid {
+		fmt.Printf("%s %s\n", bold("Synced:"), plan.SyncedAt.Time.Local().Format(snapshotTimeLayout))
+	}
+
+	fmt.Printf("\n%s\n", bold(fmt.Sprintf("Items (%d)", len(items))))
+	fmt.Printf("%-30s %-12s %-12s %8s %-10s %-11s\n", "MSKU", "ASIN", "FNSKU", "Quantity", "Prep Owner", "Label Owner")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------"))
+	for _, item := range items {
+		fmt.Printf("%-30s %-12s %-12s %8d %-10s %-11s\n", item.Msku, valueOrDash(item.Asin.String), valueOrDash(item.Fnsku.String), item.Quantity, valueOrDash(item.PrepOwner.String), valueOrDash(item.LabelOwner.String))
+	}
+
+	fmt.Printf("\n%s\n", bold(fmt.Sprintf("Shipments (%d)", len(shipments))))
+	fmt.Printf("%-40s %-16s %-12s %-10s %-40s\n", "Shipment ID", "Confirmation ID", "Status", "Warehouse", "Transportation Option")
+	fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------------------------"))
+	for _, shipment := range shipments {
+		fmt.Printf("%-40s %-16s %-12s %-10s %-40s\n", shipment.ShipmentID, valueOrDash(shipment.ConfirmationID.String), valueOrDash(shipment.Status.String), valueOrDash(shipment.DestinationWarehouse.String), valueOrDash(shipment.TransportationOptionID.String))
+	}
+
+	if len(boxes) > 0 {
+		fmt.Printf("\n%s\n", bold(fmt.Sprintf("Boxes (%d)", len(boxes))))
+		fmt.Printf("%-40s %8s %-22s %-12s %6s %-10s\n", "Package ID", "Quantity", "Dimensions", "Weight", "Items", "Warehouse")
+		fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------"))
+		for _, box := range boxes {
+			dimensions := "-"
+			if box.Length.Valid {
+				dimensions = fmt.Sprintf("%gx%gx%g %s", box.Length.Float64, box.Width.Float64, box.Height.Float64, box.DimensionUnit.String)
+			}
+			weight := "-"
+			if box.Weight.Valid {
+				weight = fmt.Sprintf("%g %s", box.Weight.Float64, box.WeightUnit.String)
+			}
+			fmt.Printf("%-40s %8d %-22s %-12s %6d %-10s\n", box.PackageID, box.Quantity.Int64, dimensions, weight, box.ItemCount.Int64, valueOrDash(box.DestinationWarehouse.String))
+		}
+	}
+
+	if len(placements) > 0 {
+		fmt.Printf("\n%s\n", bold(fmt.Sprintf("Placements (%d)", len(placements))))
+		fmt.Printf("%-40s %9s %-30s %12s %12s %-18s %-20s\n", "Placement Option ID", "Shipments", "Warehouses", "Fees", "Discounts", "Policy", "Confirmed")
+		fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------------------------------------------------"))
+		for _, placement := range placements {
+			fmt.Printf("%-40s %9d %-30s %12s %12s %-18s %-20s\n", placement.PlacementOptionID, placement.ShipmentCount, truncateString(valueOrDash(placement.Warehouses.String), 30),
+				formatPlacementAmount(placement.Fees, placement.Currency.String), formatPlacementAmount(placement.Discounts, placement.Currency.String),
+				cmp.Or(placement.Policy.String, "interactive"), placement.ConfirmedAt.Local().Format(snapshotTimeLayout))
+		}
+	}
+
+	if len(operations) > 0 {
+		fmt.Printf("\n%s\n", bold(fmt.Sprintf("Operations (%d)", len(operations))))
+		fmt.Printf("%-40s %-36s %-12s %-20s\n", "Operation ID", "Operation", "Status", "Updated")
+		fmt.Println(color.HiBlackString("%s", "------------------------------------------------------------------------------------------------------------"))
+		for _, operation := range operations {
+			fmt.Printf("%-40s %-36s %-12s %-20s\n", operation.OperationID, operation.Operation, operation.Status, operation.UpdatedAt.Local().Format(snapshotTimeLayout))
+		}
+	}
+}
+
+// formatSourceAddress formats the stored source address json as a single line.
+func formatSourceAddress(raw sql.NullString) string {
+	if !raw.Valid {
+		return "-"
+	}
+	var address fba_inbound.Address
+	if err := json.Unmarshal([]byte(raw.String), &address); err != nil {
+		return raw.String
+	}
+	parts := []string{address.Name}
+	if address.CompanyName != nil {
+		parts = append(parts, *address.CompanyName)
+	}
+	parts = append(parts, address.AddressLine1)
+	if address.AddressLine2 != nil {
+		parts = append(parts, *address.AddressLine2)
+	}
+	parts = append(parts, address.City)
+	if address.StateOrProvinceCode != nil {
+		parts = append(parts, *address.StateOrProvinceCode)
+	}
+	parts = append(parts, address.PostalCode, address.CountryCode)
+	nonEmpty := parts[:0]
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, ", ")
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
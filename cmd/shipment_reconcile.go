@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound_v0"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type reconcileShipmentConfig struct {
+	InboundPlanId string
+	Output        string
+	Local         bool
+}
+
+var (
+	reconcileShipmentCmd = &cobra.Command{
+		Use:   "reconcile [shipment id or confirmation id]",
+		Short: "compares submitted and received units of a shipment and exports shortages as a claim csv",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(reconcileShipment, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound, ServiceFBAInboundV0}}),
+	}
+	reconcileShipmentCfg reconcileShipmentConfig
+)
+
+func getReconcileShipmentCmd() *cobra.Command {
+	flags := reconcileShipmentCmd.PersistentFlags()
+	flags.StringVar(&reconcileShipmentCfg.InboundPlanId, "plan", "", "inbound plan of the shipment, required if the shipment is not tracked locally")
+	flags.StringVarP(&reconcileShipmentCfg.Output, "output", "o", "", "claim csv for shortages (default: claim_<confirmation id>.csv)")
+	flags.BoolVar(&reconcileShipmentCfg.Local, "local", false, "use the submitted quantities from the last sync without syncing the plan")
+	return reconcileShipmentCmd
+}
+
+// shipmentStatusClosed is the status of shipments that Amazon finished receiving, received quantities of other shipments may still change.
+const shipmentStatusClosed = "CLOSED"
+
+// shipmentReconciliation compares the units of an item submitted with the plan against the units received by Amazon.
+type shipmentReconciliation struct {
+	MSKU      string
+	FNSKU     string
+	ASIN      string
+	Submitted int
+	Shipped   int
+	Received  int
+}
+
+// Difference is negative for shortages and positive for overages.
+func (r shipmentReconciliation) Difference() int {
+	return r.Received - r.Submitted
+}
+
+func reconcileShipment(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	shipment, err := resolveInboundShipment(app, args[0], reconcileShipmentCfg.InboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("shipment_id", args[0]).Msg("failed to get shipment")
+		return
+	}
+	if !reconcileShipmentCfg.Local {
+		if err := syncInboundPlan(app, shipment.InboundPlanID); err != nil {
+			log.Warn().Err(err).Str("inbound_plan_id", shipment.InboundPlanID).Msg("failed to sync inbound plan, using submitted quantities from the last sync")
+		} else if synced, err := app.Query.FindInboundPlanShipment(app.Ctx, shipment.ShipmentID); err == nil {
+			shipment = synced
+		}
+	}
+	submitted, err := app.Query.GetInboundPlanShipmentItems(app.Ctx, db.GetInboundPlanShipmentItemsParams{InboundPlanID: shipment.InboundPlanID, ShipmentID: shipment.ShipmentID})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get submitted shipment items")
+		return
+	}
+	if len(submitted) == 0 {
+		log.Error().Str("shipment_id", shipment.ShipmentID).Msg("no submitted items are tracked for the shipment, run without --local to sync them")
+		return
+	}
+	marketplaceId := cfg.Amazon.Auth.DefaultMerchant.MarketplaceID[0]
+	if plan, err := app.Query.GetInboundPlan(app.Ctx, shipment.InboundPlanID); err == nil && plan.MarketplaceID.String != "" {
+		marketplaceId = plan.MarketplaceID.String
+	}
+	received, err := listReceivedShipmentItems(app, shipment.ConfirmationID.String, marketplaceId)
+	if err != nil {
+		log.Error().Err(err).Str("confirmation_id", shipment.ConfirmationID.String).Msg("failed to get received shipment items")
+		return
+	}
+
+	reconciliations := reconcileShipmentItems(submitted, received)
+	displayShipmentReconciliation(shipment, reconciliations)
+	if shipment.Status.String != shipmentStatusClosed {
+		log.Warn().Str("status", valueOrDash(shipment.Status.String)).Msg("shipment is not closed yet, received quantities may still change")
+	}
+
+	var shortages []shipmentReconciliation
+	for _, reconciliation := range reconciliations {
+		if reconciliation.Difference() < 0 {
+			shortages = append(shortages, reconciliation)
+		}
+	}
+	if len(shortages) == 0 {
+		color.Green("no shortages")
+		return
+	}
+	output := reconcileShipmentCfg.Output
+	if output == "" {
+		output = fmt.Sprintf("claim_%s.csv", shipment.ConfirmationID.String)
+	}
+	if err := writeShipmentClaim(output, shipment.ConfirmationID.String, shortages); err != nil {
+		log.Error().Err(err).Str("file", output).Msg("failed to write claim csv")
+		return
+	}
+	color.Green("%d short items written to %s", len(shortages), output)
+}
+
+// listReceivedShipmentItems returns every item of a shipment with its shipped and received quantities. Only the first page is
+// returned by shipment ID, the following pages are requested with the next token.
+func listReceivedShipmentItems(app AppCtx, confirmationId string, marketplaceId string) (fba_inbound_v0.InboundShipmentItemList, error) {
+	resp, err := app.Amazon.Client.GetShipmentItemsByShipmentId(app.Ctx, confirmationId, &fba_inbound_v0.GetShipmentItemsByShipmentIdParams{})
+	if err != nil {
+		return nil, err
+	}
+	var items fba_inbound_v0.InboundShipmentItemList
+	page := resp.JSON200.Payload
+	for page != nil {
+		if page.ItemData != nil {
+			items = append(items, *page.ItemData...)
+		}
+		if page.NextToken == nil || *page.NextToken == "" {
+			break
+		}
+		next, err := app.Amazon.Client.GetShipmentItems(app.Ctx, &fba_inbound_v0.GetShipmentItemsParams{
+			QueryType:     fba_inbound_v0.GetShipmentItemsParamsQueryTypeNEXTTOKEN,
+			NextToken:     page.NextToken,
+			MarketplaceId: marketplaceId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page = next.JSON200.Payload
+	}
+	return items, nil
+}
+
+// reconcileShipmentItems matches submitted items with received items by MSKU. Items received without being submitted are
+// included with zero submitted units, items that are not received yet have zero received units.
+func reconcileShipmentItems(submitted []db.InboundPlanShipmentItem, received []fba_inbound_v0.InboundShipmentItem) []shipmentReconciliation {
+	reconciliations := make([]shipmentReconciliation, 0, len(submitted))
+	index := make(map[string]int, len(submitted))
+	for _, item := range submitted {
+		index[item.Msku] = len(reconciliations)
+		reconciliations = append(reconciliations, shipmentReconciliation{
+			MSKU:      item.Msku,
+			FNSKU:     item.Fnsku.String,
+			ASIN:      item.Asin.String,
+			Submitted: int(item.Quantity),
+		})
+	}
+	for _, item := range received {
+		i, ok := index[item.SellerSKU]
+		if !ok {
+			i = len(reconciliations)
+			index[item.SellerSKU] = i
+			reconciliations = append(reconciliations, shipmentReconciliation{MSKU: item.SellerSKU})
+		}
+		if item.FulfillmentNetworkSKU != nil && reconciliations[i].FNSKU == "" {
+			reconciliations[i].FNSKU = *item.FulfillmentNetworkSKU
+		}
+		reconciliations[i].Shipped += int(item.QuantityShipped)
+		if item.QuantityReceived != nil {
+			reconciliations[i].Received += int(*item.QuantityReceived)
+		}
+	}
+	slices.SortStableFunc(reconciliations, func(a, b shipmentReconciliation) int { return strings.Compare(a.MSKU, b.MSKU) })
+	return reconciliations
+}
+
+func displayShipmentReconciliation(shipment db.InboundPlanShipment, reconciliations []shipmentReconciliation) {
+	fmt.Printf("Shipment: %s (%s), status %s\n", shipment.ConfirmationID.String, shipment.ShipmentID, valueOrDash(shipment.Status.String))
+	fmt.Printf("%-40s %-12s %10s %10s %10s %10s\n", "MSKU", "FNSKU", "Submitted", "Shipped", "Received", "Difference")
+	fmt.Println(color.HiBlackString("%s", "-----------------------------------------------------------------------------------------------------"))
+	var total shipmentReconciliation
+	for _, r := range reconciliations {
+		fmt.Printf("%-40s %-12s %10d %10d %10d %s\n", truncateString(r.MSKU, 40), valueOrDash(r.FNSKU), r.Submitted, r.Shipped, r.Received, formatUnitDifference(r.Difference()))
+		total.Submitted += r.Submitted
+		total.Shipped += r.Shipped
+		total.Received += r.Received
+	}
+	fmt.Println(color.HiBlackString("%s", "-----------------------------------------------------------------------------------------------------"))
+	fmt.Printf("%-40s %-12s %10d %10d %10d %s\n", "Total", "", total.Submitted, total.Shipped, total.Received, formatUnitDifference(total.Difference()))
+}
+
+func formatUnitDifference(difference int) string {
+	switch {
+	case difference < 0:
+		return color.RedString("%10d", difference)
+	case difference > 0:
+		return color.YellowString("%10s", "+"+strconv.Itoa(difference))
+	default:
+		return fmt.Sprintf("%10d", difference)
+	}
+}
+
+// writeShipmentClaim writes shortages in the units Seller Central asks for when opening a lost inbound shipment case.
+func writeShipmentClaim(path string, confirmationId string, shortages []shipmentReconciliation) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"Shipment ID", "MSKU", "FNSKU", "ASIN", "Units Submitted", "Units Shipped", "Units Received", "Units Short"}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, r := range shortages {
+		if err := writer.Write([]string{
+			confirmationId, r.MSKU, r.FNSKU, r.ASIN,
+			strconv.Itoa(r.Submitted), strconv.Itoa(r.Shipped), strconv.Itoa(r.Received), strconv.Itoa(-r.Difference()),
+		}); err != nil {
+			return fmt.Errorf("failed to write row for %s: %w", r.MSKU, err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
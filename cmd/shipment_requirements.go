@@ -0,0 +1,522 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// Sources of item requirements, recorded to tell learned requirements apart from the ones set by the user.
+const (
+	itemRequirementSourceError       = "error"
+	itemRequirementSourcePrepDetails = "prep_details"
+	itemRequirementSourceManual      = "manual"
+	itemRequirementSourceImport      = "import"
+)
+
+// Owner constraints returned by listPrepDetails.
+const (
+	ownerConstraintAmazonOnly    = "AMAZON_ONLY"
+	ownerConstraintNoneOnly      = "NONE_ONLY"
+	ownerConstraintSellerOnly    = "SELLER_ONLY"
+	allOwnersConstraintMustMatch = "MUST_MATCH"
+	prepTypeNoPrep               = "ITEM_NO_PREP"
+)
+
+// listPrepDetailsMaxMskus is the maximum number of MSKUs accepted by a single listPrepDetails request.
+const listPrepDetailsMaxMskus = 100
+
+// legacyItemRequirementsFile is where item requirements were cached before they were moved to the database.
+var legacyItemRequirementsFile = filepath.Join(os.TempDir(), "halycon_item_requirements.json")
+
+// ItemRequirements defines the ownership requirements for item preparation and labeling.
+// It specifies which entities are responsible for preparing and labeling items in a shipment.
+type ItemRequirements struct {
+	// PrepOwner indicates the entity responsible for preparing the item for shipment.
+	PrepOwner fba_inbound.PrepOwner `json:"prep_owner"`
+
+	// LabelOwner indicates the entity responsible for labeling the item for shipment.
+	LabelOwner fba_inbound.LabelOwner `json:"label_owner"`
+
+	// Source is where the requirements are learned from, see itemRequirementSource constants.
+	Source string `json:"-"`
+}
+
+// PrepRequirements is a mapping from MSKU to the ownership requirements of the item.
+type PrepRequirements map[string]ItemRequirements
+
+type shipmentRequirementsConfig struct {
+	MarketplaceID string
+}
+
+type setShipmentRequirementsConfig struct {
+	PrepOwner  string
+	LabelOwner string
+}
+
+type clearShipmentRequirementsConfig struct {
+	All bool
+}
+
+type listShipmentRequirementsConfig struct {
+	Export exportConfig
+}
+
+var (
+	shipmentRequirementsCmd = &cobra.Command{
+		Use:   "requirements",
+		Short: "manages prep and label owner requirements of items, used while creating inbound plans",
+	}
+	shipmentRequirementsCfg shipmentRequirementsConfig
+
+	listShipmentRequirementsCmd = &cobra.Command{
+		Use:   "list [msku...]",
+		Short: "lists stored item requirements, optionally filtered by MSKU",
+		Run:   WrapCommandWithResources(listShipmentRequirements, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+	listShipmentRequirementsCfg listShipmentRequirementsConfig
+
+	setShipmentRequirementsCmd = &cobra.Command{
+		Use:   "set <msku...>",
+		Short: "sets prep and label owners of items manually, learned requirements do not override manual ones",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   WrapCommandWithResources(setShipmentRequirements, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+	setShipmentRequirementsCfg setShipmentRequirementsConfig
+
+	importShipmentRequirementsCmd = &cobra.Command{
+		Use:   "import [file]",
+		Short: "imports item requirements from a csv (msku, prep_owner, label_owner) or the legacy json cache in the temp dir if no file is given",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   WrapCommandWithResources(importShipmentRequirements, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+
+	clearShipmentRequirementsCmd = &cobra.Command{
+		Use:   "clear [msku...]",
+		Short: "removes requirements of given items, or every item with --all",
+		Run:   WrapCommandWithResources(clearShipmentRequirements, ResourceConfig{Resources: []ResourceType{ResourceDB}}),
+	}
+	clearShipmentRequirementsCfg clearShipmentRequirementsConfig
+)
+
+func getShipmentRequirementsCmd() *cobra.Command {
+	shipmentRequirementsCmd.PersistentFlags().StringVar(&shipmentRequirementsCfg.MarketplaceID, "marketplace", "", "marketplace id (default first marketplace of the default merchant)")
+
+	addExportFlags(listShipmentRequirementsCmd.Flags(), &listShipmentRequirementsCfg.Export, "item_requirements")
+	shipmentRequirementsCmd.AddCommand(listShipmentRequirementsCmd)
+
+	setShipmentRequirementsCmd.Flags().StringVar(&setShipmentRequirementsCfg.PrepOwner, "prep-owner", "", "AMAZON, SELLER or NONE")
+	setShipmentRequirementsCmd.Flags().StringVar(&setShipmentRequirementsCfg.LabelOwner, "label-owner", "", "AMAZON, SELLER or NONE")
+	shipmentRequirementsCmd.AddCommand(setShipmentRequirementsCmd)
+
+	shipmentRequirementsCmd.AddCommand(importShipmentRequirementsCmd)
+
+	clearShipmentRequirementsCmd.Flags().BoolVar(&clearShipmentRequirementsCfg.All, "all", false, "remove requirements of every item in the marketplace")
+	shipmentRequirementsCmd.AddCommand(clearShipmentRequirementsCmd)
+	return shipmentRequirementsCmd
+}
+
+func requirementsMarketplaceID() (string, error) {
+	if shipmentRequirementsCfg.MarketplaceID != "" {
+		return shipmentRequirementsCfg.MarketplaceID, nil
+	}
+	if len(cfg.Amazon.Auth.DefaultMerchant.MarketplaceID) == 0 {
+		return "", errors.New("default merchant has no marketplace, use --marketplace")
+	}
+	return cfg.Amazon.Auth.DefaultMerchant.MarketplaceID[0], nil
+}
+
+func listShipmentRequirements(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	marketplaceId, err := requirementsMarketplaceID()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	rows, err := app.Query.ListItemRequirements(app.Ctx, db.ListItemRequirementsParams{
+		Merchant:      cfg.Amazon.Auth.DefaultMerchant.SellerToken,
+		MarketplaceID: marketplaceId,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list item requirements")
+		return
+	}
+	if len(args) > 0 {
+		filter := make(map[string]struct{}, len(args))
+		for _, msku := range args {
+			filter[msku] = struct{}{}
+		}
+		filtered := rows[:0]
+		for _, row := range rows {
+			if _, ok := filter[row.Msku]; ok {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+	if len(rows) == 0 {
+		log.Warn().Str("marketplace_id", marketplaceId).Msg("no item requirements found")
+		return
+	}
+
+	fmt.Printf("%-30s %-10s %-11s %-13s %-20s\n", "MSKU", "Prep Owner", "Label Owner", "Source", "Updated")
+	fmt.Println(color.HiBlackString("%s", "-------------------------------------------------------------------------------------"))
+	for _, row := range rows {
+		fmt.Printf("%-30s %-10s %-11s %-13s %-20s\n", row.Msku, row.PrepOwner, row.LabelOwner, row.Source, row.UpdatedAt.Local().Format(snapshotTimeLayout))
+	}
+
+	if listShipmentRequirementsCfg.Export.Enabled() {
+		table := export.Table{
+			Name: "item_requirements",
+			Columns: []export.Column{
+				{Name: "MSKU", Key: "msku", Type: export.String},
+				{Name: "Prep Owner", Key: "prep_owner", Type: export.String},
+				{Name: "Label Owner", Key: "label_owner", Type: export.String},
+				{Name: "Source", Key: "source", Type: export.String},
+				{Name: "Updated At", Key: "updated_at", Type: export.Time},
+			},
+		}
+		for _, row := range rows {
+			table.Rows = append(table.Rows, []any{row.Msku, row.PrepOwner, row.LabelOwner, row.Source, row.UpdatedAt})
+		}
+		if err := writeExport(listShipmentRequirementsCfg.Export, "item_requirements", table); err != nil {
+			log.Error().Err(err).Msg("failed to export item requirements")
+		}
+	}
+}
+
+func setShipmentRequirements(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	if setShipmentRequirementsCfg.PrepOwner == "" && setShipmentRequirementsCfg.LabelOwner == "" {
+		log.Error().Msg("--prep-owner or --label-owner is required")
+		return
+	}
+	marketplaceId, err := requirementsMarketplaceID()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	requirements, err := loadPrepRequirements(app, marketplaceId)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	for _, msku := range args {
+		requirement := requirements.get(msku)
+		if setShipmentRequirementsCfg.PrepOwner != "" {
+			if requirement.PrepOwner, err = parsePrepOwner(setShipmentRequirementsCfg.PrepOwner); err != nil {
+				log.Error().Err(err).Send()
+				return
+			}
+		}
+		if setShipmentRequirementsCfg.LabelOwner != "" {
+			if requirement.LabelOwner, err = parseLabelOwner(setShipmentRequirementsCfg.LabelOwner); err != nil {
+				log.Error().Err(err).Send()
+				return
+			}
+		}
+		requirement.Source = itemRequirementSourceManual
+		if err := savePrepRequirement(app.Ctx, app.Query, marketplaceId, msku, requirement); err != nil {
+			log.Error().Err(err).Str("msku", msku).Send()
+			return
+		}
+		color.Green("%s: prep owner %s, label owner %s", msku, requirement.PrepOwner, requirement.LabelOwner)
+	}
+}
+
+func importShipmentRequirements(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	marketplaceId, err := requirementsMarketplaceID()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	path := legacyItemRequirementsFile
+	if len(args) > 0 {
+		path = args[0]
+	}
+	file, err := internal.OpenFile(path)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	defer file.Close()
+
+	var requirements PrepRequirements
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		requirements, err = readLegacyPrepRequirements(file)
+	} else {
+		requirements, err = readPrepRequirementsCSV(file)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("file", path).Msg("failed to read item requirements")
+		return
+	}
+
+	tx, err := app.DB.BeginTx(app.Ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to begin transaction")
+		return
+	}
+	defer internal.Rollback(tx)
+	query := app.Query.WithTx(tx)
+	for msku, requirement := range requirements {
+		requirement.Source = itemRequirementSourceImport
+		if err := savePrepRequirement(app.Ctx, query, marketplaceId, msku, requirement); err != nil {
+			log.Error().Err(err).Str("msku", msku).Send()
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("failed to commit item requirements")
+		return
+	}
+	color.Green("imported requirements of %d items from %s", len(requirements), path)
+}
+
+func clearShipmentRequirements(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	if len(args) == 0 && !clearShipmentRequirementsCfg.All {
+		log.Error().Msg("provide MSKUs to clear, or --all to clear every item")
+		return
+	}
+	marketplaceId, err := requirementsMarketplaceID()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return
+	}
+	merchant := cfg.Amazon.Auth.DefaultMerchant.SellerToken
+	if clearShipmentRequirementsCfg.All {
+		if err := app.Query.DeleteItemRequirements(app.Ctx, db.DeleteItemRequirementsParams{Merchant: merchant, MarketplaceID: marketplaceId}); err != nil {
+			log.Error().Err(err).Msg("failed to clear item requirements")
+			return
+		}
+		color.Green("cleared every item requirement of marketplace %s", marketplaceId)
+		return
+	}
+	for _, msku := range args {
+		if err := app.Query.DeleteItemRequirement(app.Ctx, db.DeleteItemRequirementParams{Merchant: merchant, MarketplaceID: marketplaceId, Msku: msku}); err != nil {
+			log.Error().Err(err).Str("msku", msku).Msg("failed to clear item requirements")
+			return
+		}
+	}
+	color.Green("cleared requirements of %d items", len(args))
+}
+
+// get returns requirements of msku, defaulting to no prep and label owner.
+func (r PrepRequirements) get(msku string) ItemRequirements {
+	if requirement, exists := r[msku]; exists {
+		return requirement
+	}
+	return ItemRequirements{PrepOwner: fba_inbound.NONE, LabelOwner: fba_inbound.LabelOwnerNONE}
+}
+
+// loadPrepRequirements returns stored requirements of the default merchant in the marketplace.
+func loadPrepRequirements(app AppCtx, marketplaceId string) (PrepRequirements, error) {
+	rows, err := app.Query.ListItemRequirements(app.Ctx, db.ListItemRequirementsParams{
+		Merchant:      cfg.Amazon.Auth.DefaultMerchant.SellerToken,
+		MarketplaceID: marketplaceId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load item requirements: %w", err)
+	}
+	requirements := make(PrepRequirements, len(rows))
+	for _, row := range rows {
+		requirements[row.Msku] = ItemRequirements{
+			PrepOwner:  fba_inbound.PrepOwner(row.PrepOwner),
+			LabelOwner: fba_inbound.LabelOwner(row.LabelOwner),
+			Source:     row.Source,
+		}
+	}
+	return requirements, nil
+}
+
+func savePrepRequirement(ctx context.Context, query *db.Queries, marketplaceId string, msku string, requirement ItemRequirements) error {
+	err := query.UpsertItemRequirement(ctx, db.UpsertItemRequirementParams{
+		Merchant:      cfg.Amazon.Auth.DefaultMerchant.SellerToken,
+		MarketplaceID: marketplaceId,
+		Msku:          msku,
+		PrepOwner:     string(requirement.PrepOwner),
+		LabelOwner:    string(requirement.LabelOwner),
+		Source:        requirement.Source,
+		UpdatedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save item requirements: %w", err)
+	}
+	return nil
+}
+
+// prefillPrepRequirements fetches prep details of mskus from listPrepDetails and stores the owners they imply,
+// so plans are created with the correct owners instead of learning them from a failed attempt.
+// Requirements set by the user are never overridden.
+func prefillPrepRequirements(app AppCtx, marketplaceId string, mskus []string, requirements PrepRequirements) error {
+	for start := 0; start < len(mskus); start += listPrepDetailsMaxMskus {
+		batch := mskus[start:min(start+listPrepDetailsMaxMskus, len(mskus))]
+		resp, err := app.Amazon.Client.ListPrepDetails(app.Ctx, &fba_inbound.ListPrepDetailsParams{MarketplaceId: marketplaceId, Mskus: batch})
+		if err != nil {
+			return fmt.Errorf("failed to list prep details: %w", err)
+		}
+		for _, detail := range resp.JSON200.MskuPrepDetails {
+			existing := requirements.get(detail.Msku)
+			if existing.Source == itemRequirementSourceManual || existing.Source == itemRequirementSourceImport {
+				continue
+			}
+			requirement, derived := requirementsFromPrepDetail(detail, existing)
+			if !derived || requirement == existing {
+				continue
+			}
+			requirement.Source = itemRequirementSourcePrepDetails
+			if err := savePrepRequirement(app.Ctx, app.Query, marketplaceId, detail.Msku, requirement); err != nil {
+				return err
+			}
+			log.Info().
+				Str("msku", detail.Msku).
+				Str("prep_owner", string(requirement.PrepOwner)).
+				Str("label_owner", string(requirement.LabelOwner)).
+				Msg("item requirements updated from prep details")
+			requirements[detail.Msku] = requirement
+		}
+	}
+	return nil
+}
+
+// requirementsFromPrepDetail applies owner constraints of detail on top of base, reports whether any owner was derived.
+// Items that need prep but have no prep owner constraint are prepped by the seller.
+func requirementsFromPrepDetail(detail fba_inbound.MskuPrepDetail, base ItemRequirements) (ItemRequirements, bool) {
+	requirement := base
+	var prepDerived, labelDerived bool
+	if detail.PrepOwnerConstraint != nil {
+		switch *detail.PrepOwnerConstraint {
+		case ownerConstraintAmazonOnly:
+			requirement.PrepOwner, prepDerived = fba_inbound.AMAZON, true
+		case ownerConstraintNoneOnly:
+			requirement.PrepOwner, prepDerived = fba_inbound.NONE, true
+		case ownerConstraintSellerOnly:
+			requirement.PrepOwner, prepDerived = fba_inbound.SELLER, true
+		}
+	} else if requirement.PrepOwner == fba_inbound.NONE {
+		for _, prepType := range detail.PrepTypes {
+			if prepType != prepTypeNoPrep {
+				requirement.PrepOwner, prepDerived = fba_inbound.SELLER, true
+				break
+			}
+		}
+	}
+	if detail.LabelOwnerConstraint != nil {
+		switch *detail.LabelOwnerConstraint {
+		case ownerConstraintAmazonOnly:
+			requirement.LabelOwner, labelDerived = fba_inbound.LabelOwnerAMAZON, true
+		case ownerConstraintNoneOnly:
+			requirement.LabelOwner, labelDerived = fba_inbound.LabelOwnerNONE, true
+		case ownerConstraintSellerOnly:
+			requirement.LabelOwner, labelDerived = fba_inbound.LabelOwnerSELLER, true
+		}
+	}
+	if detail.AllOwnersConstraint != nil && *detail.AllOwnersConstraint == allOwnersConstraintMustMatch {
+		switch {
+		case prepDerived && !labelDerived:
+			requirement.LabelOwner, labelDerived = fba_inbound.LabelOwner(requirement.PrepOwner), true
+		case labelDerived && !prepDerived:
+			requirement.PrepOwner, prepDerived = fba_inbound.PrepOwner(requirement.LabelOwner), true
+		}
+	}
+	return requirement, prepDerived || labelDerived
+}
+
+func readLegacyPrepRequirements(r io.Reader) (PrepRequirements, error) {
+	var requirements PrepRequirements
+	if err := json.NewDecoder(r).Decode(&requirements); err != nil {
+		return nil, fmt.Errorf("failed to parse item requirements json: %w", err)
+	}
+	for msku, requirement := range requirements {
+		if requirement.PrepOwner == "" {
+			requirement.PrepOwner = fba_inbound.NONE
+		}
+		if requirement.LabelOwner == "" {
+			requirement.LabelOwner = fba_inbound.LabelOwnerNONE
+		}
+		requirements[msku] = requirement
+	}
+	return requirements, nil
+}
+
+// readPrepRequirementsCSV reads a csv with msku (or sku), prep_owner and label_owner columns, in any order.
+func readPrepRequirementsCSV(r io.Reader) (PrepRequirements, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv is empty")
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+		switch name {
+		case "msku", "sku", "seller_sku":
+			columns["msku"] = i
+		case "prep_owner", "prepowner":
+			columns["prep_owner"] = i
+		case "label_owner", "labelowner":
+			columns["label_owner"] = i
+		}
+	}
+	if _, ok := columns["msku"]; !ok {
+		return nil, errors.New("csv header must have a msku column")
+	}
+	requirements := make(PrepRequirements, len(records)-1)
+	for line, record := range records[1:] {
+		msku := strings.TrimSpace(record[columns["msku"]])
+		if msku == "" {
+			continue
+		}
+		requirement := requirements.get(msku)
+		if i, ok := columns["prep_owner"]; ok && strings.TrimSpace(record[i]) != "" {
+			if requirement.PrepOwner, err = parsePrepOwner(record[i]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line+2, err)
+			}
+		}
+		if i, ok := columns["label_owner"]; ok && strings.TrimSpace(record[i]) != "" {
+			if requirement.LabelOwner, err = parseLabelOwner(record[i]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line+2, err)
+			}
+		}
+		requirements[msku] = requirement
+	}
+	return requirements, nil
+}
+
+func parsePrepOwner(value string) (fba_inbound.PrepOwner, error) {
+	owner := fba_inbound.PrepOwner(strings.ToUpper(strings.TrimSpace(value)))
+	switch owner {
+	case fba_inbound.AMAZON, fba_inbound.SELLER, fba_inbound.NONE:
+		return owner, nil
+	}
+	return "", fmt.Errorf("invalid prep owner %s, must be AMAZON, SELLER or NONE", value)
+}
+
+func parseLabelOwner(value string) (fba_inbound.LabelOwner, error) {
+	owner := fba_inbound.LabelOwner(strings.ToUpper(strings.TrimSpace(value)))
+	switch owner {
+	case fba_inbound.LabelOwnerAMAZON, fba_inbound.LabelOwnerSELLER, fba_inbound.LabelOwnerNONE:
+		return owner, nil
+	}
+	return "", fmt.Errorf("invalid label owner %s, must be AMAZON, SELLER or NONE", value)
+}
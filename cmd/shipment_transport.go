@@ -0,0 +1,423 @@
+package cmd
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/caner-cetin/halycon/internal/export"
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type shipmentTransportConfig struct {
+	Policy      string
+	ReadyToShip string
+	Export      exportConfig
+}
+
+var (
+	shipmentTransportCmd = &cobra.Command{
+		Use:   "transport [inbound plan id]",
+		Short: "compares partnered and non-partnered transportation options of every shipment, and confirms the selected ones",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(shipmentTransport, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound}}),
+	}
+	shipmentTransportCfg shipmentTransportConfig
+)
+
+func getShipmentTransportCmd() *cobra.Command {
+	flags := shipmentTransportCmd.PersistentFlags()
+	flags.StringVar(&shipmentTransportCfg.Policy, "policy", "", fmt.Sprintf("select options without prompting (%s)", strings.Join(transportPolicies, ", ")))
+	flags.StringVar(&shipmentTransportCfg.ReadyToShip, "ready-to-ship", "", "date the shipments are ready to be picked up, YYYY-MM-DD (default tomorrow)")
+	addExportFlags(flags, &shipmentTransportCfg.Export, "transportation_quotes")
+	return shipmentTransportCmd
+}
+
+const (
+	transportPolicyCheapest = "cheapest"
+	transportPolicyFastest  = "fastest"
+	// partneredShippingSolution is the shipping solution of Amazon partnered carriers, other options use the seller's own carrier.
+	partneredShippingSolution = "AMAZON_PARTNERED_CARRIER"
+)
+
+var transportPolicies = []string{transportPolicyCheapest, transportPolicyFastest}
+
+// transportationQuote is a transportation option with the delivery windows of its shipment, if the option requires one to be confirmed.
+type transportationQuote struct {
+	Option   fba_inbound.TransportationOption
+	Windows  []fba_inbound.DeliveryWindowOption
+	Selected bool
+}
+
+func (q transportationQuote) Partnered() bool {
+	return q.Option.ShippingSolution == partneredShippingSolution
+}
+
+// Cost returns the quoted cost, options of non-partnered carriers are not quoted by Amazon.
+func (q transportationQuote) Cost() (float64, bool) {
+	if q.Option.Quote == nil {
+		return 0, false
+	}
+	return float64(q.Option.Quote.Cost.Amount), true
+}
+
+// Window returns the earliest delivery window for options that require one, and the carrier appointment for others.
+func (q transportationQuote) Window() (time.Time, time.Time, bool) {
+	if slices.Contains(q.Option.Preconditions, confirmedDeliveryWindowPrecondition) {
+		if len(q.Windows) == 0 {
+			return time.Time{}, time.Time{}, false
+		}
+		return q.Windows[0].StartDate, q.Windows[0].EndDate, true
+	}
+	if q.Option.CarrierAppointment != nil {
+		return q.Option.CarrierAppointment.StartTime, q.Option.CarrierAppointment.EndTime, true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func (q transportationQuote) carrier() string {
+	return cmp.Or(internal.NullString(q.Option.Carrier.Name).String, internal.NullString(q.Option.Carrier.AlphaCode).String, "-")
+}
+
+func shipmentTransport(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	inboundPlanId := args[0]
+	if shipmentTransportCfg.Policy != "" && !slices.Contains(transportPolicies, shipmentTransportCfg.Policy) {
+		log.Error().Str("policy", shipmentTransportCfg.Policy).Msgf("policy must be one of %s", strings.Join(transportPolicies, ", "))
+		return
+	}
+	readyToShip, err := readyToShipDate(shipmentTransportCfg.ReadyToShip)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid ready to ship date")
+		return
+	}
+	plan, err := getOrTrackInboundPlan(app, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to get inbound plan")
+		return
+	}
+	step := inboundWorkflowStepIndex(plan.Step)
+	if plan.Step == inboundStepCancelled || step < inboundWorkflowStepIndex(inboundStepPlacementConfirmed) {
+		log.Error().Str("step", plan.Step).Msg("placement of the inbound plan is not confirmed yet, continue it with shipment workflow first")
+		return
+	}
+	if step >= inboundWorkflowStepIndex(inboundStepTransportationConfirmed) {
+		log.Error().Str("step", plan.Step).Msg("transportation of the inbound plan is already confirmed")
+		return
+	}
+	shipments, err := app.Query.GetInboundPlanShipments(app.Ctx, plan.InboundPlanID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get shipments")
+		return
+	}
+	if len(shipments) == 0 {
+		log.Error().Str("inbound_plan_id", inboundPlanId).Msg("inbound plan has no shipments, rerun shipment workflow with --from placement_confirmed")
+		return
+	}
+
+	fmt.Println(color.CyanString("generating transportation options, ready to ship on %s", readyToShip.Local().Format(time.DateOnly)))
+	options, err := generateTransportationOptions(app, plan, shipments, readyToShip)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate transportation options")
+		return
+	}
+	quotes := make(map[string][]transportationQuote, len(shipments))
+	for _, shipment := range shipments {
+		quotes[shipment.ShipmentID], err = shipmentTransportationQuotes(app, plan.InboundPlanID, shipment.ShipmentID, options)
+		if err != nil {
+			log.Error().Err(err).Str("shipment_id", shipment.ShipmentID).Msg("failed to get delivery windows")
+			return
+		}
+	}
+
+	for _, shipment := range shipments {
+		shipmentQuotes := quotes[shipment.ShipmentID]
+		displayTransportationQuotes(shipment, shipmentQuotes)
+		i, err := selectTransportationQuote(shipment.ShipmentID, shipmentQuotes, shipmentTransportCfg.Policy)
+		if err != nil {
+			log.Error().Err(err).Str("shipment_id", shipment.ShipmentID).Msg("failed to select transportation option")
+			return
+		}
+		shipmentQuotes[i].Selected = true
+		if err := app.Query.UpdateInboundPlanShipmentTransportationOption(app.Ctx, db.UpdateInboundPlanShipmentTransportationOptionParams{
+			TransportationOptionID: sql.NullString{String: shipmentQuotes[i].Option.TransportationOptionId, Valid: true},
+			InboundPlanID:          plan.InboundPlanID,
+			ShipmentID:             shipment.ShipmentID,
+		}); err != nil {
+			log.Error().Err(err).Msg("failed to record transportation option")
+			return
+		}
+	}
+	if shipmentTransportCfg.Export.Enabled() {
+		if err := writeExport(shipmentTransportCfg.Export, "transportation_quotes", transportationQuotesTable(shipments, quotes)); err != nil {
+			log.Error().Err(err).Msg("failed to export transportation quotes")
+		}
+	}
+	if !recordInboundPlanStep(app, plan.InboundPlanID, inboundStepTransportationSelected) {
+		return
+	}
+
+	for _, shipment := range shipments {
+		i := slices.IndexFunc(quotes[shipment.ShipmentID], func(q transportationQuote) bool { return q.Selected })
+		quote := quotes[shipment.ShipmentID][i]
+		if !slices.Contains(quote.Option.Preconditions, confirmedDeliveryWindowPrecondition) {
+			continue
+		}
+		if err := confirmShipmentDeliveryWindow(app, plan.InboundPlanID, shipment.ShipmentID, quote.Windows, shipmentTransportCfg.Policy); err != nil {
+			log.Error().Err(err).Str("shipment_id", shipment.ShipmentID).Msg("failed to confirm delivery window")
+			return
+		}
+	}
+	if !recordInboundPlanStep(app, plan.InboundPlanID, inboundStepDeliveryWindowsConfirmed) {
+		return
+	}
+
+	plan, err = app.Query.GetInboundPlan(app.Ctx, plan.InboundPlanID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get inbound plan")
+		return
+	}
+	if err := confirmTransportationOptionsStep(app, plan); err != nil {
+		log.Error().Err(err).Msg("failed to confirm transportation options")
+		return
+	}
+	if !recordInboundPlanStep(app, plan.InboundPlanID, inboundStepTransportationConfirmed) {
+		return
+	}
+	color.Green("transportation of inbound plan %s is confirmed", plan.InboundPlanID)
+	fmt.Printf("Fetch labels with: halycon shipment workflow %s\n", plan.InboundPlanID)
+}
+
+func recordInboundPlanStep(app AppCtx, inboundPlanId string, step string) bool {
+	if err := app.Query.UpdateInboundPlanStep(app.Ctx, db.UpdateInboundPlanStepParams{Step: step, UpdatedAt: time.Now().UTC(), InboundPlanID: inboundPlanId}); err != nil {
+		log.Error().Err(err).Str("step", step).Msg("failed to record workflow step")
+		return false
+	}
+	return true
+}
+
+// shipmentTransportationQuotes collects the options of a shipment, and generates its delivery windows if any option requires one.
+// Windows are sorted by their end date, so the earliest arrival comes first.
+func shipmentTransportationQuotes(app AppCtx, inboundPlanId string, shipmentId string, options []fba_inbound.TransportationOption) ([]transportationQuote, error) {
+	var quotes []transportationQuote
+	requiresWindow := false
+	for _, option := range options {
+		if option.ShipmentId != shipmentId {
+			continue
+		}
+		quotes = append(quotes, transportationQuote{Option: option})
+		requiresWindow = requiresWindow || slices.Contains(option.Preconditions, confirmedDeliveryWindowPrecondition)
+	}
+	if !requiresWindow {
+		return quotes, nil
+	}
+	generated, err := app.Amazon.Client.GenerateDeliveryWindowOptions(app.Ctx, inboundPlanId, shipmentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate delivery window options: %w", err)
+	}
+	if _, err := waitForPlanOperation(app, inboundPlanId, generated.JSON202.OperationId); err != nil {
+		return nil, err
+	}
+	windows, err := listDeliveryWindowOptions(app, inboundPlanId, shipmentId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delivery window options: %w", err)
+	}
+	slices.SortStableFunc(windows, func(a, b fba_inbound.DeliveryWindowOption) int { return a.EndDate.Compare(b.EndDate) })
+	for i := range quotes {
+		if slices.Contains(quotes[i].Option.Preconditions, confirmedDeliveryWindowPrecondition) {
+			quotes[i].Windows = windows
+		}
+	}
+	return quotes, nil
+}
+
+// displayTransportationQuotes lists partnered options first and non-partnered options after them, both sorted by cost.
+func displayTransportationQuotes(shipment db.InboundPlanShipment, quotes []transportationQuote) {
+	slices.SortStableFunc(quotes, func(a, b transportationQuote) int {
+		if a.Partnered() != b.Partnered() {
+			if a.Partnered() {
+				return -1
+			}
+			return 1
+		}
+		return compareTransportationCost(a, b)
+	})
+	fmt.Printf("\nShipment: %s, warehouse %s\n", shipment.ShipmentID, valueOrDash(shipment.DestinationWarehouse.String))
+	fmt.Printf("%-14s %-24s %-20s %14s %-25s %s\n", "Type", "Carrier", "Mode", "Cost", "Window", "Quote Expires")
+	fmt.Println(color.HiBlackString("%s", "-------------------------------------------------------------------------------------------------------------------"))
+	for _, quote := range quotes {
+		kind := fmt.Sprintf("%-14s", "Non-partnered")
+		if quote.Partnered() {
+			kind = color.CyanString("%-14s", "Partnered")
+		}
+		fmt.Printf("%s %-24s %-20s %14s %-25s %s\n", kind, truncateString(quote.carrier(), 24), quote.Option.ShippingMode, formatTransportationCost(quote), formatTransportationWindow(quote), formatQuoteExpiration(quote))
+	}
+}
+
+func formatTransportationCost(quote transportationQuote) string {
+	if quote.Option.Quote == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f %s", quote.Option.Quote.Cost.Amount, quote.Option.Quote.Cost.Code)
+}
+
+func formatTransportationWindow(quote transportationQuote) string {
+	start, end, ok := quote.Window()
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%s - %s", start.Local().Format(time.DateOnly), end.Local().Format(time.DateOnly))
+}
+
+func formatQuoteExpiration(quote transportationQuote) string {
+	if quote.Option.Quote == nil || quote.Option.Quote.Expiration == nil {
+		return "-"
+	}
+	return quote.Option.Quote.Expiration.Local().Format(time.DateTime)
+}
+
+// compareTransportationCost orders quoted options by cost, options without a quote come last.
+func compareTransportationCost(a, b transportationQuote) int {
+	aCost, aOk := a.Cost()
+	bCost, bOk := b.Cost()
+	if aOk != bOk {
+		if aOk {
+			return -1
+		}
+		return 1
+	}
+	return cmp.Compare(aCost, bCost)
+}
+
+// compareTransportationArrival orders options by the end of their earliest window, options without a window come last.
+func compareTransportationArrival(a, b transportationQuote) int {
+	_, aEnd, aOk := a.Window()
+	_, bEnd, bOk := b.Window()
+	if aOk != bOk {
+		if aOk {
+			return -1
+		}
+		return 1
+	}
+	return aEnd.Compare(bEnd)
+}
+
+// selectTransportationQuote returns the index of the selected quote, chosen by policy or prompted for.
+func selectTransportationQuote(shipmentId string, quotes []transportationQuote, policy string) (int, error) {
+	if len(quotes) == 0 {
+		return 0, errors.New("no transportation options available")
+	}
+	switch policy {
+	case transportPolicyCheapest:
+		best := 0
+		for i := range quotes {
+			if c := compareTransportationCost(quotes[i], quotes[best]); c < 0 || (c == 0 && compareTransportationArrival(quotes[i], quotes[best]) < 0) {
+				best = i
+			}
+		}
+		fmt.Printf("  %s %s\n", color.HiBlackString("cheapest:"), formatTransportationOption(quotes[best].Option))
+		return best, nil
+	case transportPolicyFastest:
+		best := 0
+		for i := range quotes {
+			if c := compareTransportationArrival(quotes[i], quotes[best]); c < 0 || (c == 0 && compareTransportationCost(quotes[i], quotes[best]) < 0) {
+				best = i
+			}
+		}
+		fmt.Printf("  %s %s\n", color.HiBlackString("fastest:"), formatTransportationOption(quotes[best].Option))
+		return best, nil
+	}
+	choices := make([]huh.Option[string], 0, len(quotes))
+	for _, quote := range quotes {
+		key := formatTransportationOption(quote.Option)
+		if window := formatTransportationWindow(quote); window != "-" {
+			key += ", " + window
+		}
+		choices = append(choices, huh.NewOption(key, quote.Option.TransportationOptionId))
+	}
+	transportationOptionId, err := selectInboundOption(fmt.Sprintf("Transportation Option for %s", shipmentId), choices)
+	if err != nil {
+		return 0, err
+	}
+	return slices.IndexFunc(quotes, func(q transportationQuote) bool { return q.Option.TransportationOptionId == transportationOptionId }), nil
+}
+
+// confirmShipmentDeliveryWindow confirms the earliest window with a policy, or prompts for one, and records it.
+func confirmShipmentDeliveryWindow(app AppCtx, inboundPlanId string, shipmentId string, windows []fba_inbound.DeliveryWindowOption, policy string) error {
+	if len(windows) == 0 {
+		return errors.New("no delivery windows available")
+	}
+	deliveryWindowOptionId := windows[0].DeliveryWindowOptionId
+	if policy == "" {
+		var choices []huh.Option[string]
+		for _, window := range windows {
+			key := fmt.Sprintf("%s - %s (%s)", window.StartDate.Local().Format(time.DateOnly), window.EndDate.Local().Format(time.DateOnly), window.AvailabilityType)
+			choices = append(choices, huh.NewOption(key, window.DeliveryWindowOptionId))
+		}
+		var err error
+		if deliveryWindowOptionId, err = selectInboundOption(fmt.Sprintf("Delivery Window for %s", shipmentId), choices); err != nil {
+			return err
+		}
+	}
+	confirmed, err := app.Amazon.Client.ConfirmDeliveryWindowOptions(app.Ctx, inboundPlanId, shipmentId, deliveryWindowOptionId)
+	if err != nil {
+		return err
+	}
+	if _, err := waitForPlanOperation(app, inboundPlanId, confirmed.JSON202.OperationId); err != nil {
+		return err
+	}
+	return app.Query.UpdateInboundPlanShipmentDeliveryWindowOption(app.Ctx, db.UpdateInboundPlanShipmentDeliveryWindowOptionParams{
+		DeliveryWindowOptionID: sql.NullString{String: deliveryWindowOptionId, Valid: true},
+		InboundPlanID:          inboundPlanId,
+		ShipmentID:             shipmentId,
+	})
+}
+
+func transportationQuotesTable(shipments []db.InboundPlanShipment, quotes map[string][]transportationQuote) export.Table {
+	table := export.Table{
+		Name: "transportation_quotes",
+		Columns: []export.Column{
+			{Name: "Shipment ID", Key: "shipment_id", Type: export.String},
+			{Name: "Transportation Option ID", Key: "transportation_option_id", Type: export.String},
+			{Name: "Partnered", Key: "partnered", Type: export.Bool},
+			{Name: "Carrier", Key: "carrier", Type: export.String},
+			{Name: "Shipping Mode", Key: "shipping_mode", Type: export.String},
+			{Name: "Shipping Solution", Key: "shipping_solution", Type: export.String},
+			{Name: "Cost", Key: "cost", Type: export.Float},
+			{Name: "Currency", Key: "currency", Type: export.String},
+			{Name: "Quote Expiration", Key: "quote_expiration", Type: export.Time},
+			{Name: "Window Start", Key: "window_start", Type: export.Time},
+			{Name: "Window End", Key: "window_end", Type: export.Time},
+			{Name: "Selected", Key: "selected", Type: export.Bool},
+		},
+	}
+	for _, shipment := range shipments {
+		for _, quote := range quotes[shipment.ShipmentID] {
+			var cost, currency, expiration, windowStart, windowEnd any
+			if quote.Option.Quote != nil {
+				cost, currency = float64(quote.Option.Quote.Cost.Amount), quote.Option.Quote.Cost.Code
+				if quote.Option.Quote.Expiration != nil {
+					expiration = *quote.Option.Quote.Expiration
+				}
+			}
+			if start, end, ok := quote.Window(); ok {
+				windowStart, windowEnd = start, end
+			}
+			table.Rows = append(table.Rows, []any{
+				shipment.ShipmentID, quote.Option.TransportationOptionId, quote.Partnered(), quote.carrier(), quote.Option.ShippingMode,
+				quote.Option.ShippingSolution, cost, currency, expiration, windowStart, windowEnd, quote.Selected,
+			})
+		}
+	}
+	return table
+}
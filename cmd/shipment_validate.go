@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_eligibility"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/amazon/listings"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type validateShipmentConfig struct {
+	Input    string
+	ShipFrom string
+}
+
+var (
+	validateShipmentCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "checks a shipment create input for problems that would fail the inbound plan",
+		Run:   WrapCommandWithResources(validateShipment, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound, ServiceCatalog, ServiceListings, ServiceFBAEligibility}}),
+	}
+	validateShipmentCfg validateShipmentConfig
+)
+
+func getValidateShipmentCmd() *cobra.Command {
+	flags := validateShipmentCmd.PersistentFlags()
+	flags.StringVarP(&validateShipmentCfg.Input, "input", "i", "", "shipment create input")
+	flags.StringVar(&validateShipmentCfg.ShipFrom, "ship-from", "", "name or company name of the ship from address in config (default: the default ship from address)")
+	validateShipmentCmd.MarkPersistentFlagRequired("input")
+	return validateShipmentCmd
+}
+
+// Severities of validation problems, errors stop `shipment create`, warnings are only displayed.
+const (
+	validationError   = "error"
+	validationWarning = "warning"
+)
+
+// Checks run by shipment validation, in the order they are displayed.
+const (
+	validationCheckInput       = "input"
+	validationCheckInventory   = "inventory"
+	validationCheckListing     = "listing"
+	validationCheckPrep        = "prep"
+	validationCheckEligibility = "eligibility"
+)
+
+// fulfillmentChannelMerchant is the fulfillment channel code of merchant fulfilled listings, FBA channels are AMAZON_NA, AMAZON_EU and so on.
+const fulfillmentChannelMerchant = "DEFAULT"
+
+// prepCategoryUnknown is the prep category of items whose prep is not set up yet, plans with these items are rejected.
+const prepCategoryUnknown = "UNKNOWN"
+
+// ineligibilityReasons describes the most common inbound ineligibility codes, other codes are displayed as they are.
+var ineligibilityReasons = map[string]string{
+	"FBA_INB_0004":           "missing package dimensions",
+	"FBA_INB_0006":           "SKU is unknown or cannot be found",
+	"FBA_INB_0007":           "under dangerous goods (hazmat) review",
+	"FBA_INB_0050":           "no fulfillment center in the destination country can receive this product",
+	"FBA_INB_0097":           "fully regulated dangerous good (hazmat)",
+	"FBA_INB_0098":           "not authorized to send this item to the destination marketplace",
+	"UNKNOWN_INB_ERROR_CODE": "unknown ineligibility reason",
+}
+
+type shipmentValidationProblem struct {
+	MSKU          string
+	MarketplaceID string
+	Severity      string
+	Check         string
+	Problem       string
+}
+
+func validateShipment(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	shipFrom := cfg.Amazon.FBA.DefaultShipFrom
+	if validateShipmentCfg.ShipFrom != "" {
+		var err error
+		if shipFrom, err = findShipFrom(validateShipmentCfg.ShipFrom); err != nil {
+			log.Error().Err(err).Send()
+			return
+		}
+	}
+	groups, err := readShipmentInput(app, validateShipmentCfg.Input, shipFrom)
+	if err != nil {
+		log.Error().Err(err).Str("file", validateShipmentCfg.Input).Msg("failed to read shipment input")
+		return
+	}
+	problems, err := validateShipmentGroups(app, groups)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to validate shipment")
+		return
+	}
+	if errorCount := displayValidationProblems(problems); errorCount > 0 {
+		log.Error().Int("errors", errorCount).Msg("shipment has problems that would fail the inbound plan")
+		return
+	}
+	color.Green("every item passed validation")
+}
+
+// validateShipmentGroups checks every item of the groups against the local inventory cache, its listing, prep details and
+// inbound eligibility, and returns every problem found instead of stopping at the first one.
+func validateShipmentGroups(app AppCtx, groups []shipmentInputGroup) ([]shipmentValidationProblem, error) {
+	index, err := loadInventoryIdentifiers(app)
+	if err != nil {
+		return nil, err
+	}
+	var problems []shipmentValidationProblem
+	eligibility := map[[2]string][]string{}
+	for _, group := range groups {
+		marketplaceId := group.marketplaceId()
+		report := func(msku string, severity string, check string, format string, a ...any) {
+			problems = append(problems, shipmentValidationProblem{MSKU: msku, MarketplaceID: marketplaceId, Severity: severity, Check: check, Problem: fmt.Sprintf(format, a...)})
+		}
+
+		mskus := make([]string, 0, len(group.Rows))
+		for _, row := range group.Rows {
+			mskus = append(mskus, row.SKU)
+			if len(row.lines) > 1 {
+				lines := make([]string, 0, len(row.lines))
+				for _, line := range row.lines {
+					lines = append(lines, strconv.Itoa(line))
+				}
+				report(row.SKU, validationWarning, validationCheckInput, "listed on rows %s, quantities are merged into %d", strings.Join(lines, ", "), row.Quantity)
+			}
+			if _, ok := index.products[row.SKU]; !ok {
+				report(row.SKU, validationWarning, validationCheckInventory, "not in the local inventory cache, run `halycon inventory build` if the listing is new")
+			}
+		}
+
+		for i, row := range group.Rows {
+			asin, listingProblems := validateListing(app, marketplaceId, row.SKU)
+			for _, problem := range listingProblems {
+				report(row.SKU, problem[0], validationCheckListing, "%s", problem[1])
+			}
+			asin = cmp.Or(row.ASIN, asin)
+			if asin == "" {
+				report(row.SKU, validationWarning, validationCheckEligibility, "ASIN is unknown, eligibility is not checked")
+				continue
+			}
+			group.Rows[i].ASIN = asin
+			key := [2]string{marketplaceId, asin}
+			reasons, checked := eligibility[key]
+			if !checked {
+				if reasons, err = itemIneligibilityReasons(app, marketplaceId, asin); err != nil {
+					report(row.SKU, validationWarning, validationCheckEligibility, "failed to check eligibility of %s: %s", asin, err)
+					continue
+				}
+				eligibility[key] = reasons
+			}
+			for _, reason := range reasons {
+				report(row.SKU, validationError, validationCheckEligibility, "%s is not eligible for inbound: %s", asin, reason)
+			}
+		}
+
+		requirements, err := loadPrepRequirements(app, marketplaceId)
+		if err != nil {
+			return nil, err
+		}
+		for batch := range slices.Chunk(mskus, listPrepDetailsMaxMskus) {
+			resp, err := app.Amazon.Client.ListPrepDetails(app.Ctx, &fba_inbound.ListPrepDetailsParams{MarketplaceId: marketplaceId, Mskus: batch})
+			if err != nil {
+				for _, msku := range batch {
+					report(msku, validationWarning, validationCheckPrep, "failed to list prep details: %s", err)
+				}
+				continue
+			}
+			found := make(map[string]bool, len(batch))
+			for _, detail := range resp.JSON200.MskuPrepDetails {
+				found[detail.Msku] = true
+				if detail.PrepCategory == prepCategoryUnknown {
+					report(detail.Msku, validationError, validationCheckPrep, "prep category is not set, set it in Seller Central before sending the item")
+				}
+				existing := requirements.get(detail.Msku)
+				if existing.Source != itemRequirementSourceManual && existing.Source != itemRequirementSourceImport {
+					// learned requirements are refreshed from prep details by shipment create
+					continue
+				}
+				if required, derived := requirementsFromPrepDetail(detail, existing); derived && required != existing {
+					report(detail.Msku, validationError, validationCheckPrep, "stored prep owner %s and label owner %s conflict with prep details, which require %s and %s, fix them with `halycon shipment requirements set`",
+						valueOrDash(string(existing.PrepOwner)), valueOrDash(string(existing.LabelOwner)), valueOrDash(string(required.PrepOwner)), valueOrDash(string(required.LabelOwner)))
+				}
+			}
+			for _, msku := range batch {
+				if !found[msku] {
+					report(msku, validationWarning, validationCheckPrep, "no prep details returned")
+				}
+			}
+		}
+	}
+	return problems, nil
+}
+
+// validateListing checks that the listing of sku exists in the marketplace and is fulfilled by Amazon, returning its ASIN and
+// the problems found as severity and message pairs.
+func validateListing(app AppCtx, marketplaceId string, sku string) (string, [][2]string) {
+	var params listings.GetListingsItemParams
+	params.MarketplaceIds = []string{marketplaceId}
+	params.IncludedData = &[]listings.GetListingsItemParamsIncludedData{"summaries", "issues", "fulfillmentAvailability"}
+	params.IssueLocale = internal.Ptr(cfg.Amazon.DefaultLanguageTag)
+	resp, err := app.Amazon.Client.GetListingsItem(app.Ctx, &params, cfg.Amazon.Auth.DefaultMerchant.SellerToken, sku)
+	if err != nil {
+		if resp != nil && resp.HTTPResponse != nil && resp.HTTPResponse.StatusCode == http.StatusNotFound {
+			return "", [][2]string{{validationError, "no listing with this SKU in the marketplace"}}
+		}
+		return "", [][2]string{{validationWarning, fmt.Sprintf("failed to get listing: %s", err)}}
+	}
+	listing := resp.JSON200
+	var asin string
+	var problems [][2]string
+	if listing.Summaries != nil {
+		for _, summary := range *listing.Summaries {
+			if summary.MarketplaceId == marketplaceId && summary.Asin != nil {
+				asin = *summary.Asin
+			}
+		}
+	}
+	var channels []string
+	if listing.FulfillmentAvailability != nil {
+		for _, availability := range *listing.FulfillmentAvailability {
+			channels = append(channels, availability.FulfillmentChannelCode)
+		}
+	}
+	if !slices.ContainsFunc(channels, func(channel string) bool { return channel != fulfillmentChannelMerchant }) {
+		problems = append(problems, [2]string{validationError, fmt.Sprintf("listing is not fulfilled by Amazon (fulfillment channels: %s), convert it to FBA first", valueOrDash(strings.Join(channels, ", ")))})
+	}
+	if listing.Issues != nil {
+		for _, issue := range *listing.Issues {
+			if issue.Severity == "ERROR" {
+				problems = append(problems, [2]string{validationWarning, fmt.Sprintf("listing issue %s: %s", issue.Code, issue.Message)})
+			}
+		}
+	}
+	return asin, problems
+}
+
+// itemIneligibilityReasons returns why an ASIN cannot be sent to FBA in the marketplace, empty if it is eligible.
+func itemIneligibilityReasons(app AppCtx, marketplaceId string, asin string) ([]string, error) {
+	resp, err := app.Amazon.Client.GetItemEligibilityPreview(app.Ctx, &fba_eligibility.GetItemEligibilityPreviewParams{
+		MarketplaceIds: &[]string{marketplaceId},
+		Asin:           asin,
+		Program:        fba_eligibility.INBOUND,
+	})
+	if err != nil {
+		return nil, err
+	}
+	preview := resp.JSON200.Payload
+	if preview == nil || preview.IsEligibleForProgram {
+		return nil, nil
+	}
+	if preview.IneligibilityReasonList == nil || len(*preview.IneligibilityReasonList) == 0 {
+		return []string{ineligibilityReasons["UNKNOWN_INB_ERROR_CODE"]}, nil
+	}
+	var reasons []string
+	for _, code := range *preview.IneligibilityReasonList {
+		if description, ok := ineligibilityReasons[string(code)]; ok {
+			reasons = append(reasons, fmt.Sprintf("%s (%s)", description, code))
+		} else {
+			reasons = append(reasons, string(code))
+		}
+	}
+	return reasons, nil
+}
+
+// displayValidationProblems prints problems grouped by SKU and returns the number of errors among them.
+func displayValidationProblems(problems []shipmentValidationProblem) int {
+	if len(problems) == 0 {
+		return 0
+	}
+	checks := []string{validationCheckInput, validationCheckInventory, validationCheckListing, validationCheckPrep, validationCheckEligibility}
+	slices.SortStableFunc(problems, func(a, b shipmentValidationProblem) int {
+		return cmp.Or(
+			cmp.Compare(a.MSKU, b.MSKU),
+			cmp.Compare(a.MarketplaceID, b.MarketplaceID),
+			cmp.Compare(slices.Index(checks, a.Check), slices.Index(checks, b.Check)),
+		)
+	})
+	fmt.Printf("%-30s %-16s %-8s %-12s %s\n", "MSKU", "Marketplace", "Severity", "Check", "Problem")
+	fmt.Println(color.HiBlackString("%s", "--------------------------------------------------------------------------------------------------------------"))
+	errorCount := 0
+	for _, problem := range problems {
+		severity := color.YellowString("%-8s", problem.Severity)
+		if problem.Severity == validationError {
+			severity = color.RedString("%-8s", problem.Severity)
+			errorCount++
+		}
+		fmt.Printf("%-30s %-16s %s %-12s %s\n", truncateString(problem.MSKU, 30), problem.MarketplaceID, severity, problem.Check, problem.Problem)
+	}
+	fmt.Printf("%d errors, %d warnings\n", errorCount, len(problems)-errorCount)
+	return errorCount
+}
@@ -0,0 +1,751 @@
+package cmd
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound_v0"
+	"github.com/caner-cetin/halycon/internal/db"
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+type shipmentWorkflowConfig struct {
+	From        string
+	PackingInfo string
+	ReadyToShip string
+}
+
+// Steps of the Send-to-Amazon workflow, recorded in the inbound_plan table once they succeed.
+const (
+	inboundStepCreated                  = "created"
+	inboundStepPackingConfirmed         = "packing_confirmed"
+	inboundStepPackingInformationSet    = "packing_information_set"
+	inboundStepPlacementConfirmed       = "placement_confirmed"
+	inboundStepTransportationSelected   = "transportation_selected"
+	inboundStepDeliveryWindowsConfirmed = "delivery_windows_confirmed"
+	inboundStepTransportationConfirmed  = "transportation_confirmed"
+	inboundStepCompleted                = "completed"
+	// inboundStepCancelled is recorded for cancelled plans, the workflow cannot continue them.
+	inboundStepCancelled = "cancelled"
+)
+
+const (
+	inboundPageSize = 20
+	// confirmedDeliveryWindowPrecondition is listed in transportation options that require a delivery window to be confirmed first.
+	confirmedDeliveryWindowPrecondition = "CONFIRMED_DELIVERY_WINDOW"
+)
+
+// errWorkflowInputRequired stops the workflow without failing it, for steps that need input from the user.
+var errWorkflowInputRequired = errors.New("input required")
+
+type inboundWorkflowStep struct {
+	// Name is recorded as the step of the inbound plan once Run succeeds.
+	Name        string
+	Description string
+	Run         func(app AppCtx, plan db.InboundPlan) error
+}
+
+var inboundWorkflowSteps = []inboundWorkflowStep{
+	{Name: inboundStepPackingConfirmed, Description: "generating and confirming packing options", Run: confirmPackingOptionStep},
+	{Name: inboundStepPackingInformationSet, Description: "setting packing information", Run: setPackingInformationStep},
+	{Name: inboundStepPlacementConfirmed, Description: "generating and confirming placement options", Run: confirmPlacementOptionStep},
+	{Name: inboundStepTransportationSelected, Description: "generating and selecting transportation options", Run: selectTransportationOptionsStep},
+	{Name: inboundStepDeliveryWindowsConfirmed, Description: "confirming delivery windows", Run: confirmDeliveryWindowsStep},
+	{Name: inboundStepTransportationConfirmed, Description: "confirming transportation options", Run: confirmTransportationOptionsStep},
+	{Name: inboundStepCompleted, Description: "fetching shipment confirmations and labels", Run: fetchShipmentLabelsStep},
+}
+
+var (
+	shipmentWorkflowCmd = &cobra.Command{
+		Use:   "workflow [inbound plan id]",
+		Short: "drives an inbound plan through packing, placement, transportation and labels, resuming from the last successful step",
+		Args:  cobra.ExactArgs(1),
+		Run:   WrapCommandWithResources(shipmentWorkflow, ResourceConfig{Resources: []ResourceType{ResourceAmazon, ResourceDB}, Services: []ServiceType{ServiceFBAInbound, ServiceFBAInboundV0}}),
+	}
+	shipmentWorkflowCfg shipmentWorkflowConfig
+)
+
+func getShipmentWorkflowCmd() *cobra.Command {
+	stepNames := make([]string, 0, len(inboundWorkflowSteps))
+	for _, step := range inboundWorkflowSteps {
+		stepNames = append(stepNames, step.Name)
+	}
+	flags := shipmentWorkflowCmd.PersistentFlags()
+	flags.StringVar(&shipmentWorkflowCfg.From, "from", "", fmt.Sprintf("rerun the workflow starting from given step (%s)", strings.Join(stepNames, ", ")))
+	flags.StringVar(&shipmentWorkflowCfg.PackingInfo, "packing-info", "", "box contents as csv or yaml (per box or case-packed), or a setPackingInformation request json, a csv template is written if not provided")
+	flags.StringVar(&shipmentWorkflowCfg.ReadyToShip, "ready-to-ship", "", "date the shipments are ready to be picked up, YYYY-MM-DD (default tomorrow)")
+	return shipmentWorkflowCmd
+}
+
+func shipmentWorkflow(cmd *cobra.Command, args []string) {
+	app := GetApp(cmd)
+	inboundPlanId := args[0]
+	plan, err := getOrTrackInboundPlan(app, inboundPlanId)
+	if err != nil {
+		log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to get inbound plan")
+		return
+	}
+
+	if plan.Step == inboundStepCancelled {
+		log.Error().Str("inbound_plan_id", inboundPlanId).Msg("inbound plan is cancelled")
+		return
+	}
+	start := inboundWorkflowStepIndex(plan.Step) + 1
+	if shipmentWorkflowCfg.From != "" {
+		start = inboundWorkflowStepIndex(shipmentWorkflowCfg.From)
+		if start == -1 {
+			log.Error().Str("step", shipmentWorkflowCfg.From).Msg("unknown workflow step")
+			return
+		}
+	}
+	if start >= len(inboundWorkflowSteps) {
+		color.Green("inbound plan %s is already completed, use --from to rerun a step", inboundPlanId)
+		return
+	}
+	runInboundWorkflow(app, inboundPlanId, start)
+}
+
+// inboundWorkflowStepIndex returns the index of step in inboundWorkflowSteps, -1 for the created step and unknown steps.
+func inboundWorkflowStepIndex(step string) int {
+	return slices.IndexFunc(inboundWorkflowSteps, func(s inboundWorkflowStep) bool { return s.Name == step })
+}
+
+// runInboundWorkflow runs the workflow steps starting from given index, and records every successful step.
+func runInboundWorkflow(app AppCtx, inboundPlanId string, start int) {
+	for i := start; i < len(inboundWorkflowSteps); i++ {
+		step := inboundWorkflowSteps[i]
+		plan, err := app.Query.GetInboundPlan(app.Ctx, inboundPlanId)
+		if err != nil {
+			log.Error().Err(err).Str("inbound_plan_id", inboundPlanId).Msg("failed to get inbound plan")
+			return
+		}
+		fmt.Printf("%s %s\n", color.CyanString("[%d/%d]", i+1, len(inboundWorkflowSteps)), step.Description)
+		if err := step.Run(app, plan); err != nil {
+			if errors.Is(err, errWorkflowInputRequired) {
+				fmt.Printf("%s %v\n", color.YellowString("Paused:"), err)
+			} else {
+				log.Error().Err(err).Str("step", step.Name).Msg("workflow step failed")
+			}
+			fmt.Printf("Resume with: halycon shipment workflow %s\n", inboundPlanId)
+			return
+		}
+		if err := app.Query.UpdateInboundPlanStep(app.Ctx, db.UpdateInboundPlanStepParams{Step: step.Name, UpdatedAt: time.Now().UTC(), InboundPlanID: inboundPlanId}); err != nil {
+			log.Error().Err(err).Str("step", step.Name).Msg("failed to record workflow step")
+			return
+		}
+	}
+	color.Green("inbound plan %s is completed", inboundPlanId)
+}
+
+// trackInboundPlan starts tracking a newly created inbound plan locally.
+func trackInboundPlan(app AppCtx, inboundPlanId string, name *string, marketplaceId string, sourceAddress any) error {
+	address, err := json.Marshal(sourceAddress)
+	if err != nil {
+		return fmt.Errorf("failed to marshal source address: %w", err)
+	}
+	now := time.Now().UTC()
+	return app.Query.InsertInboundPlan(app.Ctx, db.InsertInboundPlanParams{
+		InboundPlanID: inboundPlanId,
+		Name:          internal.NullString(name),
+		MarketplaceID: sql.NullString{String: marketplaceId, Valid: marketplaceId != ""},
+		Step:          inboundStepCreated,
+		SourceAddress: sql.NullString{String: string(address), Valid: sourceAddress != nil},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+// getOrTrackInboundPlan returns the locally tracked plan. Plans that are not tracked yet (created in Seller Central or before tracking
+// existed) are fetched from the API, and the last step is inferred from accepted packing and placement options.
+func getOrTrackInboundPlan(app AppCtx, inboundPlanId string) (db.InboundPlan, error) {
+	plan, err := app.Query.GetInboundPlan(app.Ctx, inboundPlanId)
+	if err == nil {
+		return plan, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return plan, err
+	}
+
+	resp, err := app.Amazon.Client.GetInboundPlan(app.Ctx, inboundPlanId)
+	if err != nil {
+		return plan, err
+	}
+	remote := resp.JSON200
+	if remote.Status != "ACTIVE" {
+		return plan, fmt.Errorf("inbound plan is %s", remote.Status)
+	}
+	var marketplaceId string
+	if len(remote.MarketplaceIds) > 0 {
+		marketplaceId = remote.MarketplaceIds[0]
+	}
+	if err := trackInboundPlan(app, inboundPlanId, &remote.Name, marketplaceId, remote.SourceAddress); err != nil {
+		return plan, err
+	}
+
+	step := inboundStepCreated
+	now := time.Now().UTC()
+	if remote.PackingOptions != nil {
+		for _, option := range *remote.PackingOptions {
+			if option.Status == "ACCEPTED" {
+				step = inboundStepPackingConfirmed
+				if err := app.Query.UpdateInboundPlanPackingOption(app.Ctx, db.UpdateInboundPlanPackingOptionParams{PackingOptionID: sql.NullString{String: option.PackingOptionId, Valid: true}, UpdatedAt: now, InboundPlanID: inboundPlanId}); err != nil {
+					return plan, err
+				}
+			}
+		}
+	}
+	if remote.Placements != nil {
+		for _, option := range *remote.Placements {
+			if option.Status == "ACCEPTED" {
+				step = inboundStepPlacementConfirmed
+				if err := app.Query.UpdateInboundPlanPlacementOption(app.Ctx, db.UpdateInboundPlanPlacementOptionParams{PlacementOptionID: sql.NullString{String: option.PlacementOptionId, Valid: true}, UpdatedAt: now, InboundPlanID: inboundPlanId}); err != nil {
+					return plan, err
+				}
+			}
+		}
+	}
+	if remote.Shipments != nil && step == inboundStepPlacementConfirmed {
+		for _, shipment := range *remote.Shipments {
+			if err := app.Query.InsertInboundPlanShipment(app.Ctx, db.InsertInboundPlanShipmentParams{InboundPlanID: inboundPlanId, ShipmentID: shipment.ShipmentId}); err != nil {
+				return plan, err
+			}
+		}
+	}
+	if err := app.Query.UpdateInboundPlanStep(app.Ctx, db.UpdateInboundPlanStepParams{Step: step, UpdatedAt: now, InboundPlanID: inboundPlanId}); err != nil {
+		return plan, err
+	}
+	log.Info().Str("inbound_plan_id", inboundPlanId).Str("step", step).Msg("started tracking inbound plan")
+	return app.Query.GetInboundPlan(app.Ctx, inboundPlanId)
+}
+
+// selectInboundOption prompts for one of the options, a single option is selected without prompting.
+func selectInboundOption(title string, options []huh.Option[string]) (string, error) {
+	if len(options) == 0 {
+		return "", errors.New("no options available")
+	}
+	if len(options) == 1 {
+		fmt.Printf("  %s %s\n", color.HiBlackString("only option:"), options[0].Key)
+		return options[0].Value, nil
+	}
+	var selected string
+	if err := huh.NewSelect[string]().Title(title).Options(options...).Value(&selected).Run(); err != nil {
+		return "", fmt.Errorf("failed to select option: %w", err)
+	}
+	return selected, nil
+}
+
+// formatIncentives sums fees and discounts per currency.
+func formatIncentives(fees []fba_inbound.Incentive, discounts []fba_inbound.Incentive) string {
+	sum := func(incentives []fba_inbound.Incentive) string {
+		if len(incentives) == 0 {
+			return "none"
+		}
+		totals := make(map[string]float32)
+		for _, incentive := range incentives {
+			totals[incentive.Value.Code] += incentive.Value.Amount
+		}
+		parts := make([]string, 0, len(totals))
+		for code, amount := range totals {
+			parts = append(parts, fmt.Sprintf("%.2f %s", amount, code))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, " + ")
+	}
+	return fmt.Sprintf("fees %s, discounts %s", sum(fees), sum(discounts))
+}
+
+func listPackingOptions(app AppCtx, inboundPlanId string) ([]fba_inbound.PackingOption, error) {
+	var options []fba_inbound.PackingOption
+	params := &fba_inbound.ListPackingOptionsParams{PageSize: internal.Ptr(inboundPageSize)}
+	for {
+		resp, err := app.Amazon.Client.ListPackingOptions(app.Ctx, inboundPlanId, params)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, resp.JSON200.PackingOptions...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return options, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+func listPlacementOptions(app AppCtx, inboundPlanId string) ([]fba_inbound.PlacementOption, error) {
+	var options []fba_inbound.PlacementOption
+	params := &fba_inbound.ListPlacementOptionsParams{PageSize: internal.Ptr(inboundPageSize)}
+	for {
+		resp, err := app.Amazon.Client.ListPlacementOptions(app.Ctx, inboundPlanId, params)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, resp.JSON200.PlacementOptions...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return options, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+func listPackingGroupItems(app AppCtx, inboundPlanId string, packingGroupId string) ([]fba_inbound.Item, error) {
+	var items []fba_inbound.Item
+	params := &fba_inbound.ListPackingGroupItemsParams{PageSize: internal.Ptr(100)}
+	for {
+		resp, err := app.Amazon.Client.ListPackingGroupItems(app.Ctx, inboundPlanId, packingGroupId, params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.JSON200.Items...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return items, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+// listTransportationOptions lists options of either a placement option or a single shipment.
+func listTransportationOptions(app AppCtx, inboundPlanId string, placementOptionId *string, shipmentId *string) ([]fba_inbound.TransportationOption, error) {
+	var options []fba_inbound.TransportationOption
+	params := &fba_inbound.ListTransportationOptionsParams{PageSize: internal.Ptr(inboundPageSize), PlacementOptionId: placementOptionId, ShipmentId: shipmentId}
+	for {
+		resp, err := app.Amazon.Client.ListTransportationOptions(app.Ctx, inboundPlanId, params)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, resp.JSON200.TransportationOptions...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return options, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+func listDeliveryWindowOptions(app AppCtx, inboundPlanId string, shipmentId string) ([]fba_inbound.DeliveryWindowOption, error) {
+	var options []fba_inbound.DeliveryWindowOption
+	params := &fba_inbound.ListDeliveryWindowOptionsParams{PageSize: internal.Ptr(inboundPageSize)}
+	for {
+		resp, err := app.Amazon.Client.ListDeliveryWindowOptions(app.Ctx, inboundPlanId, shipmentId, params)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, resp.JSON200.DeliveryWindowOptions...)
+		if resp.JSON200.Pagination == nil || resp.JSON200.Pagination.NextToken == nil {
+			return options, nil
+		}
+		params.PaginationToken = resp.JSON200.Pagination.NextToken
+	}
+}
+
+func confirmPackingOptionStep(app AppCtx, plan db.InboundPlan) error {
+	generated, err := app.Amazon.Client.GeneratePackingOptions(app.Ctx, plan.InboundPlanID)
+	if err != nil {
+		return fmt.Errorf("failed to generate packing options: %w", err)
+	}
+	if _, err := waitForPlanOperation(app, plan.InboundPlanID, generated.JSON202.OperationId); err != nil {
+		return err
+	}
+	options, err := listPackingOptions(app, plan.InboundPlanID)
+	if err != nil {
+		return fmt.Errorf("failed to list packing options: %w", err)
+	}
+	var choices []huh.Option[string]
+	for _, option := range options {
+		if option.Status != "OFFERED" {
+			continue
+		}
+		var modes []string
+		for _, configuration := range option.SupportedShippingConfigurations {
+			if configuration.ShippingMode != nil {
+				modes = append(modes, *configuration.ShippingMode)
+			}
+		}
+		key := fmt.Sprintf("%d packing groups, %s, shipping modes: %s", len(option.PackingGroups), formatIncentives(option.Fees, option.Discounts), strings.Join(slices.Compact(modes), "/"))
+		choices = append(choices, huh.NewOption(key, option.PackingOptionId))
+	}
+	packingOptionId, err := selectInboundOption("Packing Option", choices)
+	if err != nil {
+		return err
+	}
+	confirmed, err := app.Amazon.Client.ConfirmPackingOption(app.Ctx, plan.InboundPlanID, packingOptionId)
+	if err != nil {
+		return fmt.Errorf("failed to confirm packing option: %w", err)
+	}
+	if _, err := waitForPlanOperation(app, plan.InboundPlanID, confirmed.JSON202.OperationId); err != nil {
+		return err
+	}
+	return app.Query.UpdateInboundPlanPackingOption(app.Ctx, db.UpdateInboundPlanPackingOptionParams{
+		PackingOptionID: sql.NullString{String: packingOptionId, Valid: true},
+		UpdatedAt:       time.Now().UTC(),
+		InboundPlanID:   plan.InboundPlanID,
+	})
+}
+
+func setPackingInformationStep(app AppCtx, plan db.InboundPlan) error {
+	groups, err := listConfirmedPackingGroups(app, plan)
+	if err != nil {
+		return err
+	}
+	if shipmentWorkflowCfg.PackingInfo == "" {
+		path := fmt.Sprintf("box_contents_%s.csv", plan.InboundPlanID)
+		if err := writeBoxContentTemplate(path, groups); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w, fill box contents, dimensions and weights in %s and rerun with --packing-info %s, or suggest them with halycon shipment pack-suggest %s", errWorkflowInputRequired, path, path, plan.InboundPlanID)
+	}
+
+	request, err := readPackingInformation(shipmentWorkflowCfg.PackingInfo, groups)
+	if err != nil {
+		return fmt.Errorf("invalid packing information: %w", err)
+	}
+	if err := validatePackingInformation(request, groups); err != nil {
+		return fmt.Errorf("invalid packing information: %w", err)
+	}
+	resp, err := app.Amazon.Client.SetPackingInformation(app.Ctx, plan.InboundPlanID, request)
+	if err != nil {
+		return fmt.Errorf("failed to set packing information: %w", err)
+	}
+	_, err = waitForPlanOperation(app, plan.InboundPlanID, resp.JSON202.OperationId)
+	return err
+}
+
+func confirmPlacementOptionStep(app AppCtx, plan db.InboundPlan) error {
+	options, err := generatePlacementOptions(app, plan.InboundPlanID)
+	if err != nil {
+		return err
+	}
+	var choices []huh.Option[string]
+	for _, option := range options {
+		key := fmt.Sprintf("%d shipments, %s", len(option.ShipmentIds), formatIncentives(option.Fees, option.Discounts))
+		choices = append(choices, huh.NewOption(key, option.PlacementOptionId))
+	}
+	placementOptionId, err := selectInboundOption("Placement Option", choices)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(options, func(option fba_inbound.PlacementOption) bool { return option.PlacementOptionId == placementOptionId })
+	return confirmPlacementOption(app, plan.InboundPlanID, newPlacementSummary(options[index]), "")
+}
+
+// generatePlacementOptions generates placement options of a plan and returns the offered ones.
+func generatePlacementOptions(app AppCtx, inboundPlanId string) ([]fba_inbound.PlacementOption, error) {
+	generated, err := app.Amazon.Client.GeneratePlacementOptions(app.Ctx, inboundPlanId, fba_inbound.GeneratePlacementOptionsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate placement options: %w", err)
+	}
+	if _, err := waitForPlanOperation(app, inboundPlanId, generated.JSON202.OperationId); err != nil {
+		return nil, err
+	}
+	options, err := listPlacementOptions(app, inboundPlanId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placement options: %w", err)
+	}
+	return slices.DeleteFunc(options, func(option fba_inbound.PlacementOption) bool { return option.Status != "OFFERED" }), nil
+}
+
+// confirmPlacementOption confirms a placement option, replaces the tracked shipments with its shipments and records it in the
+// placement history of the plan with the policy it was selected by, empty for interactive selections.
+func confirmPlacementOption(app AppCtx, inboundPlanId string, placement placementSummary, policy string) error {
+	confirmed, err := app.Amazon.Client.ConfirmPlacementOption(app.Ctx, inboundPlanId, placement.Option.PlacementOptionId)
+	if err != nil {
+		return fmt.Errorf("failed to confirm placement option: %w", err)
+	}
+	if _, err := waitForPlanOperation(app, inboundPlanId, confirmed.JSON202.OperationId); err != nil {
+		return err
+	}
+
+	tx, err := app.DB.BeginTx(app.Ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer internal.Rollback(tx)
+	query := app.Query.WithTx(tx)
+	now := time.Now().UTC()
+	if err := query.UpdateInboundPlanPlacementOption(app.Ctx, db.UpdateInboundPlanPlacementOptionParams{
+		PlacementOptionID: sql.NullString{String: placement.Option.PlacementOptionId, Valid: true},
+		UpdatedAt:         now,
+		InboundPlanID:     inboundPlanId,
+	}); err != nil {
+		return err
+	}
+	if err := query.DeleteInboundPlanShipments(app.Ctx, inboundPlanId); err != nil {
+		return err
+	}
+	for _, shipmentId := range placement.Option.ShipmentIds {
+		if err := query.InsertInboundPlanShipment(app.Ctx, db.InsertInboundPlanShipmentParams{InboundPlanID: inboundPlanId, ShipmentID: shipmentId}); err != nil {
+			return err
+		}
+	}
+	if err := query.UpsertInboundPlanPlacement(app.Ctx, db.UpsertInboundPlanPlacementParams{
+		InboundPlanID:     inboundPlanId,
+		PlacementOptionID: placement.Option.PlacementOptionId,
+		ShipmentCount:     int64(len(placement.Option.ShipmentIds)),
+		Warehouses:        sql.NullString{String: strings.Join(placement.Warehouses, ","), Valid: len(placement.Warehouses) > 0},
+		Fees:              placement.Fees,
+		Discounts:         placement.Discounts,
+		Currency:          sql.NullString{String: placement.Currency, Valid: placement.Currency != ""},
+		Policy:            sql.NullString{String: policy, Valid: policy != ""},
+		ConfirmedAt:       now,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// shipmentSourceAddress returns the stored source address of shipment, or of its plan if the shipment has none.
+// Returns a zero address if neither is stored.
+func shipmentSourceAddress(plan db.InboundPlan, shipment db.InboundPlanShipment) fba_inbound.Address {
+	for _, raw := range []sql.NullString{shipment.SourceAddress, plan.SourceAddress} {
+		if !raw.Valid {
+			continue
+		}
+		var address fba_inbound.Address
+		if err := json.Unmarshal([]byte(raw.String), &address); err == nil && address.Name != "" {
+			return address
+		}
+	}
+	return fba_inbound.Address{}
+}
+
+// shipFromContactInformation returns the contact of the address a shipment is picked up from. Contacts need a name and
+// a phone number, the default ship from address is used if address has none, such as when no source address is stored.
+func shipFromContactInformation(address fba_inbound.Address) *fba_inbound.ContactInformation {
+	if address.Name == "" || address.PhoneNumber == nil || *address.PhoneNumber == "" {
+		shipFrom := cfg.Amazon.FBA.DefaultShipFrom
+		contact := &fba_inbound.ContactInformation{Name: shipFrom.Name, PhoneNumber: shipFrom.PhoneNumber}
+		if shipFrom.Email != "" {
+			contact.Email = &shipFrom.Email
+		}
+		return contact
+	}
+	return &fba_inbound.ContactInformation{Name: address.Name, PhoneNumber: *address.PhoneNumber, Email: address.Email}
+}
+
+// readyToShipDate parses a YYYY-MM-DD ready to ship date, and defaults to tomorrow.
+func readyToShipDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now().Add(24 * time.Hour).UTC(), nil
+	}
+	date, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return date, fmt.Errorf("failed to parse ready to ship date: %w", err)
+	}
+	if !date.After(time.Now()) {
+		return date, errors.New("ready to ship date must be in the future")
+	}
+	return date.UTC(), nil
+}
+
+func formatTransportationOption(option fba_inbound.TransportationOption) string {
+	carrier := "unknown carrier"
+	if option.Carrier.Name != nil {
+		carrier = *option.Carrier.Name
+	} else if option.Carrier.AlphaCode != nil {
+		carrier = *option.Carrier.AlphaCode
+	}
+	quote := "no quote"
+	if option.Quote != nil {
+		quote = fmt.Sprintf("%.2f %s", option.Quote.Cost.Amount, option.Quote.Cost.Code)
+	}
+	return fmt.Sprintf("%s, %s, %s, %s", carrier, option.ShippingMode, option.ShippingSolution, quote)
+}
+
+// generateTransportationOptions generates transportation options of every shipment of the confirmed placement option, and lists them.
+func generateTransportationOptions(app AppCtx, plan db.InboundPlan, shipments []db.InboundPlanShipment, readyToShip time.Time) ([]fba_inbound.TransportationOption, error) {
+	request := fba_inbound.GenerateTransportationOptionsRequest{PlacementOptionId: plan.PlacementOptionID.String}
+	for _, shipment := range shipments {
+		request.ShipmentTransportationConfigurations = append(request.ShipmentTransportationConfigurations, fba_inbound.ShipmentTransportationConfiguration{
+			ShipmentId:         shipment.ShipmentID,
+			ReadyToShipWindow:  fba_inbound.WindowInput{Start: readyToShip},
+			ContactInformation: shipFromContactInformation(shipmentSourceAddress(plan, shipment)),
+		})
+	}
+	generated, err := app.Amazon.Client.GenerateTransportationOptions(app.Ctx, plan.InboundPlanID, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate transportation options: %w", err)
+	}
+	if _, err := waitForPlanOperation(app, plan.InboundPlanID, generated.JSON202.OperationId); err != nil {
+		return nil, err
+	}
+	options, err := listTransportationOptions(app, plan.InboundPlanID, internal.Ptr(plan.PlacementOptionID.String), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transportation options: %w", err)
+	}
+	return options, nil
+}
+
+func selectTransportationOptionsStep(app AppCtx, plan db.InboundPlan) error {
+	shipments, err := app.Query.GetInboundPlanShipments(app.Ctx, plan.InboundPlanID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+	if len(shipments) == 0 {
+		return errors.New("inbound plan has no shipments, rerun with --from placement_confirmed")
+	}
+	readyToShip, err := readyToShipDate(shipmentWorkflowCfg.ReadyToShip)
+	if err != nil {
+		return err
+	}
+	options, err := generateTransportationOptions(app, plan, shipments, readyToShip)
+	if err != nil {
+		return err
+	}
+	for _, shipment := range shipments {
+		var choices []huh.Option[string]
+		for _, option := range options {
+			if option.ShipmentId == shipment.ShipmentID {
+				choices = append(choices, huh.NewOption(formatTransportationOption(option), option.TransportationOptionId))
+			}
+		}
+		transportationOptionId, err := selectInboundOption(fmt.Sprintf("Transportation Option for %s", shipment.ShipmentID), choices)
+		if err != nil {
+			return fmt.Errorf("shipment %s: %w", shipment.ShipmentID, err)
+		}
+		if err := app.Query.UpdateInboundPlanShipmentTransportationOption(app.Ctx, db.UpdateInboundPlanShipmentTransportationOptionParams{
+			TransportationOptionID: sql.NullString{String: transportationOptionId, Valid: true},
+			InboundPlanID:          plan.InboundPlanID,
+			ShipmentID:             shipment.ShipmentID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func confirmDeliveryWindowsStep(app AppCtx, plan db.InboundPlan) error {
+	shipments, err := app.Query.GetInboundPlanShipments(app.Ctx, plan.InboundPlanID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+	for _, shipment := range shipments {
+		if shipment.DeliveryWindowOptionID.Valid {
+			continue
+		}
+		options, err := listTransportationOptions(app, plan.InboundPlanID, nil, internal.Ptr(shipment.ShipmentID))
+		if err != nil {
+			return fmt.Errorf("failed to list transportation options of %s: %w", shipment.ShipmentID, err)
+		}
+		index := slices.IndexFunc(options, func(option fba_inbound.TransportationOption) bool {
+			return option.TransportationOptionId == shipment.TransportationOptionID.String
+		})
+		if index == -1 {
+			return fmt.Errorf("selected transportation option of %s is no longer available, rerun with --from %s", shipment.ShipmentID, inboundStepTransportationSelected)
+		}
+		if !slices.Contains(options[index].Preconditions, confirmedDeliveryWindowPrecondition) {
+			continue
+		}
+
+		generated, err := app.Amazon.Client.GenerateDeliveryWindowOptions(app.Ctx, plan.InboundPlanID, shipment.ShipmentID)
+		if err != nil {
+			return fmt.Errorf("failed to generate delivery window options of %s: %w", shipment.ShipmentID, err)
+		}
+		if _, err := waitForPlanOperation(app, plan.InboundPlanID, generated.JSON202.OperationId); err != nil {
+			return err
+		}
+		windows, err := listDeliveryWindowOptions(app, plan.InboundPlanID, shipment.ShipmentID)
+		if err != nil {
+			return fmt.Errorf("failed to list delivery window options of %s: %w", shipment.ShipmentID, err)
+		}
+		var choices []huh.Option[string]
+		for _, window := range windows {
+			key := fmt.Sprintf("%s - %s (%s)", window.StartDate.Local().Format(time.DateOnly), window.EndDate.Local().Format(time.DateOnly), window.AvailabilityType)
+			choices = append(choices, huh.NewOption(key, window.DeliveryWindowOptionId))
+		}
+		deliveryWindowOptionId, err := selectInboundOption(fmt.Sprintf("Delivery Window for %s", shipment.ShipmentID), choices)
+		if err != nil {
+			return fmt.Errorf("shipment %s: %w", shipment.ShipmentID, err)
+		}
+		confirmed, err := app.Amazon.Client.ConfirmDeliveryWindowOptions(app.Ctx, plan.InboundPlanID, shipment.ShipmentID, deliveryWindowOptionId)
+		if err != nil {
+			return fmt.Errorf("failed to confirm delivery window of %s: %w", shipment.ShipmentID, err)
+		}
+		if _, err := waitForPlanOperation(app, plan.InboundPlanID, confirmed.JSON202.OperationId); err != nil {
+			return err
+		}
+		if err := app.Query.UpdateInboundPlanShipmentDeliveryWindowOption(app.Ctx, db.UpdateInboundPlanShipmentDeliveryWindowOptionParams{
+			DeliveryWindowOptionID: sql.NullString{String: deliveryWindowOptionId, Valid: true},
+			InboundPlanID:          plan.InboundPlanID,
+			ShipmentID:             shipment.ShipmentID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func confirmTransportationOptionsStep(app AppCtx, plan db.InboundPlan) error {
+	shipments, err := app.Query.GetInboundPlanShipments(app.Ctx, plan.InboundPlanID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+	var request fba_inbound.ConfirmTransportationOptionsRequest
+	for _, shipment := range shipments {
+		if !shipment.TransportationOptionID.Valid {
+			return fmt.Errorf("shipment %s has no transportation option selected, rerun with --from %s", shipment.ShipmentID, inboundStepTransportationSelected)
+		}
+		request.TransportationSelections = append(request.TransportationSelections, fba_inbound.TransportationSelection{
+			ShipmentId:             shipment.ShipmentID,
+			TransportationOptionId: shipment.TransportationOptionID.String,
+			ContactInformation:     shipFromContactInformation(shipmentSourceAddress(plan, shipment)),
+		})
+	}
+	confirmed, err := app.Amazon.Client.ConfirmTransportationOptions(app.Ctx, plan.InboundPlanID, request)
+	if err != nil {
+		return fmt.Errorf("failed to confirm transportation options: %w", err)
+	}
+	_, err = waitForPlanOperation(app, plan.InboundPlanID, confirmed.JSON202.OperationId)
+	return err
+}
+
+func fetchShipmentLabelsStep(app AppCtx, plan db.InboundPlan) error {
+	shipments, err := app.Query.GetInboundPlanShipments(app.Ctx, plan.InboundPlanID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+	options := shipmentLabelOptions{
+		PageType:  fba_inbound_v0.GetLabelsParamsPageType(cmp.Or(cfg.Amazon.FBA.Labels.PageType, string(fba_inbound_v0.PackageLabelPlainPaper))),
+		LabelType: fba_inbound_v0.UNIQUE,
+		Directory: cmp.Or(cfg.Amazon.FBA.Labels.Directory, "labels"),
+	}
+	fmt.Printf("%-40s %-16s %-10s %s\n", "Shipment ID", "Confirmation ID", "Warehouse", "Box Labels")
+	fmt.Println(color.HiBlackString("%s", "--------------------------------------------------------------------"))
+	for _, shipment := range shipments {
+		resp, err := app.Amazon.Client.GetInboundShipment(app.Ctx, plan.InboundPlanID, shipment.ShipmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get shipment %s: %w", shipment.ShipmentID, err)
+		}
+		remote := resp.JSON200
+		if remote.ShipmentConfirmationId == nil {
+			return fmt.Errorf("shipment %s is not confirmed yet", shipment.ShipmentID)
+		}
+		shipment.ConfirmationID = sql.NullString{String: *remote.ShipmentConfirmationId, Valid: true}
+		if err := app.Query.UpdateInboundPlanShipmentConfirmationID(app.Ctx, db.UpdateInboundPlanShipmentConfirmationIDParams{
+			ConfirmationID: shipment.ConfirmationID,
+			InboundPlanID:  plan.InboundPlanID,
+			ShipmentID:     shipment.ShipmentID,
+		}); err != nil {
+			return err
+		}
+		warehouse := "-"
+		if remote.Destination.WarehouseId != nil {
+			warehouse = *remote.Destination.WarehouseId
+		}
+		paths, err := downloadShipmentLabels(app, shipment, options)
+		if err != nil {
+			return fmt.Errorf("failed to download labels of shipment %s: %w", shipment.ShipmentID, err)
+		}
+		fmt.Printf("%-40s %-16s %-10s %s\n", shipment.ShipmentID, *remote.ShipmentConfirmationId, warehouse, strings.Join(paths, ", "))
+	}
+	fmt.Printf("\nPrint them, or download pallet labels and other page types with: halycon shipment labels <confirmation id> --print\n")
+	return nil
+}
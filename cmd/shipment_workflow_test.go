@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/config"
+	"github.com/caner-cetin/halycon/internal/db"
+)
+
+func TestShipFromContactInformation(t *testing.T) {
+	previous := cfg.Amazon.FBA.DefaultShipFrom
+	t.Cleanup(func() { cfg.Amazon.FBA.DefaultShipFrom = previous })
+	cfg.Amazon.FBA.DefaultShipFrom = config.ShipFromConfig{Name: "Default", PhoneNumber: "555-0100", Email: "default@example.com"}
+
+	planAddress := sql.NullString{String: `{"name":"Plan","phoneNumber":"555-0101","addressLine1":"1 Plan St"}`, Valid: true}
+	shipmentAddress := sql.NullString{String: `{"name":"Moved","phoneNumber":"555-0102","email":"moved@example.com","addressLine1":"2 Moved St"}`, Valid: true}
+	defaultContact := &fba_inbound.ContactInformation{Name: "Default", PhoneNumber: "555-0100", Email: internal.Ptr("default@example.com")}
+	tests := []struct {
+		name     string
+		plan     sql.NullString
+		shipment sql.NullString
+		want     *fba_inbound.ContactInformation
+	}{
+		{name: "shipment address", plan: planAddress, shipment: shipmentAddress, want: &fba_inbound.ContactInformation{Name: "Moved", PhoneNumber: "555-0102", Email: internal.Ptr("moved@example.com")}},
+		{name: "plan address", plan: planAddress, want: &fba_inbound.ContactInformation{Name: "Plan", PhoneNumber: "555-0101"}},
+		{name: "nothing stored", want: defaultContact},
+		{name: "null address", plan: sql.NullString{String: "null", Valid: true}, want: defaultContact},
+		{name: "address without phone number", plan: sql.NullString{String: `{"name":"Plan"}`, Valid: true}, want: defaultContact},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := shipmentSourceAddress(db.InboundPlan{SourceAddress: tt.plan}, db.InboundPlanShipment{SourceAddress: tt.shipment})
+			if got := shipFromContactInformation(address); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shipFromContactInformation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,16 @@ const API_MODELS: ApiModel[] = [
     remotePath: "fulfillment-inbound-api-model/fulfillmentInbound_2024-03-20.json",
     packageFolder: "internal/amazon/fba_inbound"
   },
+  {
+    json: "fulfillmentInboundV0.json",
+    remotePath: "fulfillment-inbound-api-model/fulfillmentInboundV0.json",
+    packageFolder: "internal/amazon/fba_inbound_v0"
+  },
+  {
+    json: "fbaInbound.json",
+    remotePath: "fba-inbound-eligibility-api-model/fbaInbound.json",
+    packageFolder: "internal/amazon/fba_eligibility"
+  },
   {
     json: "fbaInventory.json",
     remotePath: "fba-inventory-api-model/fbaInventory.json",
@@ -47,6 +57,8 @@ async function setupDirectories() {
   const dirs = [
     "internal/amazon/catalog",
     "internal/amazon/fba_inbound", 
+    "internal/amazon/fba_inbound_v0",
+    "internal/amazon/fba_eligibility",
     "internal/amazon/fba_inventory",
     "internal/amazon/listings",
     "internal/amazon/product_type_definitions",
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal/config"
+)
+
+// Rule types that can be configured under amazon.fba.alerts.rules
+const (
+	RuleFulfillableBelow   = "fulfillable_below"
+	RuleDaysOfCoverBelow   = "days_of_cover_below"
+	RuleUnfulfillableAbove = "unfulfillable_above"
+	RuleSKUMissing         = "sku_missing"
+)
+
+// Alert is a single fired rule for a single SKU.
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Type      string    `json:"type"`
+	SKU       string    `json:"sku"`
+	ASIN      string    `json:"asin,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Message   string    `json:"message"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+// Notifier sends fired alerts to a destination.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// NewNotifier constructs the notifier described by cfg.
+func NewNotifier(cfg config.NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case "stdout", "":
+		return &stdoutNotifier{name: cfg.Name}, nil
+	case "webhook":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("webhook notifier %s: url not set", cfg.Name)
+		}
+		return &webhookNotifier{name: cfg.Name, url: cfg.URL, headers: cfg.Headers}, nil
+	case "smtp":
+		if cfg.Host == "" {
+			return nil, fmt.Errorf("smtp notifier %s: host not set", cfg.Name)
+		}
+		if cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("smtp notifier %s: from and to must be set", cfg.Name)
+		}
+		port := cfg.Port
+		if port == 0 {
+			port = 587
+		}
+		return &smtpNotifier{name: cfg.Name, host: cfg.Host, port: port, username: cfg.Username, password: cfg.Password, from: cfg.From, to: cfg.To}, nil
+	case "file":
+		if cfg.Path == "" {
+			return nil, fmt.Errorf("file notifier %s: path not set", cfg.Name)
+		}
+		return &fileNotifier{name: cfg.Name, path: cfg.Path}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %s for notifier %s", cfg.Type, cfg.Name)
+	}
+}
+
+// ValidateRule checks the rule for unknown types and missing fields.
+func ValidateRule(rule config.AlertRuleConfig) error {
+	if rule.Name == "" {
+		return fmt.Errorf("alert rule name not set")
+	}
+	switch rule.Type {
+	case RuleFulfillableBelow, RuleDaysOfCoverBelow, RuleUnfulfillableAbove, RuleSKUMissing:
+		return nil
+	default:
+		return fmt.Errorf("unknown alert rule type %s for rule %s", rule.Type, rule.Name)
+	}
+}
@@ -0,0 +1,124 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caner-cetin/halycon/internal"
+	"github.com/fatih/color"
+)
+
+type stdoutNotifier struct {
+	name string
+}
+
+func (n *stdoutNotifier) Name() string { return n.name }
+
+func (n *stdoutNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	for _, alert := range alerts {
+		fmt.Printf("%s %s\n", color.RedString("[%s]", alert.Rule), alert.Message)
+	}
+	return nil
+}
+
+type webhookNotifier struct {
+	name    string
+	url     string
+	headers map[string]string
+}
+
+func (n *webhookNotifier) Name() string { return n.name }
+
+// Notify POSTs {"alerts": [...]} to the configured URL.
+func (n *webhookNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	body, err := json.Marshal(map[string][]Alert{"alerts": alerts})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alerts: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer internal.CloseReader(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+type smtpNotifier struct {
+	name     string
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func (n *smtpNotifier) Name() string { return n.name }
+
+// Notify sends a single plain text mail listing all alerts.
+func (n *smtpNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [halycon] %d inventory alert(s)\r\n", len(alerts))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	for _, alert := range alerts {
+		fmt.Fprintf(&msg, "[%s] %s\r\n", alert.Rule, alert.Message)
+	}
+
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+	addr := net.JoinHostPort(n.host, strconv.Itoa(n.port))
+	if err := smtp.SendMail(addr, auth, n.from, n.to, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send mail through %s: %w", addr, err)
+	}
+	return nil
+}
+
+type fileNotifier struct {
+	name string
+	path string
+}
+
+func (n *fileNotifier) Name() string { return n.name }
+
+// Notify appends alerts to the file as newline delimited JSON.
+func (n *fileNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", n.path, err)
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	for _, alert := range alerts {
+		if err := encoder.Encode(alert); err != nil {
+			return fmt.Errorf("failed to write alert to %s: %w", n.path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	alerts := []Alert{{
+		Rule:      "out-of-stock",
+		Type:      RuleFulfillableBelow,
+		SKU:       "SKU-1",
+		ASIN:      "B000000001",
+		Value:     0,
+		Threshold: 1,
+		Message:   "SKU-1 (B000000001) has 0 fulfillable units, below 1",
+		FiredAt:   time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}}
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{name: "ok", status: http.StatusOK},
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "server error", status: http.StatusInternalServerError, wantErr: "500"},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: "401"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string][]Alert
+			var method, contentType, token string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				contentType = r.Header.Get("Content-Type")
+				token = r.Header.Get("Authorization")
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					t.Errorf("failed to decode payload: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier := &webhookNotifier{name: "hook", url: server.URL, headers: map[string]string{"Authorization": "Bearer secret"}}
+			err := notifier.Notify(context.Background(), alerts)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if method != http.MethodPost {
+				t.Errorf("method = %s, want POST", method)
+			}
+			if contentType != "application/json" {
+				t.Errorf("content type = %s, want application/json", contentType)
+			}
+			if token != "Bearer secret" {
+				t.Errorf("authorization header = %q, want configured header", token)
+			}
+			if len(payload["alerts"]) != 1 {
+				t.Fatalf("payload has %d alerts, want 1", len(payload["alerts"]))
+			}
+			got := payload["alerts"][0]
+			if got.Rule != alerts[0].Rule || got.SKU != alerts[0].SKU || got.Message != alerts[0].Message || !got.FiredAt.Equal(alerts[0].FiredAt) {
+				t.Errorf("payload alert = %+v, want %+v", got, alerts[0])
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	notifier := &webhookNotifier{name: "hook", url: url}
+	if err := notifier.Notify(context.Background(), []Alert{{Rule: "r", SKU: "s"}}); err == nil {
+		t.Fatal("expected an error for an unreachable webhook")
+	}
+}
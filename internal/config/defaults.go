@@ -175,5 +175,14 @@ func SetOtherDefaults() error {
 
 	Config.Amazon.DefaultLanguageTag = "en_US"
 	Config.Sqlite.Path = filepath.Join(home, ".halycon.db")
+	if Config.Amazon.FBA.Restock.LeadTimeDays == 0 {
+		Config.Amazon.FBA.Restock.LeadTimeDays = 30
+	}
+	if Config.Amazon.FBA.Restock.TargetCoverDays == 0 {
+		Config.Amazon.FBA.Restock.TargetCoverDays = 60
+	}
+	if Config.Amazon.FBA.Restock.LookbackDays == 0 {
+		Config.Amazon.FBA.Restock.LookbackDays = 30
+	}
 	return nil
 }
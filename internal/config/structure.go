@@ -54,6 +54,92 @@ type FBAConfig struct {
 	DefaultShipFrom      ShipFromConfig   `yaml:"-"`
 	DefaultShipFromIndex int              `yaml:"-"`
 	ShipFrom             []ShipFromConfig `mapstructure:"ship_from" yaml:"ship_from"`
+	Restock              RestockConfig    `mapstructure:"restock" yaml:"restock"`
+	Alerts               AlertsConfig     `mapstructure:"alerts" yaml:"alerts"`
+	Cartons              []CartonConfig   `mapstructure:"cartons" yaml:"cartons"`
+	Labels               LabelsConfig     `mapstructure:"labels" yaml:"labels"`
+}
+
+// LabelsConfig holds the defaults for downloading and printing shipment labels
+type LabelsConfig struct {
+	// PageType of labels, such as PackageLabel_Letter_2 or PackageLabel_Thermal. Defaults to PackageLabel_Plain_Paper.
+	PageType string `mapstructure:"page_type" yaml:"page_type"`
+	// Directory labels are saved under, as <directory>/<inbound plan>/<shipment confirmation id>/. Defaults to labels.
+	Directory string `mapstructure:"directory" yaml:"directory"`
+	// PrintCommand labels are piped to when printing, such as "lp -d office". Defaults to lp, or lpr if lp is not installed.
+	PrintCommand string `mapstructure:"print_command" yaml:"print_command"`
+	// ThermalPrintCommand is used instead of PrintCommand for thermal page types, such as "lpr -P zebra -o raw".
+	ThermalPrintCommand string `mapstructure:"thermal_print_command" yaml:"thermal_print_command"`
+}
+
+// CartonConfig is a carton size available to the warehouse, used for suggesting box contents of inbound plans
+type CartonConfig struct {
+	// Name of carton, shown in suggestions. Defaults to the dimensions.
+	Name   string  `mapstructure:"name" yaml:"name"`
+	Length float64 `mapstructure:"length" yaml:"length"`
+	Width  float64 `mapstructure:"width" yaml:"width"`
+	Height float64 `mapstructure:"height" yaml:"height"`
+	// DimensionUnit is IN or CM, defaults to IN.
+	DimensionUnit string `mapstructure:"dimension_unit" yaml:"dimension_unit"`
+	// Weight of the empty carton.
+	Weight float64 `mapstructure:"weight" yaml:"weight"`
+	// MaxWeight is the maximum gross weight of a packed carton, defaults to the 50 lb limit of Amazon.
+	MaxWeight float64 `mapstructure:"max_weight" yaml:"max_weight"`
+	// WeightUnit is LB or KG, defaults to LB.
+	WeightUnit string `mapstructure:"weight_unit" yaml:"weight_unit"`
+}
+
+// RestockConfig holds the parameters used for computing reorder quantities from inventory snapshot history
+type RestockConfig struct {
+	// LeadTimeDays is the number of days between ordering units and them becoming fulfillable at Amazon.
+	LeadTimeDays int `mapstructure:"lead_time_days" yaml:"lead_time_days"`
+	// TargetCoverDays is the number of days of sales a restock should cover once it arrives.
+	TargetCoverDays int `mapstructure:"target_cover_days" yaml:"target_cover_days"`
+	// LookbackDays is how far back the snapshot history is read while computing sell-through velocity.
+	LookbackDays int `mapstructure:"lookback_days" yaml:"lookback_days"`
+}
+
+// AlertsConfig holds the inventory alert rules evaluated after every inventory build, and where the fired alerts are sent
+type AlertsConfig struct {
+	Rules     []AlertRuleConfig `mapstructure:"rules" yaml:"rules"`
+	Notifiers []NotifierConfig  `mapstructure:"notifiers" yaml:"notifiers"`
+}
+
+// AlertRuleConfig is a single threshold rule evaluated against the latest inventory snapshot
+type AlertRuleConfig struct {
+	// Name of rule, must be unique, used for deduplicating already fired alerts.
+	Name string `mapstructure:"name" yaml:"name"`
+	// Type is one of fulfillable_below, days_of_cover_below, unfulfillable_above or sku_missing.
+	Type string `mapstructure:"type" yaml:"type"`
+	// Threshold the value is compared against, not used by sku_missing.
+	Threshold float64 `mapstructure:"threshold" yaml:"threshold"`
+	// SKUs limits the rule to given SKUs, rule applies to all SKUs if empty.
+	SKUs []string `mapstructure:"skus" yaml:"skus"`
+	// Notifiers are the names of configured notifiers to send alerts of this rule to, all notifiers are used if empty.
+	Notifiers []string `mapstructure:"notifiers" yaml:"notifiers"`
+}
+
+// NotifierConfig describes a single alert destination
+type NotifierConfig struct {
+	// Name of notifier, referenced from rules.
+	Name string `mapstructure:"name" yaml:"name"`
+	// Type is one of stdout, webhook, smtp or file.
+	Type string `mapstructure:"type" yaml:"type"`
+	// URL alerts are POSTed to as JSON, for webhook notifiers.
+	URL string `mapstructure:"url" yaml:"url"`
+	// Headers added to webhook requests, such as authorization.
+	Headers map[string]string `mapstructure:"headers" yaml:"headers"`
+	// Host of SMTP server, for smtp notifiers.
+	Host string `mapstructure:"host" yaml:"host"`
+	// Port of SMTP server, defaults to 587.
+	Port     int    `mapstructure:"port" yaml:"port"`
+	Username string `mapstructure:"username" yaml:"username"`
+	Password string `mapstructure:"password" yaml:"password"`
+	From     string `mapstructure:"from" yaml:"from"`
+	// To is the list of recipients for smtp notifiers.
+	To []string `mapstructure:"to" yaml:"to"`
+	// Path of the file alerts are appended to as newline delimited JSON, for file notifiers.
+	Path string `mapstructure:"path" yaml:"path"`
 }
 
 // ShipFromConfig holds the address and contact information for FBA shipping origin
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE fba_inventory_snapshot (
+  snapshot_at DATETIME NOT NULL,
+  sku TEXT NOT NULL,
+  asin TEXT,
+  title TEXT,
+  total_quantity INTEGER,
+  fulfillable_quantity INTEGER,
+  inbound_working_quantity INTEGER,
+  inbound_shipped_quantity INTEGER,
+  inbound_receiving_quantity INTEGER,
+  reserved_quantity INTEGER,
+  unfulfillable_quantity INTEGER
+);
+CREATE INDEX idx_fba_inventory_snapshot_sku_snapshot_at ON fba_inventory_snapshot (sku, snapshot_at);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_fba_inventory_snapshot_sku_snapshot_at;
+DROP TABLE IF EXISTS fba_inventory_snapshot;
+-- +goose StatementEnd
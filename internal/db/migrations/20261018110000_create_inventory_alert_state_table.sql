@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE inventory_alert_state (
+  rule TEXT NOT NULL,
+  sku TEXT NOT NULL,
+  fired_at DATETIME NOT NULL,
+  value REAL NOT NULL,
+  PRIMARY KEY (rule, sku)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inventory_alert_state;
+-- +goose StatementEnd
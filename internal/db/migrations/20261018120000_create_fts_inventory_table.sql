@@ -0,0 +1,177 @@
+-- +goose Up
+-- +goose StatementBegin
+-- fba_inventory is recreated with an explicit integer primary key, external content FTS tables
+-- reference rows by rowid and implicit rowids are not guaranteed to be stable
+CREATE TABLE fba_inventory_new (
+  id INTEGER PRIMARY KEY,
+  title TEXT,
+  total_quantity INTEGER,
+  fulfillable_quantity INTEGER,
+  inbound_receiving_quantity INTEGER,
+  inbound_shipped_quantity INTEGER,
+  sku TEXT,
+  asin TEXT,
+  upc TEXT,
+  fnsku TEXT,
+  marketplace_id TEXT
+);
+INSERT INTO fba_inventory_new (
+    title,
+    total_quantity,
+    fulfillable_quantity,
+    inbound_receiving_quantity,
+    inbound_shipped_quantity,
+    sku,
+    asin,
+    upc
+  )
+SELECT title,
+  total_quantity,
+  fulfillable_quantity,
+  inbound_receiving_quantity,
+  inbound_shipped_quantity,
+  sku,
+  asin,
+  upc
+FROM fba_inventory;
+DROP TABLE fba_inventory;
+ALTER TABLE fba_inventory_new RENAME TO fba_inventory;
+DROP TABLE IF EXISTS fts_title_quantity;
+-- quantities are no longer indexed as text, they are read from fba_inventory through the join on rowid
+CREATE VIRTUAL TABLE fts_inventory USING fts5(
+  title,
+  sku,
+  asin,
+  fnsku,
+  upc,
+  marketplace_id,
+  content = 'fba_inventory',
+  content_rowid = 'id',
+  tokenize = "unicode61 remove_diacritics 2 tokenchars '-_'",
+  prefix = '2 3'
+);
+-- identifier matches rank above title matches
+INSERT INTO fts_inventory(fts_inventory, rank)
+VALUES('rank', 'bm25(1.0, 10.0, 10.0, 10.0, 10.0, 0.5)');
+-- term list used for typo tolerant matching
+CREATE VIRTUAL TABLE fts_inventory_vocab USING fts5vocab(fts_inventory, row);
+CREATE TRIGGER fba_inventory_ai
+AFTER
+INSERT ON fba_inventory BEGIN
+INSERT INTO fts_inventory(rowid, title, sku, asin, fnsku, upc, marketplace_id)
+VALUES (
+    new.id,
+    new.title,
+    new.sku,
+    new.asin,
+    new.fnsku,
+    new.upc,
+    new.marketplace_id
+  );
+END;
+CREATE TRIGGER fba_inventory_ad
+AFTER DELETE ON fba_inventory BEGIN
+INSERT INTO fts_inventory(
+    fts_inventory,
+    rowid,
+    title,
+    sku,
+    asin,
+    fnsku,
+    upc,
+    marketplace_id
+  )
+VALUES (
+    'delete',
+    old.id,
+    old.title,
+    old.sku,
+    old.asin,
+    old.fnsku,
+    old.upc,
+    old.marketplace_id
+  );
+END;
+CREATE TRIGGER fba_inventory_au
+AFTER
+UPDATE ON fba_inventory BEGIN
+INSERT INTO fts_inventory(
+    fts_inventory,
+    rowid,
+    title,
+    sku,
+    asin,
+    fnsku,
+    upc,
+    marketplace_id
+  )
+VALUES (
+    'delete',
+    old.id,
+    old.title,
+    old.sku,
+    old.asin,
+    old.fnsku,
+    old.upc,
+    old.marketplace_id
+  );
+INSERT INTO fts_inventory(rowid, title, sku, asin, fnsku, upc, marketplace_id)
+VALUES (
+    new.id,
+    new.title,
+    new.sku,
+    new.asin,
+    new.fnsku,
+    new.upc,
+    new.marketplace_id
+  );
+END;
+INSERT INTO fts_inventory(fts_inventory)
+VALUES('rebuild');
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS fba_inventory_ai;
+DROP TRIGGER IF EXISTS fba_inventory_ad;
+DROP TRIGGER IF EXISTS fba_inventory_au;
+DROP TABLE IF EXISTS fts_inventory_vocab;
+DROP TABLE IF EXISTS fts_inventory;
+CREATE TABLE fba_inventory_old (
+  title TEXT,
+  total_quantity INTEGER,
+  fulfillable_quantity INTEGER,
+  inbound_receiving_quantity INTEGER,
+  inbound_shipped_quantity INTEGER,
+  sku TEXT,
+  asin TEXT,
+  upc TEXT
+);
+INSERT INTO fba_inventory_old
+SELECT title,
+  total_quantity,
+  fulfillable_quantity,
+  inbound_receiving_quantity,
+  inbound_shipped_quantity,
+  sku,
+  asin,
+  upc
+FROM fba_inventory;
+DROP TABLE fba_inventory;
+ALTER TABLE fba_inventory_old RENAME TO fba_inventory;
+CREATE VIRTUAL TABLE fts_title_quantity USING FTS5(
+  title,
+  total_quantity,
+  fulfillable_quantity,
+  inbound_receiving_quantity,
+  inbound_shipped_quantity,
+  upc
+);
+INSERT INTO fts_title_quantity
+SELECT title,
+  total_quantity,
+  fulfillable_quantity,
+  inbound_receiving_quantity,
+  inbound_shipped_quantity,
+  upc
+FROM fba_inventory;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE inbound_plan (
+  inbound_plan_id TEXT PRIMARY KEY,
+  name TEXT,
+  marketplace_id TEXT,
+  step TEXT NOT NULL,
+  packing_option_id TEXT,
+  placement_option_id TEXT,
+  created_at DATETIME NOT NULL,
+  updated_at DATETIME NOT NULL
+);
+CREATE TABLE inbound_plan_shipment (
+  inbound_plan_id TEXT NOT NULL,
+  shipment_id TEXT NOT NULL,
+  transportation_option_id TEXT,
+  delivery_window_option_id TEXT,
+  confirmation_id TEXT,
+  PRIMARY KEY (inbound_plan_id, shipment_id)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inbound_plan_shipment;
+DROP TABLE IF EXISTS inbound_plan;
+-- +goose StatementEnd
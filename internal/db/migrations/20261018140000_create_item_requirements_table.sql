@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_requirements (
+  merchant TEXT NOT NULL,
+  marketplace_id TEXT NOT NULL,
+  msku TEXT NOT NULL,
+  prep_owner TEXT NOT NULL,
+  label_owner TEXT NOT NULL,
+  source TEXT NOT NULL,
+  updated_at DATETIME NOT NULL,
+  PRIMARY KEY (merchant, marketplace_id, msku)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_requirements;
+-- +goose StatementEnd
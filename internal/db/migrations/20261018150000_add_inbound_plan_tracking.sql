@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE inbound_plan ADD COLUMN status TEXT;
+ALTER TABLE inbound_plan ADD COLUMN source_address TEXT;
+ALTER TABLE inbound_plan ADD COLUMN synced_at DATETIME;
+ALTER TABLE inbound_plan_shipment ADD COLUMN name TEXT;
+ALTER TABLE inbound_plan_shipment ADD COLUMN status TEXT;
+ALTER TABLE inbound_plan_shipment ADD COLUMN destination_warehouse TEXT;
+ALTER TABLE inbound_plan_shipment ADD COLUMN amazon_reference_id TEXT;
+CREATE TABLE inbound_plan_item (
+  inbound_plan_id TEXT NOT NULL,
+  msku TEXT NOT NULL,
+  asin TEXT,
+  fnsku TEXT,
+  quantity INTEGER NOT NULL,
+  prep_owner TEXT,
+  label_owner TEXT,
+  PRIMARY KEY (inbound_plan_id, msku)
+);
+CREATE TABLE inbound_plan_box (
+  inbound_plan_id TEXT NOT NULL,
+  package_id TEXT NOT NULL,
+  box_id TEXT,
+  quantity INTEGER,
+  length REAL,
+  width REAL,
+  height REAL,
+  dimension_unit TEXT,
+  weight REAL,
+  weight_unit TEXT,
+  item_count INTEGER,
+  destination_warehouse TEXT,
+  PRIMARY KEY (inbound_plan_id, package_id)
+);
+CREATE TABLE inbound_plan_operation (
+  operation_id TEXT PRIMARY KEY,
+  inbound_plan_id TEXT NOT NULL,
+  operation TEXT NOT NULL,
+  status TEXT NOT NULL,
+  created_at DATETIME NOT NULL,
+  updated_at DATETIME NOT NULL
+);
+CREATE INDEX idx_inbound_plan_operation_plan ON inbound_plan_operation (inbound_plan_id);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inbound_plan_operation;
+DROP TABLE IF EXISTS inbound_plan_box;
+DROP TABLE IF EXISTS inbound_plan_item;
+ALTER TABLE inbound_plan_shipment DROP COLUMN amazon_reference_id;
+ALTER TABLE inbound_plan_shipment DROP COLUMN destination_warehouse;
+ALTER TABLE inbound_plan_shipment DROP COLUMN status;
+ALTER TABLE inbound_plan_shipment DROP COLUMN name;
+ALTER TABLE inbound_plan DROP COLUMN synced_at;
+ALTER TABLE inbound_plan DROP COLUMN source_address;
+ALTER TABLE inbound_plan DROP COLUMN status;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE catalog_item_dimensions (
+  marketplace_id TEXT NOT NULL,
+  asin TEXT NOT NULL,
+  length REAL,
+  width REAL,
+  height REAL,
+  weight REAL,
+  source TEXT NOT NULL,
+  updated_at DATETIME NOT NULL,
+  PRIMARY KEY (marketplace_id, asin)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS catalog_item_dimensions;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- items of every shipment of a plan as submitted, the baseline of `shipment reconcile`
+CREATE TABLE inbound_plan_shipment_item (
+  inbound_plan_id TEXT NOT NULL,
+  shipment_id TEXT NOT NULL,
+  msku TEXT NOT NULL,
+  asin TEXT,
+  fnsku TEXT,
+  quantity INTEGER NOT NULL,
+  PRIMARY KEY (inbound_plan_id, shipment_id, msku)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inbound_plan_shipment_item;
+-- +goose StatementEnd
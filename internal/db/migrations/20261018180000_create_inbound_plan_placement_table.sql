@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- confirmed placement options of plans with their fees, shown in `shipment show`
+CREATE TABLE inbound_plan_placement (
+  inbound_plan_id TEXT NOT NULL,
+  placement_option_id TEXT NOT NULL,
+  shipment_count INTEGER NOT NULL,
+  warehouses TEXT,
+  fees REAL NOT NULL,
+  discounts REAL NOT NULL,
+  currency TEXT,
+  policy TEXT,
+  confirmed_at DATETIME NOT NULL,
+  PRIMARY KEY (inbound_plan_id, placement_option_id)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS inbound_plan_placement;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- product type schemas downloaded for `listings validate`, keyed by the checksum given with the definition
+CREATE TABLE product_type_schema (
+  checksum TEXT PRIMARY KEY,
+  product_type TEXT NOT NULL,
+  schema TEXT NOT NULL,
+  fetched_at DATETIME NOT NULL
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_type_schema;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- product type definitions fetched from Amazon, keyed by the request and the version returned with the definition. schemas
+-- of definitions are stored in product_type_schema by their checksum
+CREATE TABLE product_type_definition (
+  product_type TEXT NOT NULL,
+  marketplace_id TEXT NOT NULL,
+  requirements TEXT NOT NULL,
+  locale TEXT NOT NULL,
+  version TEXT NOT NULL,
+  latest BOOLEAN NOT NULL,
+  definition TEXT NOT NULL,
+  schema_checksum TEXT NOT NULL,
+  fetched_at DATETIME NOT NULL,
+  PRIMARY KEY (product_type, marketplace_id, requirements, locale, version)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_type_definition;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- alerts are recorded per notifier that delivered them, so a failed notifier is retried without
+-- sending the alert to the others again. Existing state does not say which notifiers delivered it
+-- and is dropped, active alerts fire once more.
+DROP TABLE inventory_alert_state;
+CREATE TABLE inventory_alert_state (
+  rule TEXT NOT NULL,
+  sku TEXT NOT NULL,
+  notifier TEXT NOT NULL,
+  fired_at DATETIME NOT NULL,
+  value REAL NOT NULL,
+  PRIMARY KEY (rule, sku, notifier)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE inventory_alert_state;
+CREATE TABLE inventory_alert_state (
+  rule TEXT NOT NULL,
+  sku TEXT NOT NULL,
+  fired_at DATETIME NOT NULL,
+  value REAL NOT NULL,
+  PRIMARY KEY (rule, sku)
+);
+-- +goose StatementEnd
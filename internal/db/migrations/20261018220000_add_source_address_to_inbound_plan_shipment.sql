@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE inbound_plan_shipment ADD COLUMN source_address TEXT;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE inbound_plan_shipment DROP COLUMN source_address;
+-- +goose StatementEnd
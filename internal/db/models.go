@@ -6,9 +6,22 @@ package db
 
 import (
 	"database/sql"
+	"time"
 )
 
+type CatalogItemDimension struct {
+	MarketplaceID string
+	Asin          string
+	Length        sql.NullFloat64
+	Width         sql.NullFloat64
+	Height        sql.NullFloat64
+	Weight        sql.NullFloat64
+	Source        string
+	UpdatedAt     time.Time
+}
+
 type FbaInventory struct {
+	ID                       int64
 	Title                    sql.NullString
 	TotalQuantity            sql.NullInt64
 	FulfillableQuantity      sql.NullInt64
@@ -16,4 +29,140 @@ type FbaInventory struct {
 	InboundShippedQuantity   sql.NullInt64
 	Sku                      sql.NullString
 	Asin                     sql.NullString
+	Upc                      sql.NullString
+	Fnsku                    sql.NullString
+	MarketplaceID            sql.NullString
+}
+
+type FbaInventorySnapshot struct {
+	SnapshotAt               time.Time
+	Sku                      string
+	Asin                     sql.NullString
+	Title                    sql.NullString
+	TotalQuantity            sql.NullInt64
+	FulfillableQuantity      sql.NullInt64
+	InboundWorkingQuantity   sql.NullInt64
+	InboundShippedQuantity   sql.NullInt64
+	InboundReceivingQuantity sql.NullInt64
+	ReservedQuantity         sql.NullInt64
+	UnfulfillableQuantity    sql.NullInt64
+}
+
+type InboundPlan struct {
+	InboundPlanID     string
+	Name              sql.NullString
+	MarketplaceID     sql.NullString
+	Step              string
+	PackingOptionID   sql.NullString
+	PlacementOptionID sql.NullString
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Status            sql.NullString
+	SourceAddress     sql.NullString
+	SyncedAt          sql.NullTime
+}
+
+type InboundPlanBox struct {
+	InboundPlanID        string
+	PackageID            string
+	BoxID                sql.NullString
+	Quantity             sql.NullInt64
+	Length               sql.NullFloat64
+	Width                sql.NullFloat64
+	Height               sql.NullFloat64
+	DimensionUnit        sql.NullString
+	Weight               sql.NullFloat64
+	WeightUnit           sql.NullString
+	ItemCount            sql.NullInt64
+	DestinationWarehouse sql.NullString
+}
+
+type InboundPlanItem struct {
+	InboundPlanID string
+	Msku          string
+	Asin          sql.NullString
+	Fnsku         sql.NullString
+	Quantity      int64
+	PrepOwner     sql.NullString
+	LabelOwner    sql.NullString
+}
+
+type InboundPlanOperation struct {
+	OperationID   string
+	InboundPlanID string
+	Operation     string
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type InboundPlanPlacement struct {
+	InboundPlanID     string
+	PlacementOptionID string
+	ShipmentCount     int64
+	Warehouses        sql.NullString
+	Fees              float64
+	Discounts         float64
+	Currency          sql.NullString
+	Policy            sql.NullString
+	ConfirmedAt       time.Time
+}
+
+type InboundPlanShipment struct {
+	InboundPlanID          string
+	ShipmentID             string
+	TransportationOptionID sql.NullString
+	DeliveryWindowOptionID sql.NullString
+	ConfirmationID         sql.NullString
+	Name                   sql.NullString
+	Status                 sql.NullString
+	DestinationWarehouse   sql.NullString
+	AmazonReferenceID      sql.NullString
+	SourceAddress          sql.NullString
+}
+
+type InboundPlanShipmentItem struct {
+	InboundPlanID string
+	ShipmentID    string
+	Msku          string
+	Asin          sql.NullString
+	Fnsku         sql.NullString
+	Quantity      int64
+}
+
+type InventoryAlertState struct {
+	Rule     string
+	Sku      string
+	Notifier string
+	FiredAt  time.Time
+	Value    float64
+}
+
+type ItemRequirement struct {
+	Merchant      string
+	MarketplaceID string
+	Msku          string
+	PrepOwner     string
+	LabelOwner    string
+	Source        string
+	UpdatedAt     time.Time
+}
+
+type ProductTypeDefinition struct {
+	ProductType    string
+	MarketplaceID  string
+	Requirements   string
+	Locale         string
+	Version        string
+	Latest         bool
+	Definition     string
+	SchemaChecksum string
+	FetchedAt      time.Time
+}
+
+type ProductTypeSchema struct {
+	Checksum    string
+	ProductType string
+	Schema      string
+	FetchedAt   time.Time
 }
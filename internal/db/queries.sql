@@ -11,3 +11,370 @@ where asin = ?;
 select COUNT(sku)
 from fba_inventory
 group by sku;
+-- name: InsertFBAInventorySnapshot :exec
+insert into fba_inventory_snapshot (
+    snapshot_at,
+    sku,
+    asin,
+    title,
+    total_quantity,
+    fulfillable_quantity,
+    inbound_working_quantity,
+    inbound_shipped_quantity,
+    inbound_receiving_quantity,
+    reserved_quantity,
+    unfulfillable_quantity
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+-- name: GetFBAInventorySnapshotsSince :many
+select *
+from fba_inventory_snapshot
+where snapshot_at >= ?
+order by sku,
+  snapshot_at;
+-- name: GetFBAInventorySnapshotsAt :many
+select *
+from fba_inventory_snapshot
+where snapshot_at = ?
+order by sku;
+-- name: ListFBAInventorySnapshots :many
+select snapshot_at,
+  count(sku) as sku_count,
+  cast(coalesce(sum(total_quantity), 0) as integer) as total_quantity,
+  cast(coalesce(sum(fulfillable_quantity), 0) as integer) as fulfillable_quantity
+from fba_inventory_snapshot
+group by snapshot_at
+order by snapshot_at desc;
+-- name: GetInventoryAlertStates :many
+select *
+from inventory_alert_state;
+-- name: InsertInventoryAlertState :exec
+insert into inventory_alert_state (rule, sku, notifier, fired_at, value)
+values (?, ?, ?, ?, ?);
+-- name: DeleteInventoryAlertState :exec
+delete from inventory_alert_state
+where rule = ?
+  and sku = ?;
+-- name: ClearInventoryAlertStates :exec
+delete from inventory_alert_state;
+-- name: GetInboundPlan :one
+select *
+from inbound_plan
+where inbound_plan_id = ?;
+-- name: InsertInboundPlan :exec
+insert into inbound_plan (
+    inbound_plan_id,
+    name,
+    marketplace_id,
+    step,
+    source_address,
+    created_at,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?, ?) on conflict (inbound_plan_id) do nothing;
+-- name: UpdateInboundPlanStep :exec
+update inbound_plan
+set step = ?,
+  updated_at = ?
+where inbound_plan_id = ?;
+-- name: UpdateInboundPlanPackingOption :exec
+update inbound_plan
+set packing_option_id = ?,
+  updated_at = ?
+where inbound_plan_id = ?;
+-- name: UpdateInboundPlanPlacementOption :exec
+update inbound_plan
+set placement_option_id = ?,
+  updated_at = ?
+where inbound_plan_id = ?;
+-- name: ListInboundPlans :many
+select *
+from inbound_plan
+order by created_at desc;
+-- name: UpdateInboundPlanDetails :exec
+update inbound_plan
+set name = ?,
+  status = ?,
+  source_address = ?,
+  synced_at = ?
+where inbound_plan_id = ?;
+-- name: GetInboundPlanShipments :many
+select *
+from inbound_plan_shipment
+where inbound_plan_id = ?
+order by shipment_id;
+-- name: FindInboundPlanShipment :one
+select *
+from inbound_plan_shipment
+where shipment_id = sqlc.arg(id)
+  or confirmation_id = sqlc.arg(id)
+limit 1;
+-- name: InsertInboundPlanShipment :exec
+insert into inbound_plan_shipment (inbound_plan_id, shipment_id)
+values (?, ?) on conflict (inbound_plan_id, shipment_id) do nothing;
+-- name: DeleteInboundPlanShipments :exec
+delete from inbound_plan_shipment
+where inbound_plan_id = ?;
+-- name: DeleteInboundPlanShipment :exec
+delete from inbound_plan_shipment
+where inbound_plan_id = ?
+  and shipment_id = ?;
+-- name: UpdateInboundPlanShipmentTransportationOption :exec
+update inbound_plan_shipment
+set transportation_option_id = ?
+where inbound_plan_id = ?
+  and shipment_id = ?;
+-- name: UpdateInboundPlanShipmentDeliveryWindowOption :exec
+update inbound_plan_shipment
+set delivery_window_option_id = ?
+where inbound_plan_id = ?
+  and shipment_id = ?;
+-- name: UpdateInboundPlanShipmentConfirmationID :exec
+update inbound_plan_shipment
+set confirmation_id = ?
+where inbound_plan_id = ?
+  and shipment_id = ?;
+-- name: UpdateInboundPlanShipmentDetails :exec
+update inbound_plan_shipment
+set name = ?,
+  status = ?,
+  destination_warehouse = ?,
+  amazon_reference_id = ?,
+  source_address = ?
+where inbound_plan_id = ?
+  and shipment_id = ?;
+-- name: GetInboundPlanItems :many
+select *
+from inbound_plan_item
+where inbound_plan_id = ?
+order by msku;
+-- name: InsertInboundPlanItem :exec
+insert into inbound_plan_item (
+    inbound_plan_id,
+    msku,
+    asin,
+    fnsku,
+    quantity,
+    prep_owner,
+    label_owner
+  )
+values (?, ?, ?, ?, ?, ?, ?);
+-- name: DeleteInboundPlanItems :exec
+delete from inbound_plan_item
+where inbound_plan_id = ?;
+-- name: GetInboundPlanShipmentItems :many
+select *
+from inbound_plan_shipment_item
+where inbound_plan_id = ?
+  and shipment_id = ?
+order by msku;
+-- name: InsertInboundPlanShipmentItem :exec
+insert into inbound_plan_shipment_item (
+    inbound_plan_id,
+    shipment_id,
+    msku,
+    asin,
+    fnsku,
+    quantity
+  )
+values (?, ?, ?, ?, ?, ?);
+-- name: DeleteInboundPlanShipmentItems :exec
+delete from inbound_plan_shipment_item
+where inbound_plan_id = ?;
+-- name: GetInboundPlanBoxes :many
+select *
+from inbound_plan_box
+where inbound_plan_id = ?
+order by package_id;
+-- name: InsertInboundPlanBox :exec
+insert into inbound_plan_box (
+    inbound_plan_id,
+    package_id,
+    box_id,
+    quantity,
+    length,
+    width,
+    height,
+    dimension_unit,
+    weight,
+    weight_unit,
+    item_count,
+    destination_warehouse
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+-- name: DeleteInboundPlanBoxes :exec
+delete from inbound_plan_box
+where inbound_plan_id = ?;
+-- name: GetInboundPlanOperations :many
+select *
+from inbound_plan_operation
+where inbound_plan_id = ?
+order by created_at;
+-- name: UpsertInboundPlanOperation :exec
+insert into inbound_plan_operation (
+    operation_id,
+    inbound_plan_id,
+    operation,
+    status,
+    created_at,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?) on conflict (operation_id) do
+update
+set status = excluded.status,
+  updated_at = excluded.updated_at;
+-- name: GetInboundPlanPlacements :many
+select *
+from inbound_plan_placement
+where inbound_plan_id = ?
+order by confirmed_at;
+-- name: UpsertInboundPlanPlacement :exec
+insert into inbound_plan_placement (
+    inbound_plan_id,
+    placement_option_id,
+    shipment_count,
+    warehouses,
+    fees,
+    discounts,
+    currency,
+    policy,
+    confirmed_at
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?) on conflict (inbound_plan_id, placement_option_id) do
+update
+set shipment_count = excluded.shipment_count,
+  warehouses = excluded.warehouses,
+  fees = excluded.fees,
+  discounts = excluded.discounts,
+  currency = excluded.currency,
+  policy = excluded.policy,
+  confirmed_at = excluded.confirmed_at;
+-- name: ListItemRequirements :many
+select *
+from item_requirements
+where merchant = ?
+  and marketplace_id = ?
+order by msku;
+-- name: UpsertItemRequirement :exec
+insert into item_requirements (
+    merchant,
+    marketplace_id,
+    msku,
+    prep_owner,
+    label_owner,
+    source,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?, ?) on conflict (merchant, marketplace_id, msku) do
+update
+set prep_owner = excluded.prep_owner,
+  label_owner = excluded.label_owner,
+  source = excluded.source,
+  updated_at = excluded.updated_at;
+-- name: DeleteItemRequirement :exec
+delete from item_requirements
+where merchant = ?
+  and marketplace_id = ?
+  and msku = ?;
+-- name: DeleteItemRequirements :exec
+delete from item_requirements
+where merchant = ?
+  and marketplace_id = ?;
+-- name: GetCatalogItemDimension :one
+select *
+from catalog_item_dimensions
+where marketplace_id = ?
+  and asin = ?;
+-- name: UpsertCatalogItemDimension :exec
+insert into catalog_item_dimensions (
+    marketplace_id,
+    asin,
+    length,
+    width,
+    height,
+    weight,
+    source,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?) on conflict (marketplace_id, asin) do
+update
+set length = excluded.length,
+  width = excluded.width,
+  height = excluded.height,
+  weight = excluded.weight,
+  source = excluded.source,
+  updated_at = excluded.updated_at;
+-- name: ListInventoryIdentifiers :many
+select sku,
+  asin,
+  upc,
+  fnsku,
+  title
+from fba_inventory
+where sku is not null
+  and sku != '';
+-- name: GetProductTypeSchema :one
+select *
+from product_type_schema
+where checksum = ?;
+-- name: InsertProductTypeSchema :exec
+insert into product_type_schema (checksum, product_type, schema, fetched_at)
+values (?, ?, ?, ?) on conflict (checksum) do nothing;
+-- name: GetLatestProductTypeDefinition :one
+select *
+from product_type_definition
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+  and latest = true
+order by fetched_at desc
+limit 1;
+-- name: GetProductTypeDefinitionVersion :one
+select *
+from product_type_definition
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+  and version = ?;
+-- name: ListProductTypeDefinitionVersions :many
+select *
+from product_type_definition
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+order by fetched_at desc;
+-- name: UpsertProductTypeDefinition :exec
+insert into product_type_definition (
+    product_type,
+    marketplace_id,
+    requirements,
+    locale,
+    version,
+    latest,
+    definition,
+    schema_checksum,
+    fetched_at
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?) on conflict (
+    product_type,
+    marketplace_id,
+    requirements,
+    locale,
+    version
+  ) do
+update
+set latest = excluded.latest,
+  definition = excluded.definition,
+  schema_checksum = excluded.schema_checksum,
+  fetched_at = excluded.fetched_at;
+-- name: ClearLatestProductTypeDefinition :exec
+update product_type_definition
+set latest = false
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+  and version != ?;
@@ -8,8 +8,153 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
+const clearInventoryAlertStates = `-- name: ClearInventoryAlertStates :exec
+delete from inventory_alert_state
+`
+
+func (q *Queries) ClearInventoryAlertStates(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, clearInventoryAlertStates)
+	return err
+}
+
+const clearLatestProductTypeDefinition = `-- name: ClearLatestProductTypeDefinition :exec
+update product_type_definition
+set latest = false
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+  and version != ?
+`
+
+type ClearLatestProductTypeDefinitionParams struct {
+	ProductType   string
+	MarketplaceID string
+	Requirements  string
+	Locale        string
+	Version       string
+}
+
+func (q *Queries) ClearLatestProductTypeDefinition(ctx context.Context, arg ClearLatestProductTypeDefinitionParams) error {
+	_, err := q.db.ExecContext(ctx, clearLatestProductTypeDefinition,
+		arg.ProductType,
+		arg.MarketplaceID,
+		arg.Requirements,
+		arg.Locale,
+		arg.Version,
+	)
+	return err
+}
+
+const deleteInboundPlanBoxes = `-- name: DeleteInboundPlanBoxes :exec
+delete from inbound_plan_box
+where inbound_plan_id = ?
+`
+
+func (q *Queries) DeleteInboundPlanBoxes(ctx context.Context, inboundPlanID string) error {
+	_, err := q.db.ExecContext(ctx, deleteInboundPlanBoxes, inboundPlanID)
+	return err
+}
+
+const deleteInboundPlanItems = `-- name: DeleteInboundPlanItems :exec
+delete from inbound_plan_item
+where inbound_plan_id = ?
+`
+
+func (q *Queries) DeleteInboundPlanItems(ctx context.Context, inboundPlanID string) error {
+	_, err := q.db.ExecContext(ctx, deleteInboundPlanItems, inboundPlanID)
+	return err
+}
+
+const deleteInboundPlanShipment = `-- name: DeleteInboundPlanShipment :exec
+delete from inbound_plan_shipment
+where inbound_plan_id = ?
+  and shipment_id = ?
+`
+
+type DeleteInboundPlanShipmentParams struct {
+	InboundPlanID string
+	ShipmentID    string
+}
+
+func (q *Queries) DeleteInboundPlanShipment(ctx context.Context, arg DeleteInboundPlanShipmentParams) error {
+	_, err := q.db.ExecContext(ctx, deleteInboundPlanShipment, arg.InboundPlanID, arg.ShipmentID)
+	return err
+}
+
+const deleteInboundPlanShipmentItems = `-- name: DeleteInboundPlanShipmentItems :exec
+delete from inbound_plan_shipment_item
+where inbound_plan_id = ?
+`
+
+func (q *Queries) DeleteInboundPlanShipmentItems(ctx context.Context, inboundPlanID string) error {
+	_, err := q.db.ExecContext(ctx, deleteInboundPlanShipmentItems, inboundPlanID)
+	return err
+}
+
+const deleteInboundPlanShipments = `-- name: DeleteInboundPlanShipments :exec
+delete from inbound_plan_shipment
+where inbound_plan_id = ?
+`
+
+func (q *Queries) DeleteInboundPlanShipments(ctx context.Context, inboundPlanID string) error {
+	_, err := q.db.ExecContext(ctx, deleteInboundPlanShipments, inboundPlanID)
+	return err
+}
+
+const deleteInventoryAlertState = `-- name: DeleteInventoryAlertState :exec
+delete from inventory_alert_state
+where rule = ?
+  and sku = ?
+`
+
+type DeleteInventoryAlertStateParams struct {
+	Rule string
+	Sku  string
+}
+
+func (q *Queries) DeleteInventoryAlertState(ctx context.Context, arg DeleteInventoryAlertStateParams) error {
+	_, err := q.db.ExecContext(ctx, deleteInventoryAlertState, arg.Rule, arg.Sku)
+	return err
+}
+
+const deleteItemRequirement = `-- name: DeleteItemRequirement :exec
+delete from item_requirements
+where merchant = ?
+  and marketplace_id = ?
+  and msku = ?
+`
+
+type DeleteItemRequirementParams struct {
+	Merchant      string
+	MarketplaceID string
+	Msku          string
+}
+
+func (q *Queries) DeleteItemRequirement(ctx context.Context, arg DeleteItemRequirementParams) error {
+	_, err := q.db.ExecContext(ctx, deleteItemRequirement, arg.Merchant, arg.MarketplaceID, arg.Msku)
+	return err
+}
+
+const deleteItemRequirements = `-- name: DeleteItemRequirements :exec
+delete from item_requirements
+where merchant = ?
+  and marketplace_id = ?
+`
+
+type DeleteItemRequirementsParams struct {
+	Merchant      string
+	MarketplaceID string
+}
+
+func (q *Queries) DeleteItemRequirements(ctx context.Context, arg DeleteItemRequirementsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteItemRequirements, arg.Merchant, arg.MarketplaceID)
+	return err
+}
+
 const fbaInventoryCount = `-- name: FbaInventoryCount :one
 select COUNT(sku)
 from fba_inventory
@@ -23,6 +168,32 @@ func (q *Queries) FbaInventoryCount(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const findInboundPlanShipment = `-- name: FindInboundPlanShipment :one
+select inbound_plan_id, shipment_id, transportation_option_id, delivery_window_option_id, confirmation_id, name, status, destination_warehouse, amazon_reference_id, source_address
+from inbound_plan_shipment
+where shipment_id = ?1
+  or confirmation_id = ?1
+limit 1
+`
+
+func (q *Queries) FindInboundPlanShipment(ctx context.Context, id string) (InboundPlanShipment, error) {
+	row := q.db.QueryRowContext(ctx, findInboundPlanShipment, id)
+	var i InboundPlanShipment
+	err := row.Scan(
+		&i.InboundPlanID,
+		&i.ShipmentID,
+		&i.TransportationOptionID,
+		&i.DeliveryWindowOptionID,
+		&i.ConfirmationID,
+		&i.Name,
+		&i.Status,
+		&i.DestinationWarehouse,
+		&i.AmazonReferenceID,
+		&i.SourceAddress,
+	)
+	return i, err
+}
+
 const getAsinToSkuMapContents = `-- name: GetAsinToSkuMapContents :many
 select sku,
   asin,
@@ -59,8 +230,121 @@ func (q *Queries) GetAsinToSkuMapContents(ctx context.Context) ([]GetAsinToSkuMa
 	return items, nil
 }
 
+const getCatalogItemDimension = `-- name: GetCatalogItemDimension :one
+select marketplace_id, asin, length, width, height, weight, source, updated_at
+from catalog_item_dimensions
+where marketplace_id = ?
+  and asin = ?
+`
+
+type GetCatalogItemDimensionParams struct {
+	MarketplaceID string
+	Asin          string
+}
+
+func (q *Queries) GetCatalogItemDimension(ctx context.Context, arg GetCatalogItemDimensionParams) (CatalogItemDimension, error) {
+	row := q.db.QueryRowContext(ctx, getCatalogItemDimension, arg.MarketplaceID, arg.Asin)
+	var i CatalogItemDimension
+	err := row.Scan(
+		&i.MarketplaceID,
+		&i.Asin,
+		&i.Length,
+		&i.Width,
+		&i.Height,
+		&i.Weight,
+		&i.Source,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFBAInventorySnapshotsAt = `-- name: GetFBAInventorySnapshotsAt :many
+select snapshot_at, sku, asin, title, total_quantity, fulfillable_quantity, inbound_working_quantity, inbound_shipped_quantity, inbound_receiving_quantity, reserved_quantity, unfulfillable_quantity
+from fba_inventory_snapshot
+where snapshot_at = ?
+order by sku
+`
+
+func (q *Queries) GetFBAInventorySnapshotsAt(ctx context.Context, snapshotAt time.Time) ([]FbaInventorySnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, getFBAInventorySnapshotsAt, snapshotAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FbaInventorySnapshot
+	for rows.Next() {
+		var i FbaInventorySnapshot
+		if err := rows.Scan(
+			&i.SnapshotAt,
+			&i.Sku,
+			&i.Asin,
+			&i.Title,
+			&i.TotalQuantity,
+			&i.FulfillableQuantity,
+			&i.InboundWorkingQuantity,
+			&i.InboundShippedQuantity,
+			&i.InboundReceivingQuantity,
+			&i.ReservedQuantity,
+			&i.UnfulfillableQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFBAInventorySnapshotsSince = `-- name: GetFBAInventorySnapshotsSince :many
+select snapshot_at, sku, asin, title, total_quantity, fulfillable_quantity, inbound_working_quantity, inbound_shipped_quantity, inbound_receiving_quantity, reserved_quantity, unfulfillable_quantity
+from fba_inventory_snapshot
+where snapshot_at >= ?
+order by sku,
+  snapshot_at
+`
+
+func (q *Queries) GetFBAInventorySnapshotsSince(ctx context.Context, snapshotAt time.Time) ([]FbaInventorySnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, getFBAInventorySnapshotsSince, snapshotAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FbaInventorySnapshot
+	for rows.Next() {
+		var i FbaInventorySnapshot
+		if err := rows.Scan(
+			&i.SnapshotAt,
+			&i.Sku,
+			&i.Asin,
+			&i.Title,
+			&i.TotalQuantity,
+			&i.FulfillableQuantity,
+			&i.InboundWorkingQuantity,
+			&i.InboundShippedQuantity,
+			&i.InboundReceivingQuantity,
+			&i.ReservedQuantity,
+			&i.UnfulfillableQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFBAProductFromAsin = `-- name: GetFBAProductFromAsin :one
-select title, total_quantity, fulfillable_quantity, inbound_receiving_quantity, inbound_shipped_quantity, sku, asin
+select id, title, total_quantity, fulfillable_quantity, inbound_receiving_quantity, inbound_shipped_quantity, sku, asin, upc, fnsku, marketplace_id
 from fba_inventory
 where asin = ?
 `
@@ -69,6 +353,7 @@ func (q *Queries) GetFBAProductFromAsin(ctx context.Context, asin sql.NullString
 	row := q.db.QueryRowContext(ctx, getFBAProductFromAsin, asin)
 	var i FbaInventory
 	err := row.Scan(
+		&i.ID,
 		&i.Title,
 		&i.TotalQuantity,
 		&i.FulfillableQuantity,
@@ -76,6 +361,1304 @@ func (q *Queries) GetFBAProductFromAsin(ctx context.Context, asin sql.NullString
 		&i.InboundShippedQuantity,
 		&i.Sku,
 		&i.Asin,
+		&i.Upc,
+		&i.Fnsku,
+		&i.MarketplaceID,
+	)
+	return i, err
+}
+
+const getInboundPlan = `-- name: GetInboundPlan :one
+select inbound_plan_id, name, marketplace_id, step, packing_option_id, placement_option_id, created_at, updated_at, status, source_address, synced_at
+from inbound_plan
+where inbound_plan_id = ?
+`
+
+func (q *Queries) GetInboundPlan(ctx context.Context, inboundPlanID string) (InboundPlan, error) {
+	row := q.db.QueryRowContext(ctx, getInboundPlan, inboundPlanID)
+	var i InboundPlan
+	err := row.Scan(
+		&i.InboundPlanID,
+		&i.Name,
+		&i.MarketplaceID,
+		&i.Step,
+		&i.PackingOptionID,
+		&i.PlacementOptionID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.SourceAddress,
+		&i.SyncedAt,
 	)
 	return i, err
 }
+
+const getInboundPlanBoxes = `-- name: GetInboundPlanBoxes :many
+select inbound_plan_id, package_id, box_id, quantity, length, width, height, dimension_unit, weight, weight_unit, item_count, destination_warehouse
+from inbound_plan_box
+where inbound_plan_id = ?
+order by package_id
+`
+
+func (q *Queries) GetInboundPlanBoxes(ctx context.Context, inboundPlanID string) ([]InboundPlanBox, error) {
+	rows, err := q.db.QueryContext(ctx, getInboundPlanBoxes, inboundPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboundPlanBox
+	for rows.Next() {
+		var i InboundPlanBox
+		if err := rows.Scan(
+			&i.InboundPlanID,
+			&i.PackageID,
+			&i.BoxID,
+			&i.Quantity,
+			&i.Length,
+			&i.Width,
+			&i.Height,
+			&i.DimensionUnit,
+			&i.Weight,
+			&i.WeightUnit,
+			&i.ItemCount,
+			&i.DestinationWarehouse,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInboundPlanItems = `-- name: GetInboundPlanItems :many
+select inbound_plan_id, msku, asin, fnsku, quantity, prep_owner, label_owner
+from inbound_plan_item
+where inbound_plan_id = ?
+order by msku
+`
+
+func (q *Queries) GetInboundPlanItems(ctx context.Context, inboundPlanID string) ([]InboundPlanItem, error) {
+	rows, err := q.db.QueryContext(ctx, getInboundPlanItems, inboundPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboundPlanItem
+	for rows.Next() {
+		var i InboundPlanItem
+		if err := rows.Scan(
+			&i.InboundPlanID,
+			&i.Msku,
+			&i.Asin,
+			&i.Fnsku,
+			&i.Quantity,
+			&i.PrepOwner,
+			&i.LabelOwner,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInboundPlanOperations = `-- name: GetInboundPlanOperations :many
+select operation_id, inbound_plan_id, operation, status, created_at, updated_at
+from inbound_plan_operation
+where inbound_plan_id = ?
+order by created_at
+`
+
+func (q *Queries) GetInboundPlanOperations(ctx context.Context, inboundPlanID string) ([]InboundPlanOperation, error) {
+	rows, err := q.db.QueryContext(ctx, getInboundPlanOperations, inboundPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboundPlanOperation
+	for rows.Next() {
+		var i InboundPlanOperation
+		if err := rows.Scan(
+			&i.OperationID,
+			&i.InboundPlanID,
+			&i.Operation,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInboundPlanPlacements = `-- name: GetInboundPlanPlacements :many
+select inbound_plan_id, placement_option_id, shipment_count, warehouses, fees, discounts, currency, policy, confirmed_at
+from inbound_plan_placement
+where inbound_plan_id = ?
+order by confirmed_at
+`
+
+func (q *Queries) GetInboundPlanPlacements(ctx context.Context, inboundPlanID string) ([]InboundPlanPlacement, error) {
+	rows, err := q.db.QueryContext(ctx, getInboundPlanPlacements, inboundPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboundPlanPlacement
+	for rows.Next() {
+		var i InboundPlanPlacement
+		if err := rows.Scan(
+			&i.InboundPlanID,
+			&i.PlacementOptionID,
+			&i.ShipmentCount,
+			&i.Warehouses,
+			&i.Fees,
+			&i.Discounts,
+			&i.Currency,
+			&i.Policy,
+			&i.ConfirmedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInboundPlanShipmentItems = `-- name: GetInboundPlanShipmentItems :many
+select inbound_plan_id, shipment_id, msku, asin, fnsku, quantity
+from inbound_plan_shipment_item
+where inbound_plan_id = ?
+  and shipment_id = ?
+order by msku
+`
+
+type GetInboundPlanShipmentItemsParams struct {
+	InboundPlanID string
+	ShipmentID    string
+}
+
+func (q *Queries) GetInboundPlanShipmentItems(ctx context.Context, arg GetInboundPlanShipmentItemsParams) ([]InboundPlanShipmentItem, error) {
+	rows, err := q.db.QueryContext(ctx, getInboundPlanShipmentItems, arg.InboundPlanID, arg.ShipmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboundPlanShipmentItem
+	for rows.Next() {
+		var i InboundPlanShipmentItem
+		if err := rows.Scan(
+			&i.InboundPlanID,
+			&i.ShipmentID,
+			&i.Msku,
+			&i.Asin,
+			&i.Fnsku,
+			&i.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInboundPlanShipments = `-- name: GetInboundPlanShipments :many
+select inbound_plan_id, shipment_id, transportation_option_id, delivery_window_option_id, confirmation_id, name, status, destination_warehouse, amazon_reference_id, source_address
+from inbound_plan_shipment
+where inbound_plan_id = ?
+order by shipment_id
+`
+
+func (q *Queries) GetInboundPlanShipments(ctx context.Context, inboundPlanID string) ([]InboundPlanShipment, error) {
+	rows, err := q.db.QueryContext(ctx, getInboundPlanShipments, inboundPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboundPlanShipment
+	for rows.Next() {
+		var i InboundPlanShipment
+		if err := rows.Scan(
+			&i.InboundPlanID,
+			&i.ShipmentID,
+			&i.TransportationOptionID,
+			&i.DeliveryWindowOptionID,
+			&i.ConfirmationID,
+			&i.Name,
+			&i.Status,
+			&i.DestinationWarehouse,
+			&i.AmazonReferenceID,
+			&i.SourceAddress,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInventoryAlertStates = `-- name: GetInventoryAlertStates :many
+select rule, sku, notifier, fired_at, value
+from inventory_alert_state
+`
+
+func (q *Queries) GetInventoryAlertStates(ctx context.Context) ([]InventoryAlertState, error) {
+	rows, err := q.db.QueryContext(ctx, getInventoryAlertStates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InventoryAlertState
+	for rows.Next() {
+		var i InventoryAlertState
+		if err := rows.Scan(
+			&i.Rule,
+			&i.Sku,
+			&i.Notifier,
+			&i.FiredAt,
+			&i.Value,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLatestProductTypeDefinition = `-- name: GetLatestProductTypeDefinition :one
+select product_type, marketplace_id, requirements, locale, version, latest, definition, schema_checksum, fetched_at
+from product_type_definition
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+  and latest = true
+order by fetched_at desc
+limit 1
+`
+
+type GetLatestProductTypeDefinitionParams struct {
+	ProductType   string
+	MarketplaceID string
+	Requirements  string
+	Locale        string
+}
+
+func (q *Queries) GetLatestProductTypeDefinition(ctx context.Context, arg GetLatestProductTypeDefinitionParams) (ProductTypeDefinition, error) {
+	row := q.db.QueryRowContext(ctx, getLatestProductTypeDefinition,
+		arg.ProductType,
+		arg.MarketplaceID,
+		arg.Requirements,
+		arg.Locale,
+	)
+	var i ProductTypeDefinition
+	err := row.Scan(
+		&i.ProductType,
+		&i.MarketplaceID,
+		&i.Requirements,
+		&i.Locale,
+		&i.Version,
+		&i.Latest,
+		&i.Definition,
+		&i.SchemaChecksum,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
+const getProductTypeDefinitionVersion = `-- name: GetProductTypeDefinitionVersion :one
+select product_type, marketplace_id, requirements, locale, version, latest, definition, schema_checksum, fetched_at
+from product_type_definition
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+  and version = ?
+`
+
+type GetProductTypeDefinitionVersionParams struct {
+	ProductType   string
+	MarketplaceID string
+	Requirements  string
+	Locale        string
+	Version       string
+}
+
+func (q *Queries) GetProductTypeDefinitionVersion(ctx context.Context, arg GetProductTypeDefinitionVersionParams) (ProductTypeDefinition, error) {
+	row := q.db.QueryRowContext(ctx, getProductTypeDefinitionVersion,
+		arg.ProductType,
+		arg.MarketplaceID,
+		arg.Requirements,
+		arg.Locale,
+		arg.Version,
+	)
+	var i ProductTypeDefinition
+	err := row.Scan(
+		&i.ProductType,
+		&i.MarketplaceID,
+		&i.Requirements,
+		&i.Locale,
+		&i.Version,
+		&i.Latest,
+		&i.Definition,
+		&i.SchemaChecksum,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
+const getProductTypeSchema = `-- name: GetProductTypeSchema :one
+select checksum, product_type, schema, fetched_at
+from product_type_schema
+where checksum = ?
+`
+
+func (q *Queries) GetProductTypeSchema(ctx context.Context, checksum string) (ProductTypeSchema, error) {
+	row := q.db.QueryRowContext(ctx, getProductTypeSchema, checksum)
+	var i ProductTypeSchema
+	err := row.Scan(
+		&i.Checksum,
+		&i.ProductType,
+		&i.Schema,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
+const insertFBAInventorySnapshot = `-- name: InsertFBAInventorySnapshot :exec
+insert into fba_inventory_snapshot (
+    snapshot_at,
+    sku,
+    asin,
+    title,
+    total_quantity,
+    fulfillable_quantity,
+    inbound_working_quantity,
+    inbound_shipped_quantity,
+    inbound_receiving_quantity,
+    reserved_quantity,
+    unfulfillable_quantity
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertFBAInventorySnapshotParams struct {
+	SnapshotAt               time.Time
+	Sku                      string
+	Asin                     sql.NullString
+	Title                    sql.NullString
+	TotalQuantity            sql.NullInt64
+	FulfillableQuantity      sql.NullInt64
+	InboundWorkingQuantity   sql.NullInt64
+	InboundShippedQuantity   sql.NullInt64
+	InboundReceivingQuantity sql.NullInt64
+	ReservedQuantity         sql.NullInt64
+	UnfulfillableQuantity    sql.NullInt64
+}
+
+func (q *Queries) InsertFBAInventorySnapshot(ctx context.Context, arg InsertFBAInventorySnapshotParams) error {
+	_, err := q.db.ExecContext(ctx, insertFBAInventorySnapshot,
+		arg.SnapshotAt,
+		arg.Sku,
+		arg.Asin,
+		arg.Title,
+		arg.TotalQuantity,
+		arg.FulfillableQuantity,
+		arg.InboundWorkingQuantity,
+		arg.InboundShippedQuantity,
+		arg.InboundReceivingQuantity,
+		arg.ReservedQuantity,
+		arg.UnfulfillableQuantity,
+	)
+	return err
+}
+
+const insertInboundPlan = `-- name: InsertInboundPlan :exec
+insert into inbound_plan (
+    inbound_plan_id,
+    name,
+    marketplace_id,
+    step,
+    source_address,
+    created_at,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?, ?) on conflict (inbound_plan_id) do nothing
+`
+
+type InsertInboundPlanParams struct {
+	InboundPlanID string
+	Name          sql.NullString
+	MarketplaceID sql.NullString
+	Step          string
+	SourceAddress sql.NullString
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) InsertInboundPlan(ctx context.Context, arg InsertInboundPlanParams) error {
+	_, err := q.db.ExecContext(ctx, insertInboundPlan,
+		arg.InboundPlanID,
+		arg.Name,
+		arg.MarketplaceID,
+		arg.Step,
+		arg.SourceAddress,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const insertInboundPlanBox = `-- name: InsertInboundPlanBox :exec
+insert into inbound_plan_box (
+    inbound_plan_id,
+    package_id,
+    box_id,
+    quantity,
+    length,
+    width,
+    height,
+    dimension_unit,
+    weight,
+    weight_unit,
+    item_count,
+    destination_warehouse
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertInboundPlanBoxParams struct {
+	InboundPlanID        string
+	PackageID            string
+	BoxID                sql.NullString
+	Quantity             sql.NullInt64
+	Length               sql.NullFloat64
+	Width                sql.NullFloat64
+	Height               sql.NullFloat64
+	DimensionUnit        sql.NullString
+	Weight               sql.NullFloat64
+	WeightUnit           sql.NullString
+	ItemCount            sql.NullInt64
+	DestinationWarehouse sql.NullString
+}
+
+func (q *Queries) InsertInboundPlanBox(ctx context.Context, arg InsertInboundPlanBoxParams) error {
+	_, err := q.db.ExecContext(ctx, insertInboundPlanBox,
+		arg.InboundPlanID,
+		arg.PackageID,
+		arg.BoxID,
+		arg.Quantity,
+		arg.Length,
+		arg.Width,
+		arg.Height,
+		arg.DimensionUnit,
+		arg.Weight,
+		arg.WeightUnit,
+		arg.ItemCount,
+		arg.DestinationWarehouse,
+	)
+	return err
+}
+
+const insertInboundPlanItem = `-- name: InsertInboundPlanItem :exec
+insert into inbound_plan_item (
+    inbound_plan_id,
+    msku,
+    asin,
+    fnsku,
+    quantity,
+    prep_owner,
+    label_owner
+  )
+values (?, ?, ?, ?, ?, ?, ?)
+`
+
+type InsertInboundPlanItemParams struct {
+	InboundPlanID string
+	Msku          string
+	Asin          sql.NullString
+	Fnsku         sql.NullString
+	Quantity      int64
+	PrepOwner     sql.NullString
+	LabelOwner    sql.NullString
+}
+
+func (q *Queries) InsertInboundPlanItem(ctx context.Context, arg InsertInboundPlanItemParams) error {
+	_, err := q.db.ExecContext(ctx, insertInboundPlanItem,
+		arg.InboundPlanID,
+		arg.Msku,
+		arg.Asin,
+		arg.Fnsku,
+		arg.Quantity,
+		arg.PrepOwner,
+		arg.LabelOwner,
+	)
+	return err
+}
+
+const insertInboundPlanShipment = `-- name: InsertInboundPlanShipment :exec
+insert into inbound_plan_shipment (inbound_plan_id, shipment_id)
+values (?, ?) on conflict (inbound_plan_id, shipment_id) do nothing
+`
+
+type InsertInboundPlanShipmentParams struct {
+	InboundPlanID string
+	ShipmentID    string
+}
+
+func (q *Queries) InsertInboundPlanShipment(ctx context.Context, arg InsertInboundPlanShipmentParams) error {
+	_, err := q.db.ExecContext(ctx, insertInboundPlanShipment, arg.InboundPlanID, arg.ShipmentID)
+	return err
+}
+
+const insertInboundPlanShipmentItem = `-- name: InsertInboundPlanShipmentItem :exec
+insert into inbound_plan_shipment_item (
+    inbound_plan_id,
+    shipment_id,
+    msku,
+    asin,
+    fnsku,
+    quantity
+  )
+values (?, ?, ?, ?, ?, ?)
+`
+
+type InsertInboundPlanShipmentItemParams struct {
+	InboundPlanID string
+	ShipmentID    string
+	Msku          string
+	Asin          sql.NullString
+	Fnsku         sql.NullString
+	Quantity      int64
+}
+
+func (q *Queries) InsertInboundPlanShipmentItem(ctx context.Context, arg InsertInboundPlanShipmentItemParams) error {
+	_, err := q.db.ExecContext(ctx, insertInboundPlanShipmentItem,
+		arg.InboundPlanID,
+		arg.ShipmentID,
+		arg.Msku,
+		arg.Asin,
+		arg.Fnsku,
+		arg.Quantity,
+	)
+	return err
+}
+
+const insertInventoryAlertState = `-- name: InsertInventoryAlertState :exec
+insert into inventory_alert_state (rule, sku, notifier, fired_at, value)
+values (?, ?, ?, ?, ?)
+`
+
+type InsertInventoryAlertStateParams struct {
+	Rule     string
+	Sku      string
+	Notifier string
+	FiredAt  time.Time
+	Value    float64
+}
+
+func (q *Queries) InsertInventoryAlertState(ctx context.Context, arg InsertInventoryAlertStateParams) error {
+	_, err := q.db.ExecContext(ctx, insertInventoryAlertState,
+		arg.Rule,
+		arg.Sku,
+		arg.Notifier,
+		arg.FiredAt,
+		arg.Value,
+	)
+	return err
+}
+
+const insertProductTypeSchema = `-- name: InsertProductTypeSchema :exec
+insert into product_type_schema (checksum, product_type, schema, fetched_at)
+values (?, ?, ?, ?) on conflict (checksum) do nothing
+`
+
+type InsertProductTypeSchemaParams struct {
+	Checksum    string
+	ProductType string
+	Schema      string
+	FetchedAt   time.Time
+}
+
+func (q *Queries) InsertProductTypeSchema(ctx context.Context, arg InsertProductTypeSchemaParams) error {
+	_, err := q.db.ExecContext(ctx, insertProductTypeSchema,
+		arg.Checksum,
+		arg.ProductType,
+		arg.Schema,
+		arg.FetchedAt,
+	)
+	return err
+}
+
+const listFBAInventorySnapshots = `-- name: ListFBAInventorySnapshots :many
+select snapshot_at,
+  count(sku) as sku_count,
+  cast(coalesce(sum(total_quantity), 0) as integer) as total_quantity,
+  cast(coalesce(sum(fulfillable_quantity), 0) as integer) as fulfillable_quantity
+from fba_inventory_snapshot
+group by snapshot_at
+order by snapshot_at desc
+`
+
+type ListFBAInventorySnapshotsRow struct {
+	SnapshotAt          time.Time
+	SkuCount            int64
+	TotalQuantity       int64
+	FulfillableQuantity int64
+}
+
+func (q *Queries) ListFBAInventorySnapshots(ctx context.Context) ([]ListFBAInventorySnapshotsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFBAInventorySnapshots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFBAInventorySnapshotsRow
+	for rows.Next() {
+		var i ListFBAInventorySnapshotsRow
+		if err := rows.Scan(
+			&i.SnapshotAt,
+			&i.SkuCount,
+			&i.TotalQuantity,
+			&i.FulfillableQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInboundPlans = `-- name: ListInboundPlans :many
+select inbound_plan_id, name, marketplace_id, step, packing_option_id, placement_option_id, created_at, updated_at, status, source_address, synced_at
+from inbound_plan
+order by created_at desc
+`
+
+func (q *Queries) ListInboundPlans(ctx context.Context) ([]InboundPlan, error) {
+	rows, err := q.db.QueryContext(ctx, listInboundPlans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboundPlan
+	for rows.Next() {
+		var i InboundPlan
+		if err := rows.Scan(
+			&i.InboundPlanID,
+			&i.Name,
+			&i.MarketplaceID,
+			&i.Step,
+			&i.PackingOptionID,
+			&i.PlacementOptionID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.SourceAddress,
+			&i.SyncedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInventoryIdentifiers = `-- name: ListInventoryIdentifiers :many
+select sku,
+  asin,
+  upc,
+  fnsku,
+  title
+from fba_inventory
+where sku is not null
+  and sku != ''
+`
+
+type ListInventoryIdentifiersRow struct {
+	Sku   sql.NullString
+	Asin  sql.NullString
+	Upc   sql.NullString
+	Fnsku sql.NullString
+	Title sql.NullString
+}
+
+func (q *Queries) ListInventoryIdentifiers(ctx context.Context) ([]ListInventoryIdentifiersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInventoryIdentifiers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInventoryIdentifiersRow
+	for rows.Next() {
+		var i ListInventoryIdentifiersRow
+		if err := rows.Scan(
+			&i.Sku,
+			&i.Asin,
+			&i.Upc,
+			&i.Fnsku,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listItemRequirements = `-- name: ListItemRequirements :many
+select merchant, marketplace_id, msku, prep_owner, label_owner, source, updated_at
+from item_requirements
+where merchant = ?
+  and marketplace_id = ?
+order by msku
+`
+
+type ListItemRequirementsParams struct {
+	Merchant      string
+	MarketplaceID string
+}
+
+func (q *Queries) ListItemRequirements(ctx context.Context, arg ListItemRequirementsParams) ([]ItemRequirement, error) {
+	rows, err := q.db.QueryContext(ctx, listItemRequirements, arg.Merchant, arg.MarketplaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ItemRequirement
+	for rows.Next() {
+		var i ItemRequirement
+		if err := rows.Scan(
+			&i.Merchant,
+			&i.MarketplaceID,
+			&i.Msku,
+			&i.PrepOwner,
+			&i.LabelOwner,
+			&i.Source,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductTypeDefinitionVersions = `-- name: ListProductTypeDefinitionVersions :many
+select product_type, marketplace_id, requirements, locale, version, latest, definition, schema_checksum, fetched_at
+from product_type_definition
+where product_type = ?
+  and marketplace_id = ?
+  and requirements = ?
+  and locale = ?
+order by fetched_at desc
+`
+
+type ListProductTypeDefinitionVersionsParams struct {
+	ProductType   string
+	MarketplaceID string
+	Requirements  string
+	Locale        string
+}
+
+func (q *Queries) ListProductTypeDefinitionVersions(ctx context.Context, arg ListProductTypeDefinitionVersionsParams) ([]ProductTypeDefinition, error) {
+	rows, err := q.db.QueryContext(ctx, listProductTypeDefinitionVersions,
+		arg.ProductType,
+		arg.MarketplaceID,
+		arg.Requirements,
+		arg.Locale,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductTypeDefinition
+	for rows.Next() {
+		var i ProductTypeDefinition
+		if err := rows.Scan(
+			&i.ProductType,
+			&i.MarketplaceID,
+			&i.Requirements,
+			&i.Locale,
+			&i.Version,
+			&i.Latest,
+			&i.Definition,
+			&i.SchemaChecksum,
+			&i.FetchedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateInboundPlanDetails = `-- name: UpdateInboundPlanDetails :exec
+update inbound_plan
+set name = ?,
+  status = ?,
+  source_address = ?,
+  synced_at = ?
+where inbound_plan_id = ?
+`
+
+type UpdateInboundPlanDetailsParams struct {
+	Name          sql.NullString
+	Status        sql.NullString
+	SourceAddress sql.NullString
+	SyncedAt      sql.NullTime
+	InboundPlanID string
+}
+
+func (q *Queries) UpdateInboundPlanDetails(ctx context.Context, arg UpdateInboundPlanDetailsParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanDetails,
+		arg.Name,
+		arg.Status,
+		arg.SourceAddress,
+		arg.SyncedAt,
+		arg.InboundPlanID,
+	)
+	return err
+}
+
+const updateInboundPlanPackingOption = `-- name: UpdateInboundPlanPackingOption :exec
+update inbound_plan
+set packing_option_id = ?,
+  updated_at = ?
+where inbound_plan_id = ?
+`
+
+type UpdateInboundPlanPackingOptionParams struct {
+	PackingOptionID sql.NullString
+	UpdatedAt       time.Time
+	InboundPlanID   string
+}
+
+func (q *Queries) UpdateInboundPlanPackingOption(ctx context.Context, arg UpdateInboundPlanPackingOptionParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanPackingOption, arg.PackingOptionID, arg.UpdatedAt, arg.InboundPlanID)
+	return err
+}
+
+const updateInboundPlanPlacementOption = `-- name: UpdateInboundPlanPlacementOption :exec
+update inbound_plan
+set placement_option_id = ?,
+  updated_at = ?
+where inbound_plan_id = ?
+`
+
+type UpdateInboundPlanPlacementOptionParams struct {
+	PlacementOptionID sql.NullString
+	UpdatedAt         time.Time
+	InboundPlanID     string
+}
+
+func (q *Queries) UpdateInboundPlanPlacementOption(ctx context.Context, arg UpdateInboundPlanPlacementOptionParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanPlacementOption, arg.PlacementOptionID, arg.UpdatedAt, arg.InboundPlanID)
+	return err
+}
+
+const updateInboundPlanShipmentConfirmationID = `-- name: UpdateInboundPlanShipmentConfirmationID :exec
+update inbound_plan_shipment
+set confirmation_id = ?
+where inbound_plan_id = ?
+  and shipment_id = ?
+`
+
+type UpdateInboundPlanShipmentConfirmationIDParams struct {
+	ConfirmationID sql.NullString
+	InboundPlanID  string
+	ShipmentID     string
+}
+
+func (q *Queries) UpdateInboundPlanShipmentConfirmationID(ctx context.Context, arg UpdateInboundPlanShipmentConfirmationIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanShipmentConfirmationID, arg.ConfirmationID, arg.InboundPlanID, arg.ShipmentID)
+	return err
+}
+
+const updateInboundPlanShipmentDeliveryWindowOption = `-- name: UpdateInboundPlanShipmentDeliveryWindowOption :exec
+update inbound_plan_shipment
+set delivery_window_option_id = ?
+where inbound_plan_id = ?
+  and shipment_id = ?
+`
+
+type UpdateInboundPlanShipmentDeliveryWindowOptionParams struct {
+	DeliveryWindowOptionID sql.NullString
+	InboundPlanID          string
+	ShipmentID             string
+}
+
+func (q *Queries) UpdateInboundPlanShipmentDeliveryWindowOption(ctx context.Context, arg UpdateInboundPlanShipmentDeliveryWindowOptionParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanShipmentDeliveryWindowOption, arg.DeliveryWindowOptionID, arg.InboundPlanID, arg.ShipmentID)
+	return err
+}
+
+const updateInboundPlanShipmentDetails = `-- name: UpdateInboundPlanShipmentDetails :exec
+update inbound_plan_shipment
+set name = ?,
+  status = ?,
+  destination_warehouse = ?,
+  amazon_reference_id = ?,
+  source_address = ?
+where inbound_plan_id = ?
+  and shipment_id = ?
+`
+
+type UpdateInboundPlanShipmentDetailsParams struct {
+	Name                 sql.NullString
+	Status               sql.NullString
+	DestinationWarehouse sql.NullString
+	AmazonReferenceID    sql.NullString
+	SourceAddress        sql.NullString
+	InboundPlanID        string
+	ShipmentID           string
+}
+
+func (q *Queries) UpdateInboundPlanShipmentDetails(ctx context.Context, arg UpdateInboundPlanShipmentDetailsParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanShipmentDetails,
+		arg.Name,
+		arg.Status,
+		arg.DestinationWarehouse,
+		arg.AmazonReferenceID,
+		arg.SourceAddress,
+		arg.InboundPlanID,
+		arg.ShipmentID,
+	)
+	return err
+}
+
+const updateInboundPlanShipmentTransportationOption = `-- name: UpdateInboundPlanShipmentTransportationOption :exec
+update inbound_plan_shipment
+set transportation_option_id = ?
+where inbound_plan_id = ?
+  and shipment_id = ?
+`
+
+type UpdateInboundPlanShipmentTransportationOptionParams struct {
+	TransportationOptionID sql.NullString
+	InboundPlanID          string
+	ShipmentID             string
+}
+
+func (q *Queries) UpdateInboundPlanShipmentTransportationOption(ctx context.Context, arg UpdateInboundPlanShipmentTransportationOptionParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanShipmentTransportationOption, arg.TransportationOptionID, arg.InboundPlanID, arg.ShipmentID)
+	return err
+}
+
+const updateInboundPlanStep = `-- name: UpdateInboundPlanStep :exec
+update inbound_plan
+set step = ?,
+  updated_at = ?
+where inbound_plan_id = ?
+`
+
+type UpdateInboundPlanStepParams struct {
+	Step          string
+	UpdatedAt     time.Time
+	InboundPlanID string
+}
+
+func (q *Queries) UpdateInboundPlanStep(ctx context.Context, arg UpdateInboundPlanStepParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboundPlanStep, arg.Step, arg.UpdatedAt, arg.InboundPlanID)
+	return err
+}
+
+const upsertCatalogItemDimension = `-- name: UpsertCatalogItemDimension :exec
+insert into catalog_item_dimensions (
+    marketplace_id,
+    asin,
+    length,
+    width,
+    height,
+    weight,
+    source,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?) on conflict (marketplace_id, asin) do
+update
+set length = excluded.length,
+  width = excluded.width,
+  height = excluded.height,
+  weight = excluded.weight,
+  source = excluded.source,
+  updated_at = excluded.updated_at
+`
+
+type UpsertCatalogItemDimensionParams struct {
+	MarketplaceID string
+	Asin          string
+	Length        sql.NullFloat64
+	Width         sql.NullFloat64
+	Height        sql.NullFloat64
+	Weight        sql.NullFloat64
+	Source        string
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpsertCatalogItemDimension(ctx context.Context, arg UpsertCatalogItemDimensionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCatalogItemDimension,
+		arg.MarketplaceID,
+		arg.Asin,
+		arg.Length,
+		arg.Width,
+		arg.Height,
+		arg.Weight,
+		arg.Source,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const upsertInboundPlanOperation = `-- name: UpsertInboundPlanOperation :exec
+insert into inbound_plan_operation (
+    operation_id,
+    inbound_plan_id,
+    operation,
+    status,
+    created_at,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?) on conflict (operation_id) do
+update
+set status = excluded.status,
+  updated_at = excluded.updated_at
+`
+
+type UpsertInboundPlanOperationParams struct {
+	OperationID   string
+	InboundPlanID string
+	Operation     string
+	Status        string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpsertInboundPlanOperation(ctx context.Context, arg UpsertInboundPlanOperationParams) error {
+	_, err := q.db.ExecContext(ctx, upsertInboundPlanOperation,
+		arg.OperationID,
+		arg.InboundPlanID,
+		arg.Operation,
+		arg.Status,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const upsertInboundPlanPlacement = `-- name: UpsertInboundPlanPlacement :exec
+insert into inbound_plan_placement (
+    inbound_plan_id,
+    placement_option_id,
+    shipment_count,
+    warehouses,
+    fees,
+    discounts,
+    currency,
+    policy,
+    confirmed_at
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?) on conflict (inbound_plan_id, placement_option_id) do
+update
+set shipment_count = excluded.shipment_count,
+  warehouses = excluded.warehouses,
+  fees = excluded.fees,
+  discounts = excluded.discounts,
+  currency = excluded.currency,
+  policy = excluded.policy,
+  confirmed_at = excluded.confirmed_at
+`
+
+type UpsertInboundPlanPlacementParams struct {
+	InboundPlanID     string
+	PlacementOptionID string
+	ShipmentCount     int64
+	Warehouses        sql.NullString
+	Fees              float64
+	Discounts         float64
+	Currency          sql.NullString
+	Policy            sql.NullString
+	ConfirmedAt       time.Time
+}
+
+func (q *Queries) UpsertInboundPlanPlacement(ctx context.Context, arg UpsertInboundPlanPlacementParams) error {
+	_, err := q.db.ExecContext(ctx, upsertInboundPlanPlacement,
+		arg.InboundPlanID,
+		arg.PlacementOptionID,
+		arg.ShipmentCount,
+		arg.Warehouses,
+		arg.Fees,
+		arg.Discounts,
+		arg.Currency,
+		arg.Policy,
+		arg.ConfirmedAt,
+	)
+	return err
+}
+
+const upsertItemRequirement = `-- name: UpsertItemRequirement :exec
+insert into item_requirements (
+    merchant,
+    marketplace_id,
+    msku,
+    prep_owner,
+    label_owner,
+    source,
+    updated_at
+  )
+values (?, ?, ?, ?, ?, ?, ?) on conflict (merchant, marketplace_id, msku) do
+update
+set prep_owner = excluded.prep_owner,
+  label_owner = excluded.label_owner,
+  source = excluded.source,
+  updated_at = excluded.updated_at
+`
+
+type UpsertItemRequirementParams struct {
+	Merchant      string
+	MarketplaceID string
+	Msku          string
+	PrepOwner     string
+	LabelOwner    string
+	Source        string
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpsertItemRequirement(ctx context.Context, arg UpsertItemRequirementParams) error {
+	_, err := q.db.ExecContext(ctx, upsertItemRequirement,
+		arg.Merchant,
+		arg.MarketplaceID,
+		arg.Msku,
+		arg.PrepOwner,
+		arg.LabelOwner,
+		arg.Source,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const upsertProductTypeDefinition = `-- name: UpsertProductTypeDefinition :exec
+insert into product_type_definition (
+    product_type,
+    marketplace_id,
+    requirements,
+    locale,
+    version,
+    latest,
+    definition,
+    schema_checksum,
+    fetched_at
+  )
+values (?, ?, ?, ?, ?, ?, ?, ?, ?) on conflict (
+    product_type,
+    marketplace_id,
+    requirements,
+    locale,
+    version
+  ) do
+update
+set latest = excluded.latest,
+  definition = excluded.definition,
+  schema_checksum = excluded.schema_checksum,
+  fetched_at = excluded.fetched_at
+`
+
+type UpsertProductTypeDefinitionParams struct {
+	ProductType    string
+	MarketplaceID  string
+	Requirements   string
+	Locale         string
+	Version        string
+	Latest         bool
+	Definition     string
+	SchemaChecksum string
+	FetchedAt      time.Time
+}
+
+func (q *Queries) UpsertProductTypeDefinition(ctx context.Context, arg UpsertProductTypeDefinitionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProductTypeDefinition,
+		arg.ProductType,
+		arg.MarketplaceID,
+		arg.Requirements,
+		arg.Locale,
+		arg.Version,
+		arg.Latest,
+		arg.Definition,
+		arg.SchemaChecksum,
+		arg.FetchedAt,
+	)
+	return err
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSON    Format = "json"
+	FormatNDJSON  Format = "ndjson"
+	FormatXLSX    Format = "xlsx"
+	FormatParquet Format = "parquet"
+)
+
+// Formats lists every supported format, in the order they are displayed in help texts.
+var Formats = []Format{FormatCSV, FormatJSON, FormatNDJSON, FormatXLSX, FormatParquet}
+
+type ColumnType int
+
+const (
+	String ColumnType = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// Column describes a single column of a table.
+type Column struct {
+	// Name is the header of the column in csv and xlsx outputs.
+	Name string
+	// Key is the field name in json, ndjson and parquet outputs, must be unique.
+	Key  string
+	Type ColumnType
+}
+
+// Table is a typed tabular output. Values in a row must match the column types,
+// int/int64, float64, bool, time.Time and string, or nil for missing values.
+type Table struct {
+	// Name is used as the sheet name in xlsx and the schema name in parquet outputs.
+	Name    string
+	Columns []Column
+	Rows    [][]any
+}
+
+// ParseFormat parses a format name, case insensitively.
+func ParseFormat(value string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(value)))
+	for _, f := range Formats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %s, must be one of %s", value, FormatList())
+}
+
+// Title returns the display name of the format, such as CSV or Parquet.
+func (f Format) Title() string {
+	if f == FormatParquet {
+		return "Parquet"
+	}
+	return strings.ToUpper(string(f))
+}
+
+// FormatFromPath guesses the format from the file extension, returns false if extension is not a known format.
+func FormatFromPath(path string) (Format, bool) {
+	format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return "", false
+	}
+	return format, true
+}
+
+// FormatList returns supported formats as `csv|json|...` for help texts.
+func FormatList() string {
+	names := make([]string, 0, len(Formats))
+	for _, f := range Formats {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, "|")
+}
+
+// DefaultPath returns `<prefix>_<timestamp>.<format>`.
+func DefaultPath(prefix string, format Format) string {
+	return fmt.Sprintf("%s_%s.%s", prefix, time.Now().Format("2006-01-02_15-04-05"), format)
+}
+
+// Write writes table to path in given format.
+func Write(path string, format Format, table Table) error {
+	for i, row := range table.Rows {
+		if len(row) != len(table.Columns) {
+			return fmt.Errorf("row %d has %d values, expected %d", i, len(row), len(table.Columns))
+		}
+	}
+	switch format {
+	case FormatCSV:
+		return writeCSV(path, table)
+	case FormatJSON:
+		return writeJSON(path, table)
+	case FormatNDJSON:
+		return writeNDJSON(path, table)
+	case FormatXLSX:
+		return writeXLSX(path, table)
+	case FormatParquet:
+		return writeParquet(path, table)
+	default:
+		return fmt.Errorf("unknown export format %s", format)
+	}
+}
+
+// normalize converts supported Go values to the canonical type of the column.
+func normalize(value any, typ ColumnType) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch typ {
+	case Int:
+		switch v := value.(type) {
+		case int:
+			return int64(v), nil
+		case int32:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		}
+	case Float:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case float32:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		}
+	case Bool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case Time:
+		if v, ok := value.(time.Time); ok {
+			return v, nil
+		}
+	case String:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		return fmt.Sprintf("%v", value), nil
+	}
+	return nil, fmt.Errorf("value %v of type %T does not match column type", value, value)
+}
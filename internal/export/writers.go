@@ -0,0 +1,315 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+func formatText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case string:
+		return v
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func writeCSV(path string, table Table) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	header := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		header = append(header, column.Name)
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for i, row := range table.Rows {
+		record := make([]string, 0, len(row))
+		for j, value := range row {
+			normalized, err := normalize(value, table.Columns[j].Type)
+			if err != nil {
+				return fmt.Errorf("row %d, column %s: %w", i, table.Columns[j].Name, err)
+			}
+			record = append(record, formatText(normalized))
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", i, err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// marshalRow encodes a row as a JSON object with keys in column order.
+func marshalRow(table Table, row []any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for j, value := range row {
+		normalized, err := normalize(value, table.Columns[j].Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", table.Columns[j].Name, err)
+		}
+		if f, ok := normalized.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			normalized = nil
+		}
+		key, err := json.Marshal(table.Columns[j].Key)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", table.Columns[j].Name, err)
+		}
+		if j > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func writeJSON(path string, table Table) error {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, row := range table.Rows {
+		encoded, err := marshalRow(table, row)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		buf.Write(encoded)
+	}
+	if len(table.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func writeNDJSON(path string, table Table) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+	writer := bufio.NewWriter(file)
+	for i, row := range table.Rows {
+		encoded, err := marshalRow(table, row)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		writer.Write(encoded)
+		writer.WriteByte('\n')
+	}
+	return writer.Flush()
+}
+
+// writeXLSX writes a single sheet with typed cells, bold frozen header row and autofilter.
+func writeXLSX(path string, table Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := table.Name
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	if len(sheet) > 31 {
+		sheet = sheet[:31]
+	}
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		return fmt.Errorf("failed to name sheet: %w", err)
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#E5E7EB"}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+	timeStyle, err := f.NewStyle(&excelize.Style{NumFmt: 22})
+	if err != nil {
+		return fmt.Errorf("failed to create time style: %w", err)
+	}
+
+	widths := make([]float64, len(table.Columns))
+	for j, column := range table.Columns {
+		cell, err := excelize.CoordinatesToCellName(j+1, 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(sheet, cell, column.Name); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		widths[j] = float64(len(column.Name)) + 4
+	}
+	for i, row := range table.Rows {
+		for j, value := range row {
+			normalized, err := normalize(value, table.Columns[j].Type)
+			if err != nil {
+				return fmt.Errorf("row %d, column %s: %w", i, table.Columns[j].Name, err)
+			}
+			if normalized == nil {
+				continue
+			}
+			if v, ok := normalized.(float64); ok && (math.IsInf(v, 0) || math.IsNaN(v)) {
+				continue
+			}
+			cell, err := excelize.CoordinatesToCellName(j+1, i+2)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellValue(sheet, cell, normalized); err != nil {
+				return fmt.Errorf("failed to write cell %s: %w", cell, err)
+			}
+			if table.Columns[j].Type == Time {
+				if err := f.SetCellStyle(sheet, cell, cell, timeStyle); err != nil {
+					return fmt.Errorf("failed to style cell %s: %w", cell, err)
+				}
+			}
+			widths[j] = max(widths[j], min(float64(len(formatText(normalized)))+2, 60))
+		}
+	}
+
+	lastColumn, err := excelize.ColumnNumberToName(max(len(table.Columns), 1))
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", lastColumn+"1", headerStyle); err != nil {
+		return fmt.Errorf("failed to style header: %w", err)
+	}
+	for j, width := range widths {
+		name, err := excelize.ColumnNumberToName(j + 1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetColWidth(sheet, name, name, width); err != nil {
+			return fmt.Errorf("failed to set column width: %w", err)
+		}
+	}
+	if err := f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return fmt.Errorf("failed to freeze header: %w", err)
+	}
+	if err := f.AutoFilter(sheet, fmt.Sprintf("A1:%s%d", lastColumn, len(table.Rows)+1), nil); err != nil {
+		return fmt.Errorf("failed to set autofilter: %w", err)
+	}
+	if err := f.SaveAs(path); err != nil {
+		return fmt.Errorf("failed to save file: %w", err)
+	}
+	return nil
+}
+
+func parquetNode(typ ColumnType) parquet.Node {
+	switch typ {
+	case Int:
+		return parquet.Int(64)
+	case Float:
+		return parquet.Leaf(parquet.DoubleType)
+	case Bool:
+		return parquet.Leaf(parquet.BooleanType)
+	case Time:
+		return parquet.Timestamp(parquet.Millisecond)
+	default:
+		return parquet.String()
+	}
+}
+
+// writeParquet writes a snappy compressed file, every column is optional so that missing values are nulls.
+func writeParquet(path string, table Table) error {
+	group := make(parquet.Group, len(table.Columns))
+	for _, column := range table.Columns {
+		// columns are fields of a group keyed by name, a duplicate would overwrite the earlier column
+		if _, exists := group[column.Key]; exists {
+			return fmt.Errorf("duplicate column key %s", column.Key)
+		}
+		group[column.Key] = parquet.Optional(parquetNode(column.Type))
+	}
+	name := table.Name
+	if name == "" {
+		name = "table"
+	}
+	schema := parquet.NewSchema(name, group)
+	// group fields are sorted by name, map columns to their leaf index
+	columnIndex := make(map[string]int, len(table.Columns))
+	for i, field := range schema.Fields() {
+		columnIndex[field.Name()] = i
+	}
+
+	rows := make([]parquet.Row, 0, len(table.Rows))
+	for i, values := range table.Rows {
+		row := make(parquet.Row, len(table.Columns))
+		for j, value := range values {
+			column := table.Columns[j]
+			index := columnIndex[column.Key]
+			normalized, err := normalize(value, column.Type)
+			if err != nil {
+				return fmt.Errorf("row %d, column %s: %w", i, column.Name, err)
+			}
+			if f, ok := normalized.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+				normalized = nil
+			}
+			switch v := normalized.(type) {
+			case nil:
+				row[index] = parquet.NullValue().Level(0, 0, index)
+			case time.Time:
+				row[index] = parquet.Int64Value(v.UnixMilli()).Level(0, 1, index)
+			default:
+				row[index] = parquet.ValueOf(v).Level(0, 1, index)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+	writer := parquet.NewWriter(file, schema, parquet.Compression(&parquet.Snappy))
+	if _, err := writer.WriteRows(rows); err != nil {
+		return fmt.Errorf("failed to write rows: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close parquet writer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	firstUpdate  = time.Date(2026, 9, 1, 10, 30, 0, 0, time.UTC)
+	secondUpdate = time.Date(2026, 9, 2, 8, 0, 0, 0, time.UTC)
+)
+
+// inventoryTable has keys that are not in alphabetical order and names that differ from the keys,
+// every type, missing values, an infinite float and a string that needs quoting.
+func inventoryTable() Table {
+	return Table{
+		Name: "inventory",
+		Columns: []Column{
+			{Name: "SKU", Key: "sku", Type: String},
+			{Name: "Quantity", Key: "quantity", Type: Int},
+			{Name: "Price", Key: "price", Type: Float},
+			{Name: "Active", Key: "active", Type: Bool},
+			{Name: "Updated At", Key: "updated_at", Type: Time},
+			{Name: "ASIN", Key: "asin", Type: String},
+		},
+		Rows: [][]any{
+			{"A-1", 5, 9.5, true, firstUpdate, "B01"},
+			{"B-2", int64(0), math.Inf(1), false, nil, nil},
+			{`C,3 "q"`, nil, 1, nil, secondUpdate, 42},
+		},
+	}
+}
+
+func writeTable(t *testing.T, format Format, table Table) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "inventory."+string(format))
+	if err := Write(path, format, table); err != nil {
+		t.Fatalf("Write(%s) error = %v", format, err)
+	}
+	return path
+}
+
+func TestWriteCSV(t *testing.T) {
+	file, err := os.Open(writeTable(t, FormatCSV, inventoryTable()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"SKU", "Quantity", "Price", "Active", "Updated At", "ASIN"},
+		{"A-1", "5", "9.5", "true", "2026-09-01T10:30:00Z", "B01"},
+		{"B-2", "0", "+Inf", "false", "", ""},
+		{`C,3 "q"`, "", "1", "", "2026-09-02T08:00:00Z", "42"},
+	}
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Errorf("csv records = %q, want %q", records, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	rows := []string{
+		`{"sku":"A-1","quantity":5,"price":9.5,"active":true,"updated_at":"2026-09-01T10:30:00Z","asin":"B01"}`,
+		`{"sku":"B-2","quantity":0,"price":null,"active":false,"updated_at":null,"asin":null}`,
+		`{"sku":"C,3 \"q\"","quantity":null,"price":1,"active":null,"updated_at":"2026-09-02T08:00:00Z","asin":"42"}`,
+	}
+	tests := []struct {
+		format Format
+		table  Table
+		want   string
+	}{
+		{format: FormatJSON, table: inventoryTable(), want: "[\n  " + strings.Join(rows, ",\n  ") + "\n]\n"},
+		{format: FormatJSON, table: Table{Columns: inventoryTable().Columns}, want: "[]\n"},
+		{format: FormatNDJSON, table: inventoryTable(), want: strings.Join(rows, "\n") + "\n"},
+		{format: FormatNDJSON, table: Table{Columns: inventoryTable().Columns}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			content, err := os.ReadFile(writeTable(t, tt.format, tt.table))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.want {
+				t.Errorf("%s output =\n%s\nwant\n%s", tt.format, content, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	f, err := excelize.OpenFile(writeTable(t, FormatXLSX, inventoryTable()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if sheets := f.GetSheetList(); !slices.Equal(sheets, []string{"inventory"}) {
+		t.Fatalf("sheets = %v, want [inventory]", sheets)
+	}
+	rows, err := f.GetRows("inventory", excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"SKU", "Quantity", "Price", "Active", "Updated At", "ASIN"},
+		{"A-1", "5", "9.5", "1", "46266.4375", "B01"},
+		{"B-2", "0", "", "0"},
+		{`C,3 "q"`, "", "1", "", "46267.333333333336", "42"},
+	}
+	if !slices.EqualFunc(rows, want, slices.Equal) {
+		t.Errorf("xlsx rows = %q, want %q", rows, want)
+	}
+
+	cellTypes := map[string]excelize.CellType{
+		"A2": excelize.CellTypeSharedString,
+		"B2": excelize.CellTypeUnset,
+		"C2": excelize.CellTypeUnset,
+		"D2": excelize.CellTypeBool,
+		"E2": excelize.CellTypeUnset,
+		"F3": excelize.CellTypeUnset,
+		"F4": excelize.CellTypeSharedString,
+	}
+	for cell, want := range cellTypes {
+		got, err := f.GetCellType("inventory", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("type of %s = %v, want %v", cell, got, want)
+		}
+	}
+	for _, cell := range []string{"E2", "E4"} {
+		styleID, err := f.GetCellStyle("inventory", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		style, err := f.GetStyle(styleID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if style.NumFmt != 22 {
+			t.Errorf("number format of %s = %d, want 22", cell, style.NumFmt)
+		}
+	}
+
+	idx := slices.IndexFunc(f.GetDefinedName(), func(name excelize.DefinedName) bool { return name.Name == "_xlnm._FilterDatabase" })
+	if idx == -1 {
+		t.Fatal("autofilter is not set")
+	}
+	if ref := f.GetDefinedName()[idx].RefersTo; ref != "'inventory'!$A$1:$F$4" {
+		t.Errorf("autofilter range = %s, want 'inventory'!$A$1:$F$4", ref)
+	}
+}
+
+func TestWriteParquet(t *testing.T) {
+	path := writeTable(t, FormatParquet, inventoryTable())
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pf, err := parquet.OpenFile(file, stat.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := pf.Schema()
+	if schema.Name() != "inventory" {
+		t.Errorf("schema name = %s, want inventory", schema.Name())
+	}
+	// leaf columns are sorted by key, values are mapped back to their key through the column index
+	keys := make([]string, 0, len(schema.Fields()))
+	for _, field := range schema.Fields() {
+		keys = append(keys, field.Name())
+		if !field.Optional() {
+			t.Errorf("column %s is not optional", field.Name())
+		}
+	}
+	if want := []string{"active", "asin", "price", "quantity", "sku", "updated_at"}; !slices.Equal(keys, want) {
+		t.Fatalf("parquet columns = %v, want %v", keys, want)
+	}
+	if ts := schema.Fields()[5].Type().LogicalType(); ts == nil || ts.Timestamp == nil {
+		t.Errorf("updated_at logical type = %v, want timestamp", ts)
+	}
+
+	reader := parquet.NewReader(pf)
+	defer reader.Close()
+	rows := make([]parquet.Row, len(inventoryTable().Rows)+1)
+	n, err := reader.ReadRows(rows)
+	if err != nil && !errors.Is(err, io.EOF) {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	for _, row := range rows[:n] {
+		values := make(map[string]any, len(keys))
+		for _, value := range row {
+			key := keys[value.Column()]
+			switch {
+			case value.IsNull():
+				values[key] = nil
+			case key == "sku" || key == "asin":
+				values[key] = value.String()
+			case key == "price":
+				values[key] = value.Double()
+			case key == "active":
+				values[key] = value.Boolean()
+			default:
+				values[key] = value.Int64()
+			}
+		}
+		got = append(got, values)
+	}
+	want := []map[string]any{
+		{"sku": "A-1", "quantity": int64(5), "price": 9.5, "active": true, "updated_at": firstUpdate.UnixMilli(), "asin": "B01"},
+		{"sku": "B-2", "quantity": int64(0), "price": nil, "active": false, "updated_at": nil, "asin": nil},
+		{"sku": `C,3 "q"`, "quantity": nil, "price": 1.0, "active": nil, "updated_at": secondUpdate.UnixMilli(), "asin": "42"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("read %d rows, want %d", len(got), len(want))
+	}
+	for i := range want {
+		for key, value := range want[i] {
+			if got[i][key] != value {
+				t.Errorf("row %d %s = %v, want %v", i, key, got[i][key], value)
+			}
+		}
+	}
+}
+
+func TestWriteRejectsInvalidTables(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		table  Table
+		want   string
+	}{
+		{
+			name:   "short row",
+			format: FormatCSV,
+			table:  Table{Columns: []Column{{Name: "SKU", Key: "sku"}}, Rows: [][]any{{"A", "B"}}},
+			want:   "row 0 has 2 values, expected 1",
+		},
+		{
+			name:   "mismatched type",
+			format: FormatJSON,
+			table:  Table{Columns: []Column{{Name: "Quantity", Key: "quantity", Type: Int}}, Rows: [][]any{{"5"}}},
+			want:   "column Quantity: value 5 of type string does not match column type",
+		},
+		{
+			name:   "duplicate parquet key",
+			format: FormatParquet,
+			table:  Table{Columns: []Column{{Name: "SKU", Key: "sku"}, {Name: "Seller SKU", Key: "sku"}}},
+			want:   "duplicate column key sku",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Write(filepath.Join(t.TempDir(), "table."+string(tt.format)), tt.format, tt.table)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Write() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
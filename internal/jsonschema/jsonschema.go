@@ -0,0 +1,591 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Schema is a compiled product type schema. Amazon publishes product type schemas in JSON Schema draft 2019-09 with
+// the custom vocabulary of its product type definition meta-schema, both are validated.
+type Schema struct {
+	root     map[string]any
+	patterns map[string]*regexp.Regexp
+}
+
+// Error is a single validation failure of an instance.
+type Error struct {
+	// Path is the JSON path of the failing value, such as $.item_name[0].value.
+	Path    string
+	Keyword string
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Compile parses a schema document.
+func Compile(data []byte) (*Schema, error) {
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return &Schema{root: root, patterns: make(map[string]*regexp.Regexp)}, nil
+}
+
+// Properties returns the names of the properties defined at the root of the schema.
+func (s *Schema) Properties() map[string]any {
+	properties, _ := s.root["properties"].(map[string]any)
+	return properties
+}
+
+// Root returns the decoded schema document, for walking the schema instead of validating with it.
+func (s *Schema) Root() map[string]any {
+	return s.root
+}
+
+// Resolve returns the node a $ref points to within the schema document.
+func (s *Schema) Resolve(ref string) (any, bool) {
+	return s.resolve(ref)
+}
+
+// Validate validates an instance decoded with encoding/json against the whole schema.
+func (s *Schema) Validate(instance any) []Error {
+	return s.validate(s.root, instance, path{})
+}
+
+// ValidateProperty validates the value of a single root property, without the root level constraints such as required
+// properties. Unknown properties are reported when the schema does not allow additional properties.
+func (s *Schema) ValidateProperty(name string, value any) []Error {
+	at := path{name}
+	property, ok := s.Properties()[name]
+	if !ok {
+		if allowed, isBool := s.root["additionalProperties"].(bool); isBool && !allowed {
+			return []Error{{Path: at.String(), Keyword: "additionalProperties", Message: "property is not defined in the schema"}}
+		}
+		return nil
+	}
+	return s.validate(property, value, at)
+}
+
+// path is a location in an instance, made of object keys and array indexes.
+type path []any
+
+func (p path) with(token any) path {
+	next := make(path, len(p), len(p)+1)
+	copy(next, p)
+	return append(next, token)
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (p path) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, token := range p {
+		switch t := token.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", t)
+		case string:
+			if identifier.MatchString(t) {
+				b.WriteString("." + t)
+			} else {
+				fmt.Fprintf(&b, "[%s]", strconv.Quote(t))
+			}
+		}
+	}
+	return b.String()
+}
+
+func (s *Schema) validate(node any, instance any, at path) []Error {
+	switch schema := node.(type) {
+	case bool:
+		if !schema {
+			return []Error{{Path: at.String(), Keyword: "false", Message: "no value is allowed"}}
+		}
+		return nil
+	case map[string]any:
+		return s.validateObject(schema, instance, at)
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(schema map[string]any, instance any, at path) []Error {
+	var errs []Error
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, Error{Path: at.String(), Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		if target, found := s.resolve(ref); found {
+			errs = append(errs, s.validate(target, instance, at)...)
+		}
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(types, instance) {
+		fail("type", "expected %s, got %s", describeTypes(types), typeOf(instance))
+		return errs
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		if !containsValue(values, instance) {
+			fail("enum", "must be one of %s", formatValues(values))
+		}
+	}
+	if value, ok := schema["const"]; ok && !equal(value, instance) {
+		fail("const", "must be %s", formatValue(value))
+	}
+
+	switch v := instance.(type) {
+	case string:
+		errs = append(errs, s.validateString(schema, v, at)...)
+	case float64:
+		errs = append(errs, validateNumber(schema, v, at)...)
+	case []any:
+		errs = append(errs, s.validateArray(schema, v, at)...)
+	case map[string]any:
+		errs = append(errs, s.validateProperties(schema, v, at)...)
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, sub := range all {
+			errs = append(errs, s.validate(sub, instance, at)...)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		var best []Error
+		matched := false
+		for i, sub := range anyOf {
+			subErrs := s.validate(sub, instance, at)
+			if len(subErrs) == 0 {
+				matched = true
+				break
+			}
+			if i == 0 || len(subErrs) < len(best) {
+				best = subErrs
+			}
+		}
+		if !matched {
+			errs = append(errs, best...)
+		}
+	}
+	if one, ok := schema["oneOf"].([]any); ok {
+		var best []Error
+		matches := 0
+		for i, sub := range one {
+			subErrs := s.validate(sub, instance, at)
+			if len(subErrs) == 0 {
+				matches++
+				continue
+			}
+			if i == 0 || best == nil || len(subErrs) < len(best) {
+				best = subErrs
+			}
+		}
+		switch {
+		case matches == 0:
+			errs = append(errs, best...)
+		case matches > 1:
+			fail("oneOf", "matches %d schemas, exactly one is allowed", matches)
+		}
+	}
+	if not, ok := schema["not"]; ok && len(s.validate(not, instance, at)) == 0 {
+		fail("not", "must not match the schema")
+	}
+	if cond, ok := schema["if"]; ok {
+		if len(s.validate(cond, instance, at)) == 0 {
+			if then, ok := schema["then"]; ok {
+				errs = append(errs, s.validate(then, instance, at)...)
+			}
+		} else if els, ok := schema["else"]; ok {
+			errs = append(errs, s.validate(els, instance, at)...)
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateString(schema map[string]any, value string, at path) []Error {
+	var errs []Error
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, Error{Path: at.String(), Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	length := len([]rune(value))
+	if limit, ok := number(schema["minLength"]); ok && float64(length) < limit {
+		fail("minLength", "must be at least %g characters, got %d", limit, length)
+	}
+	if limit, ok := number(schema["maxLength"]); ok && float64(length) > limit {
+		fail("maxLength", "must be at most %g characters, got %d", limit, length)
+	}
+	if limit, ok := number(schema["minUtf8ByteLength"]); ok && float64(len(value)) < limit {
+		fail("minUtf8ByteLength", "must be at least %g bytes in UTF-8, got %d", limit, len(value))
+	}
+	if limit, ok := number(schema["maxUtf8ByteLength"]); ok && float64(len(value)) > limit {
+		fail("maxUtf8ByteLength", "must be at most %g bytes in UTF-8, got %d", limit, len(value))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re := s.pattern(pattern); re != nil && !re.MatchString(value) {
+			fail("pattern", "must match %s", pattern)
+		}
+	}
+	return errs
+}
+
+func validateNumber(schema map[string]any, value float64, at path) []Error {
+	var errs []Error
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, Error{Path: at.String(), Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	if limit, ok := number(schema["minimum"]); ok && value < limit {
+		fail("minimum", "must be at least %g, got %g", limit, value)
+	}
+	if limit, ok := number(schema["maximum"]); ok && value > limit {
+		fail("maximum", "must be at most %g, got %g", limit, value)
+	}
+	if limit, ok := number(schema["exclusiveMinimum"]); ok && value <= limit {
+		fail("exclusiveMinimum", "must be greater than %g, got %g", limit, value)
+	}
+	if limit, ok := number(schema["exclusiveMaximum"]); ok && value >= limit {
+		fail("exclusiveMaximum", "must be less than %g, got %g", limit, value)
+	}
+	if divisor, ok := number(schema["multipleOf"]); ok && divisor > 0 {
+		// decimal divisors such as 0.01 are not exact in binary, 19.99 / 0.01 is 1998.9999999999998
+		if quotient := value / divisor; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			fail("multipleOf", "must be a multiple of %g", divisor)
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateArray(schema map[string]any, values []any, at path) []Error {
+	var errs []Error
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, Error{Path: at.String(), Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	if limit, ok := number(schema["minItems"]); ok && float64(len(values)) < limit {
+		fail("minItems", "must have at least %g items, got %d", limit, len(values))
+	}
+	if limit, ok := number(schema["maxItems"]); ok && float64(len(values)) > limit {
+		fail("maxItems", "must have at most %g items, got %d", limit, len(values))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		seen := make(map[string]int, len(values))
+		for i, value := range values {
+			key := canonical(value)
+			if first, ok := seen[key]; ok {
+				fail("uniqueItems", "items %d and %d are equal", first, i)
+				continue
+			}
+			seen[key] = i
+		}
+	}
+	errs = append(errs, validateSelectors(schema, values, at)...)
+
+	switch items := schema["items"].(type) {
+	case []any:
+		for i, value := range values {
+			if i < len(items) {
+				errs = append(errs, s.validate(items[i], value, at.with(i))...)
+			} else if additional, ok := schema["additionalItems"]; ok {
+				errs = append(errs, s.validate(additional, value, at.with(i))...)
+			}
+		}
+	case map[string]any, bool:
+		for i, value := range values {
+			errs = append(errs, s.validate(items, value, at.with(i))...)
+		}
+	}
+
+	if contains, ok := schema["contains"]; ok {
+		count := 0
+		for _, value := range values {
+			if len(s.validate(contains, value, at)) == 0 {
+				count++
+			}
+		}
+		minContains := 1.0
+		if limit, ok := number(schema["minContains"]); ok {
+			minContains = limit
+		}
+		if float64(count) < minContains {
+			fail("contains", "must contain at least %g matching items, got %d", minContains, count)
+		}
+		if limit, ok := number(schema["maxContains"]); ok && float64(count) > limit {
+			fail("maxContains", "must contain at most %g matching items, got %d", limit, count)
+		}
+	}
+	return errs
+}
+
+// validateSelectors implements the selectors and maxUniqueItems keywords of Amazon's meta-schema. Items with the same values
+// for every selector property (such as marketplace_id and language_tag) are the same item, and at most maxUniqueItems of
+// them are allowed.
+func validateSelectors(schema map[string]any, values []any, at path) []Error {
+	selectors, ok := schema["selectors"].([]any)
+	if !ok || len(selectors) == 0 {
+		return nil
+	}
+	limit, ok := number(schema["maxUniqueItems"])
+	if !ok {
+		return nil
+	}
+	counts := make(map[string]int)
+	var order []string
+	for _, value := range values {
+		object, _ := value.(map[string]any)
+		parts := make([]string, 0, len(selectors))
+		for _, selector := range selectors {
+			name, _ := selector.(string)
+			parts = append(parts, fmt.Sprintf("%s=%s", name, formatValue(object[name])))
+		}
+		key := strings.Join(parts, ", ")
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+	var errs []Error
+	for _, key := range order {
+		if float64(counts[key]) > limit {
+			errs = append(errs, Error{Path: at.String(), Keyword: "maxUniqueItems", Message: fmt.Sprintf("%d items have %s, at most %g allowed", counts[key], key, limit)})
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateProperties(schema map[string]any, object map[string]any, at path) []Error {
+	var errs []Error
+	fail := func(keyword string, format string, args ...any) {
+		errs = append(errs, Error{Path: at.String(), Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if key, _ := name.(string); key != "" {
+				if _, ok := object[key]; !ok {
+					errs = append(errs, Error{Path: at.with(key).String(), Keyword: "required", Message: "is required"})
+				}
+			}
+		}
+	}
+	if dependent, ok := schema["dependentRequired"].(map[string]any); ok {
+		for name, required := range dependent {
+			if _, ok := object[name]; !ok {
+				continue
+			}
+			for _, dependency := range required.([]any) {
+				if key, _ := dependency.(string); key != "" {
+					if _, ok := object[key]; !ok {
+						errs = append(errs, Error{Path: at.with(key).String(), Keyword: "dependentRequired", Message: fmt.Sprintf("is required when %s is present", name)})
+					}
+				}
+			}
+		}
+	}
+	if limit, ok := number(schema["minProperties"]); ok && float64(len(object)) < limit {
+		fail("minProperties", "must have at least %g properties, got %d", limit, len(object))
+	}
+	if limit, ok := number(schema["maxProperties"]); ok && float64(len(object)) > limit {
+		fail("maxProperties", "must have at most %g properties, got %d", limit, len(object))
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	patternProperties, _ := schema["patternProperties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+	for _, name := range sortedKeys(object) {
+		value := object[name]
+		matched := false
+		if property, ok := properties[name]; ok {
+			matched = true
+			errs = append(errs, s.validate(property, value, at.with(name))...)
+		}
+		for pattern, property := range patternProperties {
+			if re := s.pattern(pattern); re != nil && re.MatchString(name) {
+				matched = true
+				errs = append(errs, s.validate(property, value, at.with(name))...)
+			}
+		}
+		if !matched && hasAdditional {
+			if allowed, isBool := additional.(bool); isBool && !allowed {
+				errs = append(errs, Error{Path: at.with(name).String(), Keyword: "additionalProperties", Message: "property is not defined in the schema"})
+			} else {
+				errs = append(errs, s.validate(additional, value, at.with(name))...)
+			}
+		}
+	}
+	return errs
+}
+
+// resolve resolves references within the schema document, references to other documents are not followed.
+func (s *Schema) resolve(ref string) (any, bool) {
+	index := strings.Index(ref, "#")
+	if index == -1 {
+		return nil, false
+	}
+	if base := ref[:index]; base != "" {
+		if id, _ := s.root["$id"].(string); base != id {
+			return nil, false
+		}
+	}
+	var node any = s.root
+	pointer := ref[index+1:]
+	if pointer == "" {
+		return node, true
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch n := node.(type) {
+		case map[string]any:
+			next, ok := n[token]
+			if !ok {
+				return nil, false
+			}
+			node = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// pattern compiles and caches a pattern, patterns that are not supported by RE2 (lookarounds, backreferences) are skipped.
+func (s *Schema) pattern(pattern string) *regexp.Regexp {
+	if re, ok := s.patterns[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	s.patterns[pattern] = re
+	return re
+}
+
+func matchesType(types any, instance any) bool {
+	switch t := types.(type) {
+	case string:
+		return matchesSingleType(t, instance)
+	case []any:
+		for _, name := range t {
+			if n, _ := name.(string); matchesSingleType(n, instance) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesSingleType(name string, instance any) bool {
+	switch name {
+	case "integer":
+		v, ok := instance.(float64)
+		return ok && v == math.Trunc(v)
+	case "number":
+		_, ok := instance.(float64)
+		return ok
+	default:
+		return typeOf(instance) == name
+	}
+}
+
+func typeOf(instance any) string {
+	switch instance.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", instance)
+}
+
+func describeTypes(types any) string {
+	if list, ok := types.([]any); ok {
+		names := make([]string, 0, len(list))
+		for _, name := range list {
+			names = append(names, fmt.Sprint(name))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(types)
+}
+
+func number(value any) (float64, bool) {
+	v, ok := value.(float64)
+	return v, ok
+}
+
+func containsValue(values []any, instance any) bool {
+	for _, value := range values {
+		if equal(value, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b any) bool {
+	return canonical(a) == canonical(b)
+}
+
+// canonical encodes a value as JSON with sorted object keys, so equal values have equal encodings.
+func canonical(value any) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func formatValue(value any) string {
+	if value == nil {
+		return "null"
+	}
+	return canonical(value)
+}
+
+// formatValues formats enum values, long enums are shortened.
+func formatValues(values []any) string {
+	const limit = 10
+	parts := make([]string, 0, min(len(values), limit))
+	for i, value := range values {
+		if i == limit {
+			parts = append(parts, fmt.Sprintf("and %d more", len(values)-limit))
+			break
+		}
+		parts = append(parts, formatValue(value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
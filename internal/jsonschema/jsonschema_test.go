@@ -0,0 +1,423 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+)
+
+// problems formats errors as "<path> <keyword>", in the order they are reported.
+func problems(errs []Error) []string {
+	formatted := make([]string, 0, len(errs))
+	for _, err := range errs {
+		formatted = append(formatted, err.Path+" "+err.Keyword)
+	}
+	return formatted
+}
+
+func decode(t *testing.T, data string) any {
+	t.Helper()
+	var instance any
+	if err := json.Unmarshal([]byte(data), &instance); err != nil {
+		t.Fatalf("failed to decode instance %s: %v", data, err)
+	}
+	return instance
+}
+
+func compile(t *testing.T, data string) *Schema {
+	t.Helper()
+	schema, err := Compile([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+	return schema
+}
+
+func TestValidateKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []string
+	}{
+		// type
+		{"type string", `{"type": "string"}`, `"a"`, nil},
+		{"type mismatch", `{"type": "string"}`, `1`, []string{"$ type"}},
+		{"type integer", `{"type": "integer"}`, `3`, nil},
+		{"type integer with fraction", `{"type": "integer"}`, `3.5`, []string{"$ type"}},
+		{"type large integer", `{"type": "integer"}`, `1e20`, nil},
+		{"type integer is a number", `{"type": "number"}`, `3`, nil},
+		{"type list", `{"type": ["string", "null"]}`, `null`, nil},
+		{"type list mismatch", `{"type": ["string", "null"]}`, `true`, []string{"$ type"}},
+		{"type mismatch skips other keywords", `{"type": "string", "enum": ["a"]}`, `1`, []string{"$ type"}},
+
+		// enum and const
+		{"enum", `{"enum": ["a", 1, {"b": [true]}]}`, `{"b": [true]}`, nil},
+		{"enum mismatch", `{"enum": ["a", 1]}`, `"1"`, []string{"$ enum"}},
+		{"const", `{"const": {"a": 1, "b": 2}}`, `{"b": 2, "a": 1}`, nil},
+		{"const mismatch", `{"const": true}`, `false`, []string{"$ const"}},
+
+		// strings
+		{"minLength", `{"minLength": 2}`, `"a"`, []string{"$ minLength"}},
+		{"maxLength counts characters", `{"maxLength": 3}`, `"çğü"`, nil},
+		{"maxLength", `{"maxLength": 3}`, `"abcd"`, []string{"$ maxLength"}},
+		{"maxUtf8ByteLength counts bytes", `{"maxUtf8ByteLength": 4}`, `"çğü"`, []string{"$ maxUtf8ByteLength"}},
+		{"maxUtf8ByteLength", `{"maxUtf8ByteLength": 6}`, `"çğü"`, nil},
+		{"minUtf8ByteLength", `{"minUtf8ByteLength": 4}`, `"ç"`, []string{"$ minUtf8ByteLength"}},
+		{"pattern", `{"pattern": "^[0-9]{12}$"}`, `"012345678905"`, nil},
+		{"pattern mismatch", `{"pattern": "^[0-9]{12}$"}`, `"01234567890"`, []string{"$ pattern"}},
+		{"pattern is not anchored", `{"pattern": "[0-9]"}`, `"abc1"`, nil},
+		{"unsupported pattern is skipped", `{"pattern": "^(?!x).*$"}`, `"x"`, nil},
+		{"string keywords ignore other types", `{"maxLength": 1, "pattern": "^a$"}`, `12`, nil},
+
+		// numbers
+		{"minimum", `{"minimum": 1}`, `1`, nil},
+		{"minimum mismatch", `{"minimum": 1}`, `0.5`, []string{"$ minimum"}},
+		{"maximum mismatch", `{"maximum": 1}`, `2`, []string{"$ maximum"}},
+		{"exclusiveMinimum", `{"exclusiveMinimum": 1}`, `1`, []string{"$ exclusiveMinimum"}},
+		{"exclusiveMaximum", `{"exclusiveMaximum": 1}`, `1`, []string{"$ exclusiveMaximum"}},
+		{"multipleOf", `{"multipleOf": 5}`, `15`, nil},
+		{"multipleOf mismatch", `{"multipleOf": 5}`, `16`, []string{"$ multipleOf"}},
+		{"multipleOf decimal", `{"multipleOf": 0.01}`, `19.99`, nil},
+		{"multipleOf decimal mismatch", `{"multipleOf": 0.01}`, `19.995`, []string{"$ multipleOf"}},
+
+		// arrays
+		{"minItems", `{"minItems": 1}`, `[]`, []string{"$ minItems"}},
+		{"maxItems", `{"maxItems": 1}`, `[1, 2]`, []string{"$ maxItems"}},
+		{"uniqueItems", `{"uniqueItems": true}`, `[{"a": 1, "b": 2}, {"b": 2, "a": 1}]`, []string{"$ uniqueItems"}},
+		{"uniqueItems distinct", `{"uniqueItems": true}`, `[1, "1", [1]]`, nil},
+		{"items", `{"items": {"type": "string"}}`, `["a", 1, "b", 2]`, []string{"$[1] type", "$[3] type"}},
+		{"items tuple", `{"items": [{"type": "string"}, {"type": "number"}]}`, `["a", 1, true]`, nil},
+		{"items tuple additionalItems", `{"items": [{"type": "string"}], "additionalItems": false}`, `["a", 1]`, []string{"$[1] false"}},
+		{"contains", `{"contains": {"const": 1}}`, `[0, 1]`, nil},
+		{"contains mismatch", `{"contains": {"const": 1}}`, `[0, 2]`, []string{"$ contains"}},
+		{"minContains", `{"contains": {"const": 1}, "minContains": 2}`, `[1, 0]`, []string{"$ contains"}},
+		{"maxContains", `{"contains": {"const": 1}, "maxContains": 1}`, `[1, 1]`, []string{"$ maxContains"}},
+
+		// selectors and maxUniqueItems
+		{
+			"maxUniqueItems distinct selectors",
+			`{"selectors": ["marketplace_id", "language_tag"], "maxUniqueItems": 1}`,
+			`[{"marketplace_id": "A", "language_tag": "en_US"}, {"marketplace_id": "A", "language_tag": "es_US"}, {"marketplace_id": "B", "language_tag": "en_US"}]`,
+			nil,
+		},
+		{
+			"maxUniqueItems repeated selectors",
+			`{"selectors": ["marketplace_id", "language_tag"], "maxUniqueItems": 1}`,
+			`[{"marketplace_id": "A", "language_tag": "en_US", "value": "x"}, {"marketplace_id": "A", "language_tag": "en_US", "value": "y"}]`,
+			[]string{"$ maxUniqueItems"},
+		},
+		{
+			"maxUniqueItems missing selector values are equal",
+			`{"selectors": ["marketplace_id"], "maxUniqueItems": 1}`,
+			`[{"value": "x"}, {"value": "y"}]`,
+			[]string{"$ maxUniqueItems"},
+		},
+		{
+			"maxUniqueItems above one",
+			`{"selectors": ["marketplace_id"], "maxUniqueItems": 2}`,
+			`[{"marketplace_id": "A"}, {"marketplace_id": "A"}]`,
+			nil,
+		},
+		{"maxUniqueItems without selectors", `{"maxUniqueItems": 1}`, `[{"a": 1}, {"a": 1}]`, nil},
+
+		// objects
+		{"required", `{"required": ["a", "b"]}`, `{"a": 1}`, []string{"$.b required"}},
+		{"required ignores other types", `{"required": ["a"]}`, `[]`, nil},
+		{"dependentRequired", `{"dependentRequired": {"a": ["b"]}}`, `{"a": 1}`, []string{"$.b dependentRequired"}},
+		{"dependentRequired absent", `{"dependentRequired": {"a": ["b"]}}`, `{"c": 1}`, nil},
+		{"minProperties", `{"minProperties": 1}`, `{}`, []string{"$ minProperties"}},
+		{"maxProperties", `{"maxProperties": 1}`, `{"a": 1, "b": 2}`, []string{"$ maxProperties"}},
+		{"properties", `{"properties": {"a": {"type": "string"}, "b c": {"type": "string"}}}`, `{"a": 1, "b c": 2}`, []string{"$.a type", `$["b c"] type`}},
+		{"patternProperties", `{"patternProperties": {"^x_": {"type": "number"}}}`, `{"x_a": "1", "y": "1"}`, []string{"$.x_a type"}},
+		{"additionalProperties false", `{"properties": {"a": {}}, "patternProperties": {"^x_": {}}, "additionalProperties": false}`, `{"a": 1, "x_b": 1, "c": 1}`, []string{"$.c additionalProperties"}},
+		{"additionalProperties schema", `{"properties": {"a": {}}, "additionalProperties": {"type": "number"}}`, `{"a": "1", "b": "2"}`, []string{"$.b type"}},
+
+		// references
+		{"ref", `{"$defs": {"name": {"type": "string"}}, "$ref": "#/$defs/name"}`, `1`, []string{"$ type"}},
+		{"ref with siblings", `{"$defs": {"name": {"type": "string"}}, "$ref": "#/$defs/name", "maxLength": 1}`, `"ab"`, []string{"$ maxLength"}},
+		{"nested ref", `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"minimum": 2}}, "properties": {"x": {"$ref": "#/$defs/a"}}}`, `{"x": 1}`, []string{"$.x minimum"}},
+		{"ref with id", `{"$id": "https://example.com/s", "$defs": {"a": {"const": 1}}, "$ref": "https://example.com/s#/$defs/a"}`, `2`, []string{"$ const"}},
+		{"ref to other document is skipped", `{"$id": "https://example.com/s", "$ref": "https://example.com/other#/$defs/a"}`, `2`, nil},
+		{"ref with escaped tokens", `{"$defs": {"a/b": {"const": 1}, "c~d": {"const": 2}}, "allOf": [{"$ref": "#/$defs/a~1b"}, {"$ref": "#/$defs/c~0d"}]}`, `3`, []string{"$ const", "$ const"}},
+		{"ref to array index", `{"$defs": {"list": [{"const": 1}]}, "$ref": "#/$defs/list/0"}`, `2`, []string{"$ const"}},
+		{"missing ref is skipped", `{"$ref": "#/$defs/missing"}`, `1`, nil},
+
+		// applicators
+		{"allOf", `{"allOf": [{"minimum": 1}, {"maximum": 0}]}`, `2`, []string{"$ maximum"}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"type": "number"}]}`, `1`, nil},
+		{"anyOf mismatch reports closest branch", `{"anyOf": [{"type": "string", "minLength": 5, "pattern": "^a"}, {"type": "string", "maxLength": 1}]}`, `"bbb"`, []string{"$ maxLength"}},
+		{"oneOf", `{"oneOf": [{"type": "string"}, {"type": "number"}]}`, `1`, nil},
+		{"oneOf none reports closest branch", `{"oneOf": [{"minimum": 5, "multipleOf": 2}, {"const": 2}]}`, `1`, []string{"$ const"}},
+		{"oneOf many", `{"oneOf": [{"type": "number"}, {"minimum": 0}]}`, `1`, []string{"$ oneOf"}},
+		{"not", `{"not": {"type": "string"}}`, `"a"`, []string{"$ not"}},
+		{"not mismatch", `{"not": {"type": "string"}}`, `1`, nil},
+		{"if then", `{"if": {"properties": {"a": {"const": 1}}}, "then": {"required": ["b"]}, "else": {"required": ["c"]}}`, `{"a": 1}`, []string{"$.b required"}},
+		{"if else", `{"if": {"properties": {"a": {"const": 1}}}, "then": {"required": ["b"]}, "else": {"required": ["c"]}}`, `{"a": 2}`, []string{"$.c required"}},
+		{"if without then", `{"if": {"const": 1}, "else": {"const": 2}}`, `1`, nil},
+
+		// boolean schemas
+		{"true schema", `{"properties": {"a": true}}`, `{"a": 1}`, nil},
+		{"false schema", `{"properties": {"a": false}}`, `{"a": 1}`, []string{"$.a false"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := problems(compile(t, tt.schema).Validate(decode(t, tt.instance)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Validate(%s) = %q, want %q", tt.instance, got, tt.want)
+			}
+		})
+	}
+}
+
+func loadLuggageSchema(t *testing.T) *Schema {
+	t.Helper()
+	data, err := os.ReadFile("testdata/luggage.json")
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	schema, err := Compile(data)
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+	return schema
+}
+
+// luggageAttributes are the attributes of a valid LUGGAGE listing, test cases replace or add attributes to it.
+const luggageAttributes = `{
+	"item_name": [{"value": "Carry-On Spinner", "language_tag": "en_US", "marketplace_id": "ATVPDKIKX0DER"}],
+	"brand": [{"value": "Halycon", "language_tag": "en_US", "marketplace_id": "ATVPDKIKX0DER"}],
+	"condition_type": [{"value": "new_new", "marketplace_id": "ATVPDKIKX0DER"}],
+	"externally_assigned_product_identifier": [{"type": "upc", "value": "012345678905", "marketplace_id": "ATVPDKIKX0DER"}],
+	"list_price": [{"currency": "USD", "value": 129.99, "marketplace_id": "ATVPDKIKX0DER"}],
+	"number_of_wheels": [{"value": 4, "marketplace_id": "ATVPDKIKX0DER"}]
+}`
+
+func TestValidateProductTypeSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		// attributes are merged into luggageAttributes, null values remove the attribute.
+		attributes string
+		want       []string
+	}{
+		{"valid listing", `{}`, nil},
+		{"missing required attribute", `{"brand": null}`, []string{"$.brand required"}},
+		{"empty required attribute", `{"item_name": []}`, []string{"$.item_name minItems"}},
+		{
+			"item name too long",
+			`{"item_name": [{"value": "Carry-On Spinner Luggage", "language_tag": "en_US"}]}`,
+			[]string{"$.item_name[0].value maxLength"},
+		},
+		{
+			"item name too many bytes",
+			`{"item_name": [{"value": "Çanta Çanta Çanta Çanta", "language_tag": "en_US"}]}`,
+			[]string{"$.item_name[0].value maxLength", "$.item_name[0].value maxUtf8ByteLength"},
+		},
+		{
+			"item name without language tag",
+			`{"item_name": [{"value": "Spinner"}]}`,
+			[]string{"$.item_name[0].language_tag required"},
+		},
+		{
+			"item name with unknown property",
+			`{"item_name": [{"value": "Spinner", "language_tag": "en_US", "lang": "en"}]}`,
+			[]string{"$.item_name[0].lang additionalProperties"},
+		},
+		{
+			"item name in two languages",
+			`{"item_name": [{"value": "Spinner", "language_tag": "en_US"}, {"value": "Maleta", "language_tag": "es_US"}]}`,
+			nil,
+		},
+		{
+			"item name twice in the same language",
+			`{"item_name": [{"value": "Spinner", "language_tag": "en_US"}, {"value": "Suitcase", "language_tag": "en_US"}]}`,
+			[]string{"$.item_name maxUniqueItems"},
+		},
+		{
+			"brand through reference with id",
+			`{"brand": [{"value": 12, "language_tag": "en_US"}]}`,
+			[]string{"$.brand[0].value type"},
+		},
+		{
+			"unknown condition",
+			`{"condition_type": [{"value": "refurbished"}]}`,
+			[]string{"$.condition_type[0].value enum"},
+		},
+		{
+			"upc pattern",
+			`{"externally_assigned_product_identifier": [{"type": "upc", "value": "0123456789050"}]}`,
+			[]string{"$.externally_assigned_product_identifier[0].value pattern"},
+		},
+		{
+			"ean pattern",
+			`{"externally_assigned_product_identifier": [{"type": "ean", "value": "0123456789050"}]}`,
+			nil,
+		},
+		{
+			"identifiers of different types",
+			`{"externally_assigned_product_identifier": [{"type": "upc", "value": "012345678905"}, {"type": "ean", "value": "0012345678905"}]}`,
+			nil,
+		},
+		{"price with cents", `{"list_price": [{"currency": "USD", "value": 0.07}]}`, nil},
+		{
+			"price with fractions of cents",
+			`{"list_price": [{"currency": "USD", "value": 10.005}]}`,
+			[]string{"$.list_price[0].value multipleOf"},
+		},
+		{
+			"negative price",
+			`{"list_price": [{"currency": "USD", "value": -1}]}`,
+			[]string{"$.list_price[0].value minimum"},
+		},
+		{
+			"fractional wheels",
+			`{"number_of_wheels": [{"value": 2.5}]}`,
+			[]string{"$.number_of_wheels[0].value type"},
+		},
+		{
+			"too many wheels",
+			`{"number_of_wheels": [{"value": 10}]}`,
+			[]string{"$.number_of_wheels[0].value maximum"},
+		},
+		{
+			"batteries required without battery",
+			`{"batteries_required": [{"value": true}]}`,
+			[]string{"$.battery required"},
+		},
+		{
+			"batteries required with battery",
+			`{"batteries_required": [{"value": true}], "battery": [{"cell_composition": [{"value": "lithium_ion"}]}]}`,
+			nil,
+		},
+		{"batteries not required", `{"batteries_required": [{"value": false}]}`, nil},
+		{
+			"battery with unknown cell composition",
+			`{"battery": [{"cell_composition": [{"value": "lead_acid"}]}]}`,
+			[]string{"$.battery[0].cell_composition[0].value enum"},
+		},
+		{
+			"empty battery",
+			`{"battery": [{}]}`,
+			[]string{"$.battery[0] minProperties"},
+		},
+		{
+			"ghs regulation without ghs",
+			`{"supplier_declared_dg_hz_regulation": [{"value": "ghs"}]}`,
+			[]string{"$.ghs required"},
+		},
+		{
+			"ghs regulation with ghs",
+			`{"supplier_declared_dg_hz_regulation": [{"value": "transportation"}, {"value": "ghs"}], "ghs": [{"classification": [{"class": "explosive"}]}]}`,
+			nil,
+		},
+		{
+			"ghs without ghs regulation",
+			`{"supplier_declared_dg_hz_regulation": [{"value": "not_applicable"}], "ghs": [{"classification": [{"class": "explosive"}]}]}`,
+			[]string{"$ not"},
+		},
+		{
+			"too many regulations",
+			`{"supplier_declared_dg_hz_regulation": [{"value": "other"}, {"value": "other"}, {"value": "other"}, {"value": "other"}, {"value": "other"}, {"value": "other"}]}`,
+			[]string{"$.supplier_declared_dg_hz_regulation maxItems"},
+		},
+		{
+			"every problem is reported",
+			`{"brand": null, "condition_type": [{"value": "refurbished"}], "batteries_required": [{"value": true}]}`,
+			[]string{"$.brand required", "$.condition_type[0].value enum", "$.battery required"},
+		},
+	}
+	schema := loadLuggageSchema(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := decode(t, luggageAttributes).(map[string]any)
+			for name, value := range decode(t, tt.attributes).(map[string]any) {
+				if value == nil {
+					delete(attributes, name)
+					continue
+				}
+				attributes[name] = value
+			}
+			got := problems(schema.Validate(attributes))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateProperty(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		property string
+		value    string
+		want     []string
+	}{
+		{"valid property", `{"properties": {"a": {"type": "string"}}, "required": ["a", "b"]}`, "a", `"x"`, nil},
+		{"invalid property", `{"properties": {"a": {"type": "string"}}}`, "a", `1`, []string{"$.a type"}},
+		{"unknown property", `{"properties": {"a": {}}, "additionalProperties": false}`, "b", `1`, []string{"$.b additionalProperties"}},
+		{"unknown property allowed", `{"properties": {"a": {}}}`, "b", `1`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := problems(compile(t, tt.schema).ValidateProperty(tt.property, decode(t, tt.value)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateProperty(%s, %s) = %q, want %q", tt.property, tt.value, got, tt.want)
+			}
+		})
+	}
+
+	schema := loadLuggageSchema(t)
+	got := problems(schema.ValidateProperty("item_name", decode(t, `[{"value": "Carry-On Spinner Luggage", "language_tag": "en_US"}]`)))
+	if want := []string{"$.item_name[0].value maxLength"}; !slices.Equal(got, want) {
+		t.Errorf("ValidateProperty(item_name) = %q, want %q", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	schema := loadLuggageSchema(t)
+	tests := []struct {
+		ref   string
+		found bool
+	}{
+		{"#/$defs/marketplace_id", true},
+		{"#/properties/item_name/items/allOf/0", true},
+		{"https://schemas.amazon.com/selling-partners/definitions/product-types/schema/v1/LUGGAGE#/$defs/localized_text", true},
+		{"#", true},
+		{"#/$defs/missing", false},
+		{"#/properties/item_name/items/allOf/1", false},
+		{"https://schemas.amazon.com/selling-partners/definitions/product-types/schema/v1/SHOES#/$defs/localized_text", false},
+		{"localized_text", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if _, found := schema.Resolve(tt.ref); found != tt.found {
+				t.Errorf("Resolve(%s) found = %v, want %v", tt.ref, found, tt.found)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	if _, err := Compile([]byte(`{"type": `)); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestErrorPath(t *testing.T) {
+	tests := []struct {
+		path path
+		want string
+	}{
+		{path{}, "$"},
+		{path{"item_name", 0, "value"}, "$.item_name[0].value"},
+		{path{"b c"}, `$["b c"]`},
+		{path{"1a"}, `$["1a"]`},
+	}
+	for _, tt := range tests {
+		if got := tt.path.String(); got != tt.want {
+			t.Errorf("path%v = %s, want %s", []any(tt.path), got, tt.want)
+		}
+	}
+}
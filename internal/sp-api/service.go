@@ -9,7 +9,9 @@ import (
 
 	"github.com/caner-cetin/halycon/internal"
 	"github.com/caner-cetin/halycon/internal/amazon/catalog"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_eligibility"
 	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound"
+	"github.com/caner-cetin/halycon/internal/amazon/fba_inbound_v0"
 	"github.com/caner-cetin/halycon/internal/amazon/fba_inventory"
 	"github.com/caner-cetin/halycon/internal/amazon/feeds"
 	"github.com/caner-cetin/halycon/internal/amazon/listings"
@@ -23,6 +25,8 @@ const (
 	CatalogServiceName                = "services.catalog"
 	ListingsServiceName               = "services.listings"
 	FBAInboundServiceName             = "services.fba.inbound"
+	FBAInboundV0ServiceName           = "services.fba.inbound.v0"
+	FBAEligibilityServiceName         = "services.fba.eligibility"
 	FBAInventoryServiceName           = "services.fba.inventory"
 	ProductTypeDefinitionsServiceName = "services.listings.product_type.definitions"
 	FeedsServiceName                  = "services.feeds"
@@ -56,6 +60,14 @@ func (a *Client) GetFBAInboundService() *fba_inbound.ClientWithResponses {
 	return a.services[FBAInboundServiceName].(*fba_inbound.ClientWithResponses)
 }
 
+func (a *Client) GetFBAInboundV0Service() *fba_inbound_v0.ClientWithResponses {
+	return a.services[FBAInboundV0ServiceName].(*fba_inbound_v0.ClientWithResponses)
+}
+
+func (a *Client) GetFBAEligibilityService() *fba_eligibility.ClientWithResponses {
+	return a.services[FBAEligibilityServiceName].(*fba_eligibility.ClientWithResponses)
+}
+
 func (a *Client) GetFBAInventoryService() *fba_inventory.ClientWithResponses {
 	return a.services[FBAInventoryServiceName].(*fba_inventory.ClientWithResponses)
 }
@@ -100,6 +112,141 @@ func (a *Client) GetInboundOperationStatus(ctx context.Context, operation_id str
 	return recordError(a.GetFBAInboundService().GetInboundOperationStatusWithResponse(ctx, operation_id, a.WithAuth(), a.WithRateLimit(GetInboundOperationStatusRLKey))) //nolint:typecheck
 }
 
+func (a *Client) GetInboundPlan(ctx context.Context, inboundPlanId string) (*fba_inbound.GetInboundPlanResp, error) {
+	return recordError(a.GetFBAInboundService().GetInboundPlanWithResponse(ctx, inboundPlanId, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListInboundPlans(ctx context.Context, params *fba_inbound.ListInboundPlansParams) (*fba_inbound.ListInboundPlansResp, error) {
+	return recordError(a.GetFBAInboundService().ListInboundPlansWithResponse(ctx, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListInboundPlanItems(ctx context.Context, inboundPlanId string, params *fba_inbound.ListInboundPlanItemsParams) (*fba_inbound.ListInboundPlanItemsResp, error) {
+	return recordError(a.GetFBAInboundService().ListInboundPlanItemsWithResponse(ctx, inboundPlanId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListInboundPlanBoxes(ctx context.Context, inboundPlanId string, params *fba_inbound.ListInboundPlanBoxesParams) (*fba_inbound.ListInboundPlanBoxesResp, error) {
+	return recordError(a.GetFBAInboundService().ListInboundPlanBoxesWithResponse(ctx, inboundPlanId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GeneratePackingOptions(ctx context.Context, inboundPlanId string) (*fba_inbound.GeneratePackingOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().GeneratePackingOptionsWithResponse(ctx, inboundPlanId, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListPackingOptions(ctx context.Context, inboundPlanId string, params *fba_inbound.ListPackingOptionsParams) (*fba_inbound.ListPackingOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().ListPackingOptionsWithResponse(ctx, inboundPlanId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ConfirmPackingOption(ctx context.Context, inboundPlanId string, packingOptionId string) (*fba_inbound.ConfirmPackingOptionResp, error) {
+	return recordError(a.GetFBAInboundService().ConfirmPackingOptionWithResponse(ctx, inboundPlanId, packingOptionId, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListPackingGroupItems(ctx context.Context, inboundPlanId string, packingGroupId string, params *fba_inbound.ListPackingGroupItemsParams) (*fba_inbound.ListPackingGroupItemsResp, error) {
+	return recordError(a.GetFBAInboundService().ListPackingGroupItemsWithResponse(ctx, inboundPlanId, packingGroupId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) SetPackingInformation(ctx context.Context, inboundPlanId string, body fba_inbound.SetPackingInformationJSONRequestBody) (*fba_inbound.SetPackingInformationResp, error) {
+	return recordError(a.GetFBAInboundService().SetPackingInformationWithResponse(ctx, inboundPlanId, body, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GeneratePlacementOptions(ctx context.Context, inboundPlanId string, body fba_inbound.GeneratePlacementOptionsJSONRequestBody) (*fba_inbound.GeneratePlacementOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().GeneratePlacementOptionsWithResponse(ctx, inboundPlanId, body, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListPlacementOptions(ctx context.Context, inboundPlanId string, params *fba_inbound.ListPlacementOptionsParams) (*fba_inbound.ListPlacementOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().ListPlacementOptionsWithResponse(ctx, inboundPlanId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ConfirmPlacementOption(ctx context.Context, inboundPlanId string, placementOptionId string) (*fba_inbound.ConfirmPlacementOptionResp, error) {
+	return recordError(a.GetFBAInboundService().ConfirmPlacementOptionWithResponse(ctx, inboundPlanId, placementOptionId, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GetInboundShipment(ctx context.Context, inboundPlanId string, shipmentId string) (*fba_inbound.GetShipmentResp, error) {
+	return recordError(a.GetFBAInboundService().GetShipmentWithResponse(ctx, inboundPlanId, shipmentId, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GenerateTransportationOptions(ctx context.Context, inboundPlanId string, body fba_inbound.GenerateTransportationOptionsJSONRequestBody) (*fba_inbound.GenerateTransportationOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().GenerateTransportationOptionsWithResponse(ctx, inboundPlanId, body, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListTransportationOptions(ctx context.Context, inboundPlanId string, params *fba_inbound.ListTransportationOptionsParams) (*fba_inbound.ListTransportationOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().ListTransportationOptionsWithResponse(ctx, inboundPlanId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ConfirmTransportationOptions(ctx context.Context, inboundPlanId string, body fba_inbound.ConfirmTransportationOptionsJSONRequestBody) (*fba_inbound.ConfirmTransportationOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().ConfirmTransportationOptionsWithResponse(ctx, inboundPlanId, body, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GenerateDeliveryWindowOptions(ctx context.Context, inboundPlanId string, shipmentId string) (*fba_inbound.GenerateDeliveryWindowOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().GenerateDeliveryWindowOptionsWithResponse(ctx, inboundPlanId, shipmentId, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListDeliveryWindowOptions(ctx context.Context, inboundPlanId string, shipmentId string, params *fba_inbound.ListDeliveryWindowOptionsParams) (*fba_inbound.ListDeliveryWindowOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().ListDeliveryWindowOptionsWithResponse(ctx, inboundPlanId, shipmentId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ConfirmDeliveryWindowOptions(ctx context.Context, inboundPlanId string, shipmentId string, deliveryWindowOptionId string) (*fba_inbound.ConfirmDeliveryWindowOptionsResp, error) {
+	return recordError(a.GetFBAInboundService().ConfirmDeliveryWindowOptionsWithResponse(ctx, inboundPlanId, shipmentId, deliveryWindowOptionId, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListShipmentItems(ctx context.Context, inboundPlanId string, shipmentId string, params *fba_inbound.ListShipmentItemsParams) (*fba_inbound.ListShipmentItemsResp, error) {
+	return recordError(a.GetFBAInboundService().ListShipmentItemsWithResponse(ctx, inboundPlanId, shipmentId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) CancelInboundPlan(ctx context.Context, inboundPlanId string) (*fba_inbound.CancelInboundPlanResp, error) {
+	return recordError(a.GetFBAInboundService().CancelInboundPlanWithResponse(ctx, inboundPlanId, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) UpdateInboundPlanName(ctx context.Context, inboundPlanId string, body fba_inbound.UpdateInboundPlanNameJSONRequestBody) (*fba_inbound.UpdateInboundPlanNameResp, error) {
+	return recordError(a.GetFBAInboundService().UpdateInboundPlanNameWithResponse(ctx, inboundPlanId, body, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) UpdateShipmentSourceAddress(ctx context.Context, inboundPlanId string, shipmentId string, body fba_inbound.UpdateShipmentSourceAddressJSONRequestBody) (*fba_inbound.UpdateShipmentSourceAddressResp, error) {
+	return recordError(a.GetFBAInboundService().UpdateShipmentSourceAddressWithResponse(ctx, inboundPlanId, shipmentId, body, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GenerateShipmentContentUpdatePreviews(ctx context.Context, inboundPlanId string, shipmentId string, body fba_inbound.GenerateShipmentContentUpdatePreviewsJSONRequestBody) (*fba_inbound.GenerateShipmentContentUpdatePreviewsResp, error) {
+	return recordError(a.GetFBAInboundService().GenerateShipmentContentUpdatePreviewsWithResponse(ctx, inboundPlanId, shipmentId, body, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListShipmentContentUpdatePreviews(ctx context.Context, inboundPlanId string, shipmentId string, params *fba_inbound.ListShipmentContentUpdatePreviewsParams) (*fba_inbound.ListShipmentContentUpdatePreviewsResp, error) {
+	return recordError(a.GetFBAInboundService().ListShipmentContentUpdatePreviewsWithResponse(ctx, inboundPlanId, shipmentId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ConfirmShipmentContentUpdatePreview(ctx context.Context, inboundPlanId string, shipmentId string, contentUpdatePreviewId string) (*fba_inbound.ConfirmShipmentContentUpdatePreviewResp, error) {
+	return recordError(a.GetFBAInboundService().ConfirmShipmentContentUpdatePreviewWithResponse(ctx, inboundPlanId, shipmentId, contentUpdatePreviewId, a.WithAuth(), a.WithRateLimit(InboundWriteRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListShipmentBoxes(ctx context.Context, inboundPlanId string, shipmentId string, params *fba_inbound.ListShipmentBoxesParams) (*fba_inbound.ListShipmentBoxesResp, error) {
+	return recordError(a.GetFBAInboundService().ListShipmentBoxesWithResponse(ctx, inboundPlanId, shipmentId, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GetDeliveryChallanDocument(ctx context.Context, inboundPlanId string, shipmentId string) (*fba_inbound.GetDeliveryChallanDocumentResp, error) {
+	return recordError(a.GetFBAInboundService().GetDeliveryChallanDocumentWithResponse(ctx, inboundPlanId, shipmentId, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
+// GetLabels uses the v0 API, shipmentId is the shipment confirmation ID of a v2024-03-20 shipment.
+func (a *Client) GetLabels(ctx context.Context, shipmentId string, params *fba_inbound_v0.GetLabelsParams) (*fba_inbound_v0.GetLabelsResp, error) {
+	return recordError(a.GetFBAInboundV0Service().GetLabelsWithResponse(ctx, shipmentId, params, a.WithAuth(), a.WithRateLimit(GetLabelsRLKey))) //nolint:typecheck
+}
+
+// GetShipmentItemsByShipmentId uses the v0 API for received quantities, shipmentId is the shipment confirmation ID of a v2024-03-20 shipment.
+func (a *Client) GetShipmentItemsByShipmentId(ctx context.Context, shipmentId string, params *fba_inbound_v0.GetShipmentItemsByShipmentIdParams) (*fba_inbound_v0.GetShipmentItemsByShipmentIdResp, error) {
+	return recordError(a.GetFBAInboundV0Service().GetShipmentItemsByShipmentIdWithResponse(ctx, shipmentId, params, a.WithAuth(), a.WithRateLimit(GetShipmentItemsRLKey))) //nolint:typecheck
+}
+
+// GetShipmentItems uses the v0 API, it is used with NEXT_TOKEN queries for the pages after the first one of GetShipmentItemsByShipmentId.
+func (a *Client) GetShipmentItems(ctx context.Context, params *fba_inbound_v0.GetShipmentItemsParams) (*fba_inbound_v0.GetShipmentItemsResp, error) {
+	return recordError(a.GetFBAInboundV0Service().GetShipmentItemsWithResponse(ctx, params, a.WithAuth(), a.WithRateLimit(GetShipmentItemsRLKey))) //nolint:typecheck
+}
+
+func (a *Client) GetItemEligibilityPreview(ctx context.Context, params *fba_eligibility.GetItemEligibilityPreviewParams) (*fba_eligibility.GetItemEligibilityPreviewResp, error) {
+	return recordError(a.GetFBAEligibilityService().GetItemEligibilityPreviewWithResponse(ctx, params, a.WithAuth(), a.WithRateLimit(GetItemEligibilityPreviewRLKey))) //nolint:typecheck
+}
+
+func (a *Client) ListPrepDetails(ctx context.Context, params *fba_inbound.ListPrepDetailsParams) (*fba_inbound.ListPrepDetailsResp, error) {
+	return recordError(a.GetFBAInboundService().ListPrepDetailsWithResponse(ctx, params, a.WithAuth(), a.WithRateLimit(InboundReadRLKey))) //nolint:typecheck
+}
+
 func (a *Client) SearchProductTypeDefinitions(ctx context.Context, params *product_type_definitions.SearchDefinitionsProductTypesParams) (*product_type_definitions.SearchDefinitionsProductTypesResp, error) {
 	return recordError(a.GetProductTypeDefinitionsService().SearchDefinitionsProductTypesWithResponse(ctx, params, a.WithAuth(), a.WithRateLimit(SearchProductTypeDefinitionsRLKey))) //nolint:typecheck
 }
@@ -142,6 +289,11 @@ const (
 	FBAInventorySummariesRLKey        = "fba.inventorySummaries"
 	CreateInboundPlanRLKey            = "fba.createInboundPlan"
 	GetInboundOperationStatusRLKey    = "fba.getInboundOperationStatus"
+	InboundReadRLKey                  = "fba.inboundRead"
+	InboundWriteRLKey                 = "fba.inboundWrite"
+	GetLabelsRLKey                    = "fba.getLabels"
+	GetItemEligibilityPreviewRLKey    = "fba.getItemEligibilityPreview"
+	GetShipmentItemsRLKey             = "fba.getShipmentItems"
 	SearchProductTypeDefinitionsRLKey = "listings.search_product_type_definitions"
 	GetProductTypeDefinitionRLKey     = "listings.get_product_type_definitions"
 	GetFeedsRLKey                     = "feeds.getFeeds"
@@ -161,6 +313,11 @@ func (a *Client) SetRateLimits() {
 		FBAInventorySummariesRLKey:        rate.NewLimiter(rate.Limit(2), 2),
 		CreateInboundPlanRLKey:            rate.NewLimiter(rate.Limit(2), 2),
 		GetInboundOperationStatusRLKey:    rate.NewLimiter(rate.Limit(2), 6),
+		InboundReadRLKey:                  rate.NewLimiter(rate.Limit(2), 6),
+		InboundWriteRLKey:                 rate.NewLimiter(rate.Limit(2), 2),
+		GetLabelsRLKey:                    rate.NewLimiter(rate.Limit(2), 30),
+		GetItemEligibilityPreviewRLKey:    rate.NewLimiter(rate.Limit(1), 1),
+		GetShipmentItemsRLKey:             rate.NewLimiter(rate.Limit(2), 30),
 		SearchProductTypeDefinitionsRLKey: rate.NewLimiter(rate.Limit(5), 10),
 		GetProductTypeDefinitionRLKey:     rate.NewLimiter(rate.Limit(5), 10),
 		CreateListingRLKey:                rate.NewLimiter(rate.Limit(5), 10),
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -208,14 +209,21 @@ func CloseStmt(s *sql.Stmt) {
 	}
 }
 
+func CloseDB(d *sql.DB) {
+	if cerr := d.Close(); cerr != nil {
+		log.Error().Err(fmt.Errorf("error closing db: %w", cerr)).Send()
+	}
+}
+
 func CloseRows(r *sql.Rows) {
 	if cerr := r.Close(); cerr != nil {
 		log.Error().Err(fmt.Errorf("error closing rows: %w", cerr)).Send()
 	}
 }
 
+// Rollback rolls back the transaction, meant to be deferred, committed transactions are left as is.
 func Rollback(t *sql.Tx) {
-	if cerr := t.Rollback(); cerr != nil {
+	if cerr := t.Rollback(); cerr != nil && !errors.Is(cerr, sql.ErrTxDone) {
 		log.Error().Err(fmt.Errorf("error rolling back stmt: %w", cerr)).Send()
 	}
 }
@@ -229,3 +237,19 @@ func Rollback(t *sql.Tx) {
 func Ptr[T any](v T) *T {
 	return &v
 }
+
+// NullString converts an optional string into [sql.NullString], nil pointers are stored as NULL.
+func NullString(v *string) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *v, Valid: true}
+}
+
+// NullInt64 converts an optional int into [sql.NullInt64], nil pointers are stored as NULL.
+func NullInt64(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}